@@ -4,42 +4,54 @@ import (
 	"archive/tar"
 	"fmt"
 	"io"
-	"io/ioutil"
-	"os"
-	"os/exec"
-	"path"
-	"strings"
 
 	"crypto/sha256"
 	"time"
 
 	"sync"
 
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bytesize"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/pkg/errors"
 )
 
-const timestampFormat = "2006/01/02 15:04:05 MST"
+const (
+	timestampFormat  = "2006/01/02 15:04:05 MST"
+	metadataFilename = "metadata"
+	manifestFilename = "manifest.yml"
+	logsDirectory    = "logs"
+)
 
 type BackupDirectory struct {
 	orchestrator.Logger
-	baseDirName string
+	storage          Storage
+	kind             string
+	compression      string
+	backupSelection  orchestrator.Selection
+	restoreSelection orchestrator.Selection
+	encryptionSecret []byte
+	encryptionKey    *encryptionKey
+	encryptionLoaded bool
+	artifactSizes    map[string]int64
 	sync.Mutex
 }
 
+// GetArtifactSize is the size of the artifact as it is streamed back to an instance, before any
+// compression or encryption. It is empty for artifacts whose stored size differs from that, if
+// the backup did not record it.
 func (backupDirectory *BackupDirectory) GetArtifactSize(artifactIdentifier orchestrator.ArtifactIdentifier) (string, error) {
-	filename := backupDirectory.instanceFilename(artifactIdentifier)
-
-	cmd := exec.Command("du", "-sh", filename)
-
-	output, err := cmd.Output()
-
-	if err != nil {
-		return "", err
+	metadata, err := readMetadata(backupDirectory.storage, metadataFilename)
+	if err == nil {
+		artifact, found := metadata.findArtifactMetadata(artifactIdentifier)
+		if found && artifact.Size > 0 {
+			return bytesize.Format(artifact.Size), nil
+		}
+		if (found && artifact.Compression != "") || metadata.Encryption != nil {
+			return "", nil
+		}
 	}
 
-	size := strings.Fields(string(output))[0]
-	return size, nil
+	return backupDirectory.storage.Size(fileName(artifactIdentifier))
 }
 
 func (backupDirectory *BackupDirectory) logAndReturn(err error, message string, args ...interface{}) error {
@@ -53,12 +65,16 @@ func (backupDirectory *BackupDirectory) DeploymentMatches(deployment string, ins
 	if err != nil {
 		return false, backupDirectory.logAndReturn(err, "Error checking metadata file")
 	}
-	meta, err := readMetadata(backupDirectory.metadataFilename())
+	meta, err := readMetadata(backupDirectory.storage, metadataFilename)
 	if err != nil {
 		return false, backupDirectory.logAndReturn(err, "Error reading metadata file")
 	}
 
 	for _, inst := range meta.MetadataForEachInstance {
+		if !backupDirectory.instanceIsSelected(inst) {
+			continue
+		}
+
 		present := backupDirectory.backupInstanceIsPresent(inst, instances)
 		if present != true {
 			backupDirectory.Debug("bbr", "Instance %v/%v not found in %v", inst.Name, inst.Index, instances)
@@ -69,53 +85,149 @@ func (backupDirectory *BackupDirectory) DeploymentMatches(deployment string, ins
 	return true, nil
 }
 
+// OriginalInstanceID is the ID of the instance whose artifacts were backed up with the given
+// name and index, or empty for backups which did not record it.
+func (backupDirectory *BackupDirectory) OriginalInstanceID(name, index string) (string, error) {
+	meta, err := readMetadata(backupDirectory.storage, metadataFilename)
+	if err != nil {
+		return "", backupDirectory.logAndReturn(err, "Error reading metadata file")
+	}
+
+	for _, inst := range meta.MetadataForEachInstance {
+		if inst.Name == name && inst.Index == index {
+			return inst.ID, nil
+		}
+	}
+	return "", nil
+}
+
 func (backupDirectory *BackupDirectory) CreateArtifact(artifactIdentifier orchestrator.ArtifactIdentifier) (io.WriteCloser, error) {
 	backupDirectory.Debug("bbr", "Trying to create file %s", fileName(artifactIdentifier))
 
-	file, err := os.Create(path.Join(backupDirectory.baseDirName, fileName(artifactIdentifier)))
+	file, err := backupDirectory.createEncrypted(fileName(artifactIdentifier))
 	if err != nil {
 		return nil, backupDirectory.logAndReturn(err, "Error creating file %s", fileName(artifactIdentifier))
 
 	}
 
-	return file, err
+	writer, err := compressingWriter(backupDirectory.compression, file)
+	if err != nil {
+		file.Close()
+		return nil, backupDirectory.logAndReturn(err, "Error creating file %s", fileName(artifactIdentifier))
+	}
+
+	return &countingWriteCloser{WriteCloser: writer, closed: func(written int64) {
+		backupDirectory.Lock()
+		defer backupDirectory.Unlock()
+
+		if backupDirectory.artifactSizes == nil {
+			backupDirectory.artifactSizes = map[string]int64{}
+		}
+		backupDirectory.artifactSizes[fileName(artifactIdentifier)] = written
+	}}, nil
+}
+
+// countingWriteCloser counts the bytes written to an artifact before they are compressed or
+// encrypted, so the size can be recorded with its checksum
+type countingWriteCloser struct {
+	io.WriteCloser
+	written int64
+	closed  func(written int64)
+}
+
+func (w *countingWriteCloser) Write(data []byte) (int, error) {
+	written, err := w.WriteCloser.Write(data)
+	w.written += int64(written)
+	return written, err
+}
+
+func (w *countingWriteCloser) Close() error {
+	if err := w.WriteCloser.Close(); err != nil {
+		return err
+	}
+	w.closed(w.written)
+	return nil
+}
+
+func (w *countingWriteCloser) CloseWithError(err error) error {
+	return closeWithError(w.WriteCloser, err)
+}
+
+func (backupDirectory *BackupDirectory) createEncrypted(name string) (io.WriteCloser, error) {
+	key, err := backupDirectory.cipherKey()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := backupDirectory.storage.Create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := encryptingWriter(key, file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return writer, nil
 }
 
 func (backupDirectory *BackupDirectory) ReadArtifact(artifactIdentifier orchestrator.ArtifactIdentifier) (io.ReadCloser, error) {
 	filename := backupDirectory.instanceFilename(artifactIdentifier)
 	backupDirectory.Debug("bbr", "Trying to open %s", filename)
-	file, err := os.Open(filename)
+	file, err := backupDirectory.storage.Open(fileName(artifactIdentifier))
 	if err != nil {
 		backupDirectory.Debug("bbr", "Error reading artifact file %s", filename)
 		return nil, backupDirectory.logAndReturn(err, "Error reading artifact file %s", filename)
 	}
 
-	return file, nil
+	key, err := backupDirectory.cipherKey()
+	if err != nil {
+		file.Close()
+		return nil, backupDirectory.logAndReturn(err, "Error reading artifact file %s", filename)
+	}
+
+	decrypted, err := decryptingReader(key, file)
+	if err != nil {
+		file.Close()
+		return nil, backupDirectory.logAndReturn(err, "Error reading artifact file %s", filename)
+	}
+
+	reader, err := decompressingReader(backupDirectory.artifactCompression(artifactIdentifier), decrypted)
+	if err != nil {
+		decrypted.Close()
+		return nil, backupDirectory.logAndReturn(err, "Error reading artifact file %s", filename)
+	}
+
+	return reader, nil
+}
+
+// artifactCompression prefers the codec recorded in the metadata, falling back to the codec
+// this backup is being written with for artifacts which have not been checksummed yet
+func (backupDirectory *BackupDirectory) artifactCompression(artifactIdentifier orchestrator.ArtifactIdentifier) string {
+	backupDirectory.Lock()
+	defer backupDirectory.Unlock()
+
+	metadata, err := readMetadata(backupDirectory.storage, metadataFilename)
+	if err != nil {
+		return backupDirectory.compression
+	}
+
+	if artifact, found := metadata.findArtifactMetadata(artifactIdentifier); found {
+		return artifact.Compression
+	}
+	return backupDirectory.compression
 }
 
 func (backupDirectory *BackupDirectory) FetchChecksum(artifactIdentifier orchestrator.ArtifactIdentifier) (orchestrator.BackupChecksum, error) {
-	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	metadata, err := readMetadata(backupDirectory.storage, metadataFilename)
 
 	if err != nil {
-		return nil, backupDirectory.logAndReturn(err, "Error reading metadata from %s", backupDirectory.metadataFilename())
+		return nil, backupDirectory.logAndReturn(err, "Error reading metadata from %s", backupDirectory.metadataLocation())
 	}
 
-	if artifactIdentifier.HasCustomName() {
-		for _, customArtifactInMetadata := range metadata.MetadataForEachArtifact {
-			if customArtifactInMetadata.Name == artifactIdentifier.Name() {
-				return customArtifactInMetadata.Checksum, nil
-			}
-		}
-	} else {
-		for _, instanceInMetadata := range metadata.MetadataForEachInstance {
-			if instanceInMetadata.Index == artifactIdentifier.InstanceIndex() && instanceInMetadata.Name == artifactIdentifier.InstanceName() {
-				for _, artifact := range instanceInMetadata.Artifacts {
-					if artifact.Name == artifactIdentifier.Name() {
-						return artifact.Checksum, nil
-					}
-				}
-			}
-		}
+	if artifact, found := metadata.findArtifactMetadata(artifactIdentifier); found {
+		return artifact.Checksum, nil
 	}
 
 	backupDirectory.Warn("bbr", "Checksum for %s not found in artifact", logName(artifactIdentifier))
@@ -169,25 +281,52 @@ func (backupDirectory *BackupDirectory) AddChecksum(artifactIdentifier orchestra
 		return backupDirectory.logAndReturn(err, "unable to load metadata")
 	}
 
-	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	metadata, err := readMetadata(backupDirectory.storage, metadataFilename)
 	if err != nil {
-		return backupDirectory.logAndReturn(err, "Error reading metadata from %s", backupDirectory.metadataFilename())
+		return backupDirectory.logAndReturn(err, "unable to load metadata")
 	}
 
 	if artifactIdentifier.HasCustomName() {
 		metadata.MetadataForEachArtifact = append(metadata.MetadataForEachArtifact, artifactMetadata{
-			Name:     artifactIdentifier.Name(),
-			Checksum: shasum,
+			Name:        artifactIdentifier.Name(),
+			Checksum:    shasum,
+			Compression: backupDirectory.recordedCompression(),
+			Size:        backupDirectory.artifactSizes[fileName(artifactIdentifier)],
 		})
 	} else {
 		instanceMetadata := metadata.findOrCreateInstanceMetadata(artifactIdentifier.InstanceName(), artifactIdentifier.InstanceIndex())
+		if artifactIdentifier.InstanceID() != "" {
+			instanceMetadata.ID = artifactIdentifier.InstanceID()
+		}
 		instanceMetadata.Artifacts = append(instanceMetadata.Artifacts, artifactMetadata{
-			Name:     artifactIdentifier.Name(),
-			Checksum: shasum,
+			Name:        artifactIdentifier.Name(),
+			Checksum:    shasum,
+			Compression: backupDirectory.recordedCompression(),
+			Size:        backupDirectory.artifactSizes[fileName(artifactIdentifier)],
 		})
 	}
 
-	return metadata.save(backupDirectory.metadataFilename())
+	return metadata.save(backupDirectory.storage, metadataFilename)
+}
+
+func (backupDirectory *BackupDirectory) AddVerifyResults(results []orchestrator.VerifyResult) error {
+	defer backupDirectory.Unlock()
+	backupDirectory.Lock()
+
+	metadata, err := readMetadata(backupDirectory.storage, metadataFilename)
+	if err != nil {
+		return backupDirectory.logAndReturn(err, "unable to load metadata")
+	}
+
+	for _, result := range results {
+		verify := verifyMetadata{Instance: result.InstanceIdentifier, Job: result.JobName, Passed: result.Error == nil}
+		if result.Error != nil {
+			verify.Error = result.Error.Error()
+		}
+		metadata.PostBackupVerify = append(metadata.PostBackupVerify, verify)
+	}
+
+	return metadata.save(backupDirectory.storage, metadataFilename)
 }
 
 func (backupDirectory *BackupDirectory) CreateMetadataFileWithStartTime(startTime time.Time) error {
@@ -202,14 +341,22 @@ func (backupDirectory *BackupDirectory) CreateMetadataFileWithStartTime(startTim
 		MetadataForBackupActivity: backupActivityMetadata{
 			StartTime: startTime.Format(timestampFormat),
 		},
+		Kind:        backupDirectory.kind,
+		Compression: backupDirectory.recordedCompression(),
+	}
+	if backupDirectory.encryptionKey != nil {
+		metadata.Encryption = backupDirectory.encryptionKey.metadata()
+	}
+	if !backupDirectory.backupSelection.IsEmpty() {
+		metadata.Selection = newSelectionMetadata(backupDirectory.backupSelection)
 	}
-	metadata.save(backupDirectory.metadataFilename())
+	metadata.save(backupDirectory.storage, metadataFilename)
 
 	return nil
 }
 
 func (backupDirectory *BackupDirectory) AddFinishTime(finishTime time.Time) error {
-	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	metadata, err := readMetadata(backupDirectory.storage, metadataFilename)
 	if err != nil {
 		message := "unable to load metadata"
 		backupDirectory.Debug("bbr", "%s: %v", message, nil)
@@ -217,19 +364,65 @@ func (backupDirectory *BackupDirectory) AddFinishTime(finishTime time.Time) erro
 	}
 
 	metadata.MetadataForBackupActivity.FinishTime = finishTime.Format(timestampFormat)
-	metadata.save(backupDirectory.metadataFilename())
+	metadata.save(backupDirectory.storage, metadataFilename)
 
 	return nil
 }
 
+// AddLog saves the output of a script as logs/<name>.log, encrypted like the artifacts when
+// the backup is encrypted, and lists it in the metadata
+func (backupDirectory *BackupDirectory) AddLog(name string, contents []byte) error {
+	filename := logsDirectory + "/" + name + ".log"
+
+	if err := backupDirectory.storage.CreateDirectory(logsDirectory); err != nil {
+		return backupDirectory.logAndReturn(err, "Error creating directory %s", logsDirectory)
+	}
+
+	writer, err := backupDirectory.createEncrypted(filename)
+	if err != nil {
+		return backupDirectory.logAndReturn(err, "Error creating file %s", filename)
+	}
+	if _, err := writer.Write(contents); err != nil {
+		writer.Close()
+		return backupDirectory.logAndReturn(err, "Error writing file %s", filename)
+	}
+	if err := writer.Close(); err != nil {
+		return backupDirectory.logAndReturn(err, "Error writing file %s", filename)
+	}
+
+	backupDirectory.Lock()
+	defer backupDirectory.Unlock()
+
+	metadata, err := readMetadata(backupDirectory.storage, metadataFilename)
+	if err != nil {
+		return backupDirectory.logAndReturn(err, "unable to load metadata")
+	}
+	metadata.Logs = append(metadata.Logs, filename)
+
+	return metadata.save(backupDirectory.storage, metadataFilename)
+}
+
 func (backupDirectory *BackupDirectory) SaveManifest(manifest string) error {
-	return errors.Wrap(ioutil.WriteFile(backupDirectory.manifestFilename(), []byte(manifest), 0666), "failed to save manifest")
+	writer, err := backupDirectory.createEncrypted(manifestFilename)
+	if err != nil {
+		return errors.Wrap(err, "failed to save manifest")
+	}
+
+	if _, err := writer.Write([]byte(manifest)); err != nil {
+		writer.Close()
+		return errors.Wrap(err, "failed to save manifest")
+	}
+	return errors.Wrap(writer.Close(), "failed to save manifest")
 }
 
 func (backupDirectory *BackupDirectory) Valid() (bool, error) {
-	meta, err := readMetadata(backupDirectory.metadataFilename())
+	meta, err := readMetadata(backupDirectory.storage, metadataFilename)
 	if err != nil {
-		return false, backupDirectory.logAndReturn(err, "Error reading metadata from %s", backupDirectory.metadataFilename())
+		return false, backupDirectory.logAndReturn(err, "Error reading metadata from %s", backupDirectory.metadataLocation())
+	}
+
+	if _, err := backupDirectory.cipherKey(); err != nil {
+		return false, backupDirectory.logAndReturn(err, "Error loading encryption key")
 	}
 
 	for _, artifact := range meta.MetadataForEachArtifact {
@@ -259,6 +452,25 @@ func (backupDirectory *BackupDirectory) Valid() (bool, error) {
 	return true, nil
 }
 
+// instanceIsSelected is true when a selective restore will restore any of the instance's
+// artifacts, so the instance has to be in the deployment.
+func (backupDirectory *BackupDirectory) instanceIsSelected(backupInstance *instanceMetadata) bool {
+	selection := backupDirectory.restoreSelection
+	if selection.IsEmpty() {
+		return true
+	}
+	if !selection.IncludesInstanceGroup(backupInstance.Name) {
+		return false
+	}
+
+	for _, artifact := range backupInstance.Artifacts {
+		if selection.IncludesJob(artifact.Name) && selection.IncludesArtifact(artifact.Name) {
+			return true
+		}
+	}
+	return false
+}
+
 func (backupDirectory *BackupDirectory) backupInstanceIsPresent(backupInstance *instanceMetadata, instances []orchestrator.Instance) bool {
 	for _, inst := range instances {
 		if inst.Index() == backupInstance.Index && inst.Name() == backupInstance.Name {
@@ -269,23 +481,60 @@ func (backupDirectory *BackupDirectory) backupInstanceIsPresent(backupInstance *
 }
 
 func (backupDirectory *BackupDirectory) instanceFilename(artifactIdentifier orchestrator.ArtifactIdentifier) string {
-	return path.Join(backupDirectory.baseDirName, fileName(artifactIdentifier))
+	return backupDirectory.storage.Location() + "/" + fileName(artifactIdentifier)
+}
+
+func (backupDirectory *BackupDirectory) metadataLocation() string {
+	return backupDirectory.storage.Location() + "/" + metadataFilename
 }
 
-func (backupDirectory *BackupDirectory) metadataFilename() string {
-	return path.Join(backupDirectory.baseDirName, "metadata")
+// cipherKey derives the key recorded in the metadata of an existing backup from the secret
+// given on the command line, and checks it against the recorded fingerprint
+func (backupDirectory *BackupDirectory) cipherKey() (*encryptionKey, error) {
+	backupDirectory.Lock()
+	defer backupDirectory.Unlock()
+
+	if backupDirectory.encryptionLoaded {
+		return backupDirectory.encryptionKey, nil
+	}
+
+	metadata, err := readMetadata(backupDirectory.storage, metadataFilename)
+	if err != nil {
+		if len(backupDirectory.encryptionSecret) == 0 {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if metadata.Encryption == nil {
+		if len(backupDirectory.encryptionSecret) > 0 {
+			backupDirectory.Warn("bbr", "Backup %s is not encrypted, ignoring the encryption key", backupDirectory.storage.Location())
+		}
+	} else {
+		key, err := loadEncryptionKey(backupDirectory.encryptionSecret, metadata.Encryption)
+		if err != nil {
+			return nil, err
+		}
+		backupDirectory.encryptionKey = key
+	}
+
+	backupDirectory.encryptionLoaded = true
+	return backupDirectory.encryptionKey, nil
 }
 
-func (backupDirectory *BackupDirectory) manifestFilename() string {
-	return path.Join(backupDirectory.baseDirName, "manifest.yml")
+func (backupDirectory *BackupDirectory) recordedCompression() string {
+	if backupDirectory.compression == CompressionNone {
+		return ""
+	}
+	return backupDirectory.compression
 }
 
 func (backupDirectory *BackupDirectory) metadataExistsAndIsReadable() (bool, error) {
-	_, err := os.Stat(backupDirectory.metadataFilename())
+	exists, err := backupDirectory.storage.Exists(metadataFilename)
 	if err != nil {
 		return false, backupDirectory.logAndReturn(err, "Error checking metadata exists and is readable")
 	}
-	return true, nil
+	return exists, nil
 }
 
 func fileName(artifactIdentifier orchestrator.ArtifactIdentifier) string {
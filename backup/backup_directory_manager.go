@@ -6,17 +6,59 @@ import (
 	"fmt"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/s3"
 	"github.com/pkg/errors"
 )
 
-type BackupDirectoryManager struct{}
+// Kinds of backup, recorded in the metadata so that backups of deployments can be told apart
+// from those of directors
+const (
+	KindDeployment = "deployment"
+	KindDirector   = "director"
+)
+
+// BackupDirectoryManager creates and opens backups. Kind and BackupSelection are recorded in
+// the backups it creates, and RestoreSelection limits what is restored from those it opens.
+type BackupDirectoryManager struct {
+	Kind             string
+	Compression      string
+	EncryptionKey    []byte
+	BackupSelection  orchestrator.Selection
+	RestoreSelection orchestrator.Selection
+}
 
-func (BackupDirectoryManager) Create(path, directoryName string, logger orchestrator.Logger) (orchestrator.Backup, error) {
+func (manager BackupDirectoryManager) Create(path, directoryName string, logger orchestrator.Logger) (orchestrator.Backup, error) {
 	var (
 		backupPath string
 		err        error
 	)
 
+	backupDirectory := &BackupDirectory{kind: manager.Kind, compression: manager.Compression, backupSelection: manager.BackupSelection, encryptionLoaded: true, Logger: logger}
+	if len(manager.EncryptionKey) > 0 {
+		backupDirectory.encryptionKey, err = newEncryptionKey(manager.EncryptionKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s3.IsURL(path) {
+		storage, err := openBucketStorage(path, directoryName)
+		if err != nil {
+			return nil, err
+		}
+
+		exists, err := storage.Exists(metadataFilename)
+		if err != nil && !s3.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed checking artifact directory %s", storage.Location())
+		}
+		if exists {
+			return nil, errors.Errorf("failed creating artifact directory: %s already exists", storage.Location())
+		}
+
+		backupDirectory.storage = storage
+		return backupDirectory, nil
+	}
+
 	if path != "" {
 		fileInfo, err := os.Stat(path)
 		if err != nil {
@@ -37,10 +79,82 @@ func (BackupDirectoryManager) Create(path, directoryName string, logger orchestr
 		return nil, errors.New("failed creating artifact directory")
 	}
 
-	return &BackupDirectory{baseDirName: backupPath, Logger: logger}, nil
+	backupDirectory.storage = localStorage{baseDirName: backupPath}
+	return backupDirectory, nil
 }
 
-func (BackupDirectoryManager) Open(name string, logger orchestrator.Logger) (orchestrator.Backup, error) {
+func (manager BackupDirectoryManager) Open(name string, logger orchestrator.Logger) (orchestrator.Backup, error) {
+	if s3.IsURL(name) {
+		storage, err := openBucketStorage(name, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed opening the directory")
+		}
+
+		_, err = storage.Exists(metadataFilename)
+		return manager.open(storage, logger), errors.Wrap(err, "failed opening the directory")
+	}
+
 	_, err := os.Stat(name)
-	return &BackupDirectory{baseDirName: name, Logger: logger}, errors.Wrap(err, "failed opening the directory")
+	return manager.open(localStorage{baseDirName: name}, logger), errors.Wrap(err, "failed opening the directory")
+}
+
+// open carries on with the compression an existing backup was started with, so the artifacts
+// a resumed backup drains are written the same way as the rest
+func (manager BackupDirectoryManager) open(storage Storage, logger orchestrator.Logger) *BackupDirectory {
+	backupDirectory := &BackupDirectory{storage: storage, encryptionSecret: manager.EncryptionKey, restoreSelection: manager.RestoreSelection, Logger: logger}
+	if meta, err := readMetadata(storage, metadataFilename); err == nil {
+		backupDirectory.compression = meta.Compression
+	}
+	return backupDirectory
+}
+
+func (manager BackupDirectoryManager) Verify(name string, logger orchestrator.Logger) (Verification, error) {
+	artifact, err := manager.Open(name, logger)
+	if err != nil {
+		return Verification{Location: name}, err
+	}
+
+	return artifact.(*BackupDirectory).Verify()
+}
+
+// RecordedSelection returns the instance groups, jobs and artifacts a partial backup was
+// restricted to. Instance IDs, AZs and exclusions are left out, as they need not match the
+// deployment being restored. It is empty for a backup of the whole deployment.
+func (manager BackupDirectoryManager) RecordedSelection(name string, logger orchestrator.Logger) (orchestrator.Selection, error) {
+	artifact, err := manager.Open(name, logger)
+	if err != nil {
+		return orchestrator.Selection{}, err
+	}
+
+	meta, err := readMetadata(artifact.(*BackupDirectory).storage, metadataFilename)
+	if err != nil {
+		return orchestrator.Selection{}, err
+	}
+	recorded := meta.Selection.selection()
+	return orchestrator.Selection{
+		InstanceGroups: recorded.InstanceGroups,
+		Jobs:           recorded.Jobs,
+		Artifacts:      recorded.Artifacts,
+	}, nil
+}
+
+func openBucketStorage(url, directoryName string) (bucketStorage, error) {
+	bucket, prefix, err := s3.ParseURL(url)
+	if err != nil {
+		return bucketStorage{}, err
+	}
+
+	if directoryName != "" {
+		if prefix != "" {
+			prefix = prefix + "/"
+		}
+		prefix = prefix + directoryName
+	}
+
+	client, err := s3.NewClientFromEnvironment()
+	if err != nil {
+		return bucketStorage{}, err
+	}
+
+	return bucketStorage{client: client, bucket: bucket, prefix: prefix}, nil
 }
@@ -99,6 +99,42 @@ instances:
 			})
 		})
 
+		Context("when only some of the backup is being restored", func() {
+			BeforeEach(func() {
+				createTestMetadata(backupName, `---
+instances:
+- name: redis
+  index: 0
+  artifacts:
+  - name: redis-server
+- name: redis
+  index: 1
+  artifacts:
+  - name: redis-server
+- name: broker
+  index: 0
+  artifacts:
+  - name: redis-broker
+`)
+			})
+
+			It("only requires the instances with selected artifacts to be in the deployment", func() {
+				selectiveManager := BackupDirectoryManager{RestoreSelection: orchestrator.Selection{Jobs: []string{"redis-server"}}}
+				artifact, _ = selectiveManager.Open(backupName, logger)
+
+				match, _ := artifact.DeploymentMatches(backupName, []orchestrator.Instance{instance1, instance2})
+				Expect(match).To(BeTrue())
+			})
+
+			It("still requires the selected instances to be in the deployment", func() {
+				selectiveManager := BackupDirectoryManager{RestoreSelection: orchestrator.Selection{InstanceGroups: []string{"broker"}}}
+				artifact, _ = selectiveManager.Open(backupName, logger)
+
+				match, _ := artifact.DeploymentMatches(backupName, []orchestrator.Instance{instance1, instance2})
+				Expect(match).To(BeFalse())
+			})
+		})
+
 		Context("when an error occurs unmarshaling the metadata", func() {
 			BeforeEach(func() {
 				Expect(os.Mkdir(backupName, 0777)).To(Succeed())
@@ -123,6 +159,31 @@ instances:
 		})
 	})
 
+	Describe("OriginalInstanceID", func() {
+		var artifact orchestrator.Backup
+
+		BeforeEach(func() {
+			artifact, _ = backupDirectoryManager.Open(backupName, logger)
+			createTestMetadata(backupName, `---
+instances:
+- name: redis
+  index: 0
+  id: 3e2fe0fd-b7f5-4a2c-a5d6-9b3a4c0c5c31
+- name: redis
+  index: 1
+`)
+		})
+
+		It("returns the ID recorded for the instance", func() {
+			Expect(artifact.OriginalInstanceID("redis", "0")).To(Equal("3e2fe0fd-b7f5-4a2c-a5d6-9b3a4c0c5c31"))
+		})
+
+		It("returns an empty ID when none was recorded", func() {
+			Expect(artifact.OriginalInstanceID("redis", "1")).To(BeEmpty())
+			Expect(artifact.OriginalInstanceID("redis", "2")).To(BeEmpty())
+		})
+	})
+
 	Describe("Valid", func() {
 		var backup orchestrator.Backup
 		var verifyResult bool
@@ -608,6 +669,17 @@ instances:
 				})
 			})
 
+			Context("when the instance has an ID", func() {
+				BeforeEach(func() {
+					fakeBackupArtifact.InstanceIDReturns("3e2fe0fd-b7f5-4a2c-a5d6-9b3a4c0c5c31")
+				})
+
+				It("records the ID of the instance", func() {
+					Expect(addChecksumError).NotTo(HaveOccurred())
+					Expect(artifact.OriginalInstanceID("redis-server", "0")).To(Equal("3e2fe0fd-b7f5-4a2c-a5d6-9b3a4c0c5c31"))
+				})
+			})
+
 			Context("when default artifacts for the same instance have been added", func() {
 				BeforeEach(func() {
 					anotherFakeBackupArtifact := new(fakes.FakeBackupArtifact)
@@ -944,12 +1016,79 @@ backup_activity:
 			})
 		})
 
+		Context("when the kind of backup is known", func() {
+			BeforeEach(func() {
+				Expect(os.RemoveAll(backupName)).To(Succeed())
+
+				var err error
+				artifact, err = BackupDirectoryManager{Kind: KindDirector}.Create("", backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("records it", func() {
+				theTime := time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC)
+				Expect(artifact.CreateMetadataFileWithStartTime(theTime)).To(Succeed())
+
+				expectedMetadata := `---
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+kind: director`
+
+				Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(expectedMetadata))
+			})
+		})
+
 		Context("when the metadata file already exists", func() {
 			It("returns an error", func() {
 				createTestMetadata(backupName, "")
 				Expect(artifact.CreateMetadataFileWithStartTime(time.Now())).To(MatchError("metadata file already exists"))
 			})
 		})
+
+		Context("when only some instances and jobs were selected", func() {
+			BeforeEach(func() {
+				Expect(os.RemoveAll(backupName)).To(Succeed())
+
+				var err error
+				selectiveManager := BackupDirectoryManager{BackupSelection: orchestrator.Selection{
+					InstanceGroups: []string{"redis"},
+					Instances:      []string{"redis/0"},
+					AZs:            []string{"z1"},
+					Jobs:           []string{"redis-server"},
+					ExcludeJobs:    []string{"consul_agent"},
+				}}
+				artifact, err = selectiveManager.Create("", backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("records the selection", func() {
+				theTime := time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC)
+				Expect(artifact.CreateMetadataFileWithStartTime(theTime)).To(Succeed())
+
+				expectedMetadata := `---
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+selection:
+  instance_groups: [redis]
+  instances: [redis/0]
+  azs: [z1]
+  jobs: [redis-server]
+  exclude_jobs: [consul_agent]`
+
+				Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(expectedMetadata))
+			})
+
+			It("reads back only the instance groups, jobs and artifacts when restoring", func() {
+				Expect(artifact.CreateMetadataFileWithStartTime(time.Now())).To(Succeed())
+
+				selection, err := BackupDirectoryManager{}.RecordedSelection(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(selection).To(Equal(orchestrator.Selection{
+					InstanceGroups: []string{"redis"},
+					Jobs:           []string{"redis-server"},
+				}))
+			})
+		})
 	})
 
 	Describe("AddFinishTime", func() {
@@ -984,6 +1123,80 @@ backup_activity:
 		})
 	})
 
+	Describe("AddVerifyResults", func() {
+		var artifact orchestrator.Backup
+
+		BeforeEach(func() {
+			var err error
+			artifact, err = backupDirectoryManager.Create("", backupName, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(backupName)).To(Succeed())
+		})
+
+		It("records whether each verify script passed in the metadata", func() {
+			Expect(artifact.AddVerifyResults([]orchestrator.VerifyResult{
+				{InstanceIdentifier: "redis/0", JobName: "redis-server"},
+				{InstanceIdentifier: "redis/1", JobName: "redis-server", Error: fmt.Errorf("dump is truncated")},
+			})).To(Succeed())
+
+			Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(`---
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+post_backup_verify:
+- instance: redis/0
+  job: redis-server
+  passed: true
+- instance: redis/1
+  job: redis-server
+  passed: false
+  error: dump is truncated`))
+		})
+	})
+
+	Describe("AddLog", func() {
+		var artifact orchestrator.Backup
+
+		BeforeEach(func() {
+			var err error
+			artifact, err = backupDirectoryManager.Create("", backupName, logger)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(backupName)).To(Succeed())
+		})
+
+		Context("when no metadata file exists", func() {
+			It("returns an error", func() {
+				Expect(artifact.AddLog("redis-0-redis-server-backup", []byte("dumped"))).To(MatchError(ContainSubstring("unable to load metadata")))
+			})
+		})
+
+		Context("when the metadata file already exists", func() {
+			BeforeEach(func() {
+				Expect(artifact.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+			})
+
+			It("saves the log in the logs directory and lists it in the metadata", func() {
+				Expect(artifact.AddLog("redis-0-redis-server-pre-backup-lock", []byte("locked"))).To(Succeed())
+				Expect(artifact.AddLog("redis-0-redis-server-backup", []byte("dumped"))).To(Succeed())
+
+				Expect(ioutil.ReadFile(backupName + "/logs/redis-0-redis-server-pre-backup-lock.log")).To(Equal([]byte("locked")))
+				Expect(ioutil.ReadFile(backupName + "/logs/redis-0-redis-server-backup.log")).To(Equal([]byte("dumped")))
+				Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(`---
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+logs:
+- logs/redis-0-redis-server-pre-backup-lock.log
+- logs/redis-0-redis-server-backup.log`))
+			})
+		})
+	})
+
 	Describe("GetArtifactSize", func() {
 		var (
 			jobName            string
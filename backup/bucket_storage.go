@@ -0,0 +1,54 @@
+package backup
+
+import (
+	"io"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bytesize"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/s3"
+)
+
+type bucketStorage struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+func (s bucketStorage) Create(name string) (io.WriteCloser, error) {
+	return s.client.NewUpload(s.bucket, s.key(name)), nil
+}
+
+// CreateDirectory does nothing, as buckets have no directories: a key's prefix is enough
+func (s bucketStorage) CreateDirectory(name string) error {
+	return nil
+}
+
+func (s bucketStorage) Open(name string) (io.ReadCloser, error) {
+	return s.client.Get(s.bucket, s.key(name))
+}
+
+func (s bucketStorage) Exists(name string) (bool, error) {
+	_, err := s.client.Head(s.bucket, s.key(name))
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s bucketStorage) Size(name string) (string, error) {
+	size, err := s.client.Head(s.bucket, s.key(name))
+	if err != nil {
+		return "", err
+	}
+	return bytesize.Format(size), nil
+}
+
+func (s bucketStorage) Location() string {
+	return s3.URLScheme + s.bucket + "/" + s.prefix
+}
+
+func (s bucketStorage) key(name string) string {
+	if s.prefix == "" {
+		return name
+	}
+	return s.prefix + "/" + name
+}
@@ -0,0 +1,168 @@
+package backup_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/s3/s3test"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BackupDirectory in an S3 bucket", func() {
+	var server *s3test.Server
+	var backupManager = BackupDirectoryManager{}
+	var logger = boshlog.NewWriterLogger(boshlog.LevelDebug, GinkgoWriter)
+	var originalEnv map[string]string
+
+	BeforeEach(func() {
+		server = s3test.NewServer()
+
+		originalEnv = map[string]string{}
+		for name, value := range map[string]string{
+			"AWS_ENDPOINT_URL":      server.URL,
+			"AWS_REGION":            "eu-west-1",
+			"AWS_ACCESS_KEY_ID":     "access-key",
+			"AWS_SECRET_ACCESS_KEY": "secret-key",
+		} {
+			originalEnv[name] = os.Getenv(name)
+			os.Setenv(name, value)
+		}
+	})
+
+	AfterEach(func() {
+		for name, value := range originalEnv {
+			os.Setenv(name, value)
+		}
+		server.Close()
+	})
+
+	Describe("Create", func() {
+		It("stores the backup under the given prefix", func() {
+			backup, err := backupManager.Create("s3://backups/nightly", "redis_20151021T010203Z", logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(backup.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+			Expect(backup.SaveManifest("a manifest")).To(Succeed())
+
+			writer, err := backup.CreateArtifact(bucketArtifact("redis", "0", "redis-backup"))
+			Expect(err).NotTo(HaveOccurred())
+			writer.Write([]byte("artifact contents"))
+			Expect(writer.Close()).To(Succeed())
+
+			Expect(server.Keys()).To(ConsistOf(
+				"backups/nightly/redis_20151021T010203Z/metadata",
+				"backups/nightly/redis_20151021T010203Z/manifest.yml",
+				"backups/nightly/redis_20151021T010203Z/redis-0-redis-backup.tar",
+			))
+		})
+
+		It("does not store artifacts which are closed with an error", func() {
+			compressedBackupManager := BackupDirectoryManager{Compression: CompressionGzip}
+			backup, err := compressedBackupManager.Create("s3://backups", "redis_20151021T010203Z", logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			writer, err := backup.CreateArtifact(bucketArtifact("redis", "0", "redis-backup"))
+			Expect(err).NotTo(HaveOccurred())
+			writer.Write([]byte("partial artifact contents"))
+			Expect(writer.(orchestrator.AbortableWriter).CloseWithError(fmt.Errorf("stream failed"))).To(MatchError("stream failed"))
+
+			Expect(server.Keys()).NotTo(ContainElement("backups/redis_20151021T010203Z/redis-0-redis-backup.tar"))
+		})
+
+		Context("when the backup already exists in the bucket", func() {
+			BeforeEach(func() {
+				server.PutObject("backups", "redis_20151021T010203Z/metadata", []byte("---"))
+			})
+
+			It("returns an error", func() {
+				_, err := backupManager.Create("s3://backups", "redis_20151021T010203Z", logger)
+				Expect(err).To(MatchError(ContainSubstring("failed creating artifact directory")))
+			})
+		})
+
+		Context("when the bucket cannot be reached", func() {
+			BeforeEach(func() {
+				os.Setenv("AWS_ENDPOINT_URL", "http://127.0.0.1:1")
+			})
+
+			It("returns an error rather than creating the backup", func() {
+				_, err := backupManager.Create("s3://backups", "redis_20151021T010203Z", logger)
+				Expect(err).To(MatchError(ContainSubstring("failed checking artifact directory")))
+			})
+		})
+
+		Context("when no credentials are configured", func() {
+			BeforeEach(func() {
+				os.Setenv("AWS_ACCESS_KEY_ID", "")
+			})
+
+			It("returns an error", func() {
+				_, err := backupManager.Create("s3://backups", "redis_20151021T010203Z", logger)
+				Expect(err).To(MatchError(ContainSubstring("S3 credentials are required")))
+			})
+		})
+	})
+
+	Describe("Open", func() {
+		Context("when the backup exists", func() {
+			var backup orchestrator.Backup
+
+			BeforeEach(func() {
+				server.PutObject("backups", "redis/metadata", []byte(`---
+instances:
+- name: redis
+  index: "0"
+`))
+				server.PutObject("backups", "redis/redis-0-redis-backup.tar", []byte("artifact contents"))
+
+				var err error
+				backup, err = backupManager.Open("s3://backups/redis", logger)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("reads artifacts from the bucket", func() {
+				reader, err := backup.ReadArtifact(bucketArtifact("redis", "0", "redis-backup"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ioutil.ReadAll(reader)).To(Equal([]byte("artifact contents")))
+			})
+
+			It("reports the artifact size", func() {
+				size, err := backup.GetArtifactSize(bucketArtifact("redis", "0", "redis-backup"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(size).To(Equal("17"))
+			})
+
+			It("matches the deployment described by the metadata", func() {
+				instance := new(fakes.FakeInstance)
+				instance.NameReturns("redis")
+				instance.IndexReturns("0")
+
+				match, err := backup.DeploymentMatches("redis", []orchestrator.Instance{instance})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(match).To(BeTrue())
+			})
+		})
+
+		Context("when there is no backup at the URL", func() {
+			It("fails", func() {
+				_, err := backupManager.Open("s3://backups/missing", logger)
+				Expect(err).To(MatchError(ContainSubstring("failed opening the directory")))
+			})
+		})
+	})
+})
+
+func bucketArtifact(instanceName, instanceIndex, name string) *fakes.FakeBackupArtifact {
+	artifact := new(fakes.FakeBackupArtifact)
+	artifact.InstanceNameReturns(instanceName)
+	artifact.InstanceIndexReturns(instanceIndex)
+	artifact.NameReturns(name)
+	return artifact
+}
@@ -0,0 +1,98 @@
+package backup
+
+import (
+	"compress/gzip"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+func ValidateCompression(codec string) error {
+	switch codec {
+	case "", CompressionNone, CompressionGzip, CompressionZstd:
+		return nil
+	default:
+		return errors.Errorf("unsupported compression %q: must be one of %s, %s or %s", codec, CompressionGzip, CompressionZstd, CompressionNone)
+	}
+}
+
+func compressingWriter(codec string, writer io.WriteCloser) (io.WriteCloser, error) {
+	switch codec {
+	case "", CompressionNone:
+		return writer, nil
+	case CompressionGzip:
+		return &compressedWriteCloser{compressor: gzip.NewWriter(writer), underlying: writer}, nil
+	case CompressionZstd:
+		encoder, err := zstd.NewWriter(writer)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create zstd encoder")
+		}
+		return &compressedWriteCloser{compressor: encoder, underlying: writer}, nil
+	default:
+		return nil, ValidateCompression(codec)
+	}
+}
+
+func decompressingReader(codec string, reader io.ReadCloser) (io.ReadCloser, error) {
+	switch codec {
+	case "", CompressionNone:
+		return reader, nil
+	case CompressionGzip:
+		decompressor, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read gzip header")
+		}
+		return &decompressedReadCloser{Reader: decompressor, close: decompressor.Close, underlying: reader}, nil
+	case CompressionZstd:
+		decoder, err := zstd.NewReader(reader)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create zstd decoder")
+		}
+		return &decompressedReadCloser{Reader: decoder, close: func() error { decoder.Close(); return nil }, underlying: reader}, nil
+	default:
+		return nil, ValidateCompression(codec)
+	}
+}
+
+type compressedWriteCloser struct {
+	compressor io.WriteCloser
+	underlying io.WriteCloser
+}
+
+func (w *compressedWriteCloser) Write(data []byte) (int, error) {
+	return w.compressor.Write(data)
+}
+
+// Close flushes the compressor before closing the file, so the trailer is written
+func (w *compressedWriteCloser) Close() error {
+	if err := w.compressor.Close(); err != nil {
+		w.underlying.Close()
+		return errors.Wrap(err, "failed to flush compressed artifact")
+	}
+	return w.underlying.Close()
+}
+
+// CloseWithError abandons the file without flushing the compressor
+func (w *compressedWriteCloser) CloseWithError(err error) error {
+	err = closeWithError(w.underlying, err)
+	w.compressor.Close()
+	return err
+}
+
+type decompressedReadCloser struct {
+	io.Reader
+	close      func() error
+	underlying io.ReadCloser
+}
+
+func (r *decompressedReadCloser) Close() error {
+	r.close()
+	return r.underlying.Close()
+}
@@ -0,0 +1,201 @@
+package backup_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bytesize"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/config"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+var _ = Describe("Compressed artifacts", func() {
+	var backupName string
+	var logger = boshlog.NewWriterLogger(boshlog.LevelDebug, GinkgoWriter)
+	var tarContents []byte
+	var fakeBackupArtifact *fakes.FakeBackupArtifact
+
+	BeforeEach(func() {
+		backupName = fmt.Sprintf("my-cool-redis-%d_20151021T010203Z", config.GinkgoConfig.ParallelNode)
+		tarContents = createTarWithContents(map[string]string{"readme.txt": "This archive contains some text files."})
+
+		fakeBackupArtifact = new(fakes.FakeBackupArtifact)
+		fakeBackupArtifact.InstanceNameReturns("redis-server")
+		fakeBackupArtifact.InstanceIndexReturns("0")
+		fakeBackupArtifact.NameReturns("redis")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(backupName)).To(Succeed())
+	})
+
+	writeArtifact := func(compression string) orchestrator.Backup {
+		artifact, err := BackupDirectoryManager{Compression: compression}.Create("", backupName, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(artifact.CreateMetadataFileWithStartTime(time.Now())).To(Succeed())
+
+		writer, err := artifact.CreateArtifact(fakeBackupArtifact)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = writer.Write(tarContents)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+
+		return artifact
+	}
+
+	for _, compression := range []string{CompressionGzip, CompressionZstd} {
+		compression := compression
+
+		Context("when the backup is compressed with "+compression, func() {
+			It("compresses the artifact on disk", func() {
+				writeArtifact(compression)
+
+				onDisk, err := ioutil.ReadFile(backupName + "/redis-server-0-redis.tar")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(onDisk).NotTo(Equal(tarContents))
+			})
+
+			It("checksums the uncompressed contents", func() {
+				artifact := writeArtifact(compression)
+
+				checksum, err := artifact.CalculateChecksum(fakeBackupArtifact)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(checksum).To(Equal(orchestrator.BackupChecksum{
+					"readme.txt": fmt.Sprintf("%x", sha256.Sum256([]byte("This archive contains some text files."))),
+				}))
+			})
+
+			It("records the codec in the metadata and reads the artifact back decompressed", func() {
+				artifact := writeArtifact(compression)
+				checksum, err := artifact.CalculateChecksum(fakeBackupArtifact)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(artifact.AddChecksum(fakeBackupArtifact, checksum)).To(Succeed())
+
+				metadata, err := ioutil.ReadFile(backupName + "/metadata")
+				Expect(err).NotTo(HaveOccurred())
+				var parsed struct {
+					Instances []struct {
+						Artifacts []struct {
+							Compression string `yaml:"compression"`
+						} `yaml:"artifacts"`
+					} `yaml:"instances"`
+				}
+				Expect(yaml.Unmarshal(metadata, &parsed)).To(Succeed())
+				Expect(parsed.Instances[0].Artifacts[0].Compression).To(Equal(compression))
+
+				reopened, err := BackupDirectoryManager{}.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+				reader, err := reopened.ReadArtifact(fakeBackupArtifact)
+				Expect(err).NotTo(HaveOccurred())
+				defer reader.Close()
+				Expect(ioutil.ReadAll(reader)).To(Equal(tarContents))
+			})
+
+			It("reports the size of the artifact before it was compressed", func() {
+				artifact := writeArtifact(compression)
+				checksum, err := artifact.CalculateChecksum(fakeBackupArtifact)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(artifact.AddChecksum(fakeBackupArtifact, checksum)).To(Succeed())
+
+				Expect(artifact.GetArtifactSize(fakeBackupArtifact)).To(Equal(bytesize.Format(int64(len(tarContents)))))
+			})
+
+			It("keeps compressing the artifacts of a reopened backup", func() {
+				artifact, err := BackupDirectoryManager{Compression: compression}.Create("", backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(artifact.CreateMetadataFileWithStartTime(time.Now())).To(Succeed())
+
+				reopened, err := BackupDirectoryManager{}.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+				writer, err := reopened.CreateArtifact(fakeBackupArtifact)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = writer.Write(tarContents)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(writer.Close()).To(Succeed())
+
+				onDisk, err := ioutil.ReadFile(backupName + "/redis-server-0-redis.tar")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(onDisk).NotTo(Equal(tarContents))
+
+				reader, err := reopened.ReadArtifact(fakeBackupArtifact)
+				Expect(err).NotTo(HaveOccurred())
+				defer reader.Close()
+				Expect(ioutil.ReadAll(reader)).To(Equal(tarContents))
+			})
+		})
+	}
+
+	Context("when the backup is not compressed", func() {
+		It("writes the tar as is and does not record a codec", func() {
+			artifact := writeArtifact(CompressionNone)
+			checksum, err := artifact.CalculateChecksum(fakeBackupArtifact)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.AddChecksum(fakeBackupArtifact, checksum)).To(Succeed())
+
+			Expect(ioutil.ReadFile(backupName + "/redis-server-0-redis.tar")).To(Equal(tarContents))
+			Expect(ioutil.ReadFile(backupName + "/metadata")).NotTo(ContainSubstring("compression"))
+		})
+	})
+
+	Context("when the metadata records a codec the artifact was not written with", func() {
+		It("fails to read the artifact", func() {
+			Expect(os.MkdirAll(backupName, 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(backupName+"/redis-server-0-redis.tar", tarContents, 0600)).To(Succeed())
+			createTestMetadata(backupName, `---
+instances:
+- name: redis-server
+  index: "0"
+  artifacts:
+  - name: redis
+    checksums: {}
+    compression: gzip
+`)
+
+			artifact, err := BackupDirectoryManager{}.Open(backupName, logger)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = artifact.ReadArtifact(fakeBackupArtifact)
+			Expect(err).To(MatchError(ContainSubstring("Error reading artifact file")))
+		})
+	})
+
+	Context("when the metadata records a compressed artifact without its size", func() {
+		It("does not report a size", func() {
+			Expect(os.MkdirAll(backupName, 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(backupName+"/redis-server-0-redis.tar", tarContents, 0600)).To(Succeed())
+			createTestMetadata(backupName, `---
+instances:
+- name: redis-server
+  index: "0"
+  artifacts:
+  - name: redis
+    checksums: {}
+    compression: gzip
+`)
+
+			artifact, err := BackupDirectoryManager{}.Open(backupName, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.GetArtifactSize(fakeBackupArtifact)).To(BeEmpty())
+		})
+	})
+
+	Describe("ValidateCompression", func() {
+		It("accepts the supported codecs", func() {
+			for _, compression := range []string{"", CompressionNone, CompressionGzip, CompressionZstd} {
+				Expect(ValidateCompression(compression)).To(Succeed())
+			}
+		})
+
+		It("rejects anything else", func() {
+			Expect(ValidateCompression("bzip2")).To(MatchError(ContainSubstring(`unsupported compression "bzip2"`)))
+		})
+	})
+})
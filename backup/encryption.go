@@ -0,0 +1,260 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	EncryptionAlgorithm        = "aes-256-gcm"
+	EncryptionPassphraseEnvVar = "BBR_ENCRYPTION_PASSPHRASE"
+
+	encryptionChunkSize   = 64 * 1024
+	encryptionSaltSize    = 16
+	encryptionNoncePrefix = 7
+)
+
+// ReadEncryptionKey returns the contents of keyFile, or the passphrase from the environment
+// when no key file is given. It returns nil if neither is set.
+func ReadEncryptionKey(keyFile string) ([]byte, error) {
+	var secret []byte
+	if keyFile != "" {
+		contents, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read encryption key file")
+		}
+		secret = bytes.TrimSpace(contents)
+		if len(secret) == 0 {
+			return nil, errors.Errorf("encryption key file %s is empty", keyFile)
+		}
+	} else if passphrase := os.Getenv(EncryptionPassphraseEnvVar); passphrase != "" {
+		secret = []byte(passphrase)
+	}
+	return secret, nil
+}
+
+type encryptionKey struct {
+	key  []byte
+	salt []byte
+}
+
+func newEncryptionKey(secret []byte) (*encryptionKey, error) {
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate encryption salt")
+	}
+	return deriveEncryptionKey(secret, salt)
+}
+
+func deriveEncryptionKey(secret, salt []byte) (*encryptionKey, error) {
+	key, err := scrypt.Key(secret, salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to derive encryption key")
+	}
+	return &encryptionKey{key: key, salt: salt}, nil
+}
+
+func loadEncryptionKey(secret []byte, meta *encryptionMetadata) (*encryptionKey, error) {
+	if meta.Algorithm != EncryptionAlgorithm {
+		return nil, errors.Errorf("unsupported encryption algorithm %q", meta.Algorithm)
+	}
+	if len(secret) == 0 {
+		return nil, errors.Errorf("backup is encrypted with key %s: an encryption key is required", meta.KeyFingerprint)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(meta.Salt)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid encryption salt in metadata")
+	}
+
+	key, err := deriveEncryptionKey(secret, salt)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(key.fingerprint()), []byte(meta.KeyFingerprint)) {
+		return nil, errors.Errorf("encryption key does not match: backup was encrypted with key %s, provided key is %s", meta.KeyFingerprint, key.fingerprint())
+	}
+	return key, nil
+}
+
+func (k *encryptionKey) fingerprint() string {
+	mac := hmac.New(sha256.New, k.key)
+	mac.Write([]byte("bbr key fingerprint"))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func (k *encryptionKey) metadata() *encryptionMetadata {
+	return &encryptionMetadata{
+		Algorithm:      EncryptionAlgorithm,
+		KeyFingerprint: k.fingerprint(),
+		Salt:           base64.StdEncoding.EncodeToString(k.salt),
+	}
+}
+
+func (k *encryptionKey) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypted files are a random nonce prefix followed by fixed size sealed chunks. Each
+// chunk's nonce is the prefix, the chunk counter and a flag marking the final chunk, so
+// chunks cannot be reordered and a truncated file fails to decrypt.
+func encryptingWriter(key *encryptionKey, writer io.WriteCloser) (io.WriteCloser, error) {
+	if key == nil {
+		return writer, nil
+	}
+
+	aead, err := key.aead()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialise cipher")
+	}
+
+	prefix := make([]byte, encryptionNoncePrefix)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+	if _, err := writer.Write(prefix); err != nil {
+		return nil, errors.Wrap(err, "failed to write encryption header")
+	}
+
+	return &encryptedWriteCloser{aead: aead, prefix: prefix, underlying: writer}, nil
+}
+
+func decryptingReader(key *encryptionKey, reader io.ReadCloser) (io.ReadCloser, error) {
+	if key == nil {
+		return reader, nil
+	}
+
+	aead, err := key.aead()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialise cipher")
+	}
+
+	buffered := bufio.NewReaderSize(reader, encryptionChunkSize+aead.Overhead())
+	prefix := make([]byte, encryptionNoncePrefix)
+	if _, err := io.ReadFull(buffered, prefix); err != nil {
+		return nil, errors.Wrap(err, "failed to read encryption header")
+	}
+
+	return &decryptedReadCloser{aead: aead, prefix: prefix, source: buffered, underlying: reader}, nil
+}
+
+func chunkNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, encryptionNoncePrefix+5)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[encryptionNoncePrefix:], counter)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+type encryptedWriteCloser struct {
+	aead       cipher.AEAD
+	prefix     []byte
+	counter    uint32
+	buffer     bytes.Buffer
+	underlying io.WriteCloser
+}
+
+func (w *encryptedWriteCloser) Write(data []byte) (int, error) {
+	written, _ := w.buffer.Write(data)
+	// keep at least one byte back, so the final chunk is never empty unless the file is
+	for w.buffer.Len() > encryptionChunkSize {
+		if err := w.seal(w.buffer.Next(encryptionChunkSize), false); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+func (w *encryptedWriteCloser) Close() error {
+	if err := w.seal(w.buffer.Bytes(), true); err != nil {
+		w.underlying.Close()
+		return err
+	}
+	return w.underlying.Close()
+}
+
+// CloseWithError abandons the file without sealing the final chunk
+func (w *encryptedWriteCloser) CloseWithError(err error) error {
+	return closeWithError(w.underlying, err)
+}
+
+func (w *encryptedWriteCloser) seal(chunk []byte, last bool) error {
+	sealed := w.aead.Seal(nil, chunkNonce(w.prefix, w.counter, last), chunk, nil)
+	w.counter++
+	if _, err := w.underlying.Write(sealed); err != nil {
+		return errors.Wrap(err, "failed to write encrypted chunk")
+	}
+	return nil
+}
+
+type decryptedReadCloser struct {
+	aead       cipher.AEAD
+	prefix     []byte
+	counter    uint32
+	source     *bufio.Reader
+	plaintext  []byte
+	done       bool
+	underlying io.ReadCloser
+}
+
+func (r *decryptedReadCloser) Read(data []byte) (int, error) {
+	for len(r.plaintext) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	read := copy(data, r.plaintext)
+	r.plaintext = r.plaintext[read:]
+	return read, nil
+}
+
+func (r *decryptedReadCloser) open() error {
+	sealed := make([]byte, encryptionChunkSize+r.aead.Overhead())
+	length, err := io.ReadFull(r.source, sealed)
+	switch {
+	case err == io.ErrUnexpectedEOF || err == io.EOF:
+		r.done = true
+	case err != nil:
+		return errors.Wrap(err, "failed to read encrypted chunk")
+	default:
+		if _, peekErr := r.source.Peek(1); peekErr == io.EOF {
+			r.done = true
+		}
+	}
+
+	plaintext, err := r.aead.Open(nil, chunkNonce(r.prefix, r.counter, r.done), sealed[:length], nil)
+	if err != nil {
+		return errors.New("failed to decrypt artifact: the file is corrupt or was encrypted with a different key")
+	}
+	r.counter++
+	r.plaintext = plaintext
+	return nil
+}
+
+func (r *decryptedReadCloser) Close() error {
+	return r.underlying.Close()
+}
@@ -0,0 +1,212 @@
+package backup_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/config"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+var _ = Describe("Encrypted artifacts", func() {
+	var backupName string
+	var logger = boshlog.NewWriterLogger(boshlog.LevelDebug, GinkgoWriter)
+	var tarContents []byte
+	var fakeBackupArtifact *fakes.FakeBackupArtifact
+	var key = []byte("correct horse battery staple")
+
+	BeforeEach(func() {
+		backupName = fmt.Sprintf("my-cool-redis-%d_20151021T010203Z", config.GinkgoConfig.ParallelNode)
+		tarContents = createTarWithContents(map[string]string{"readme.txt": "This archive contains some text files."})
+
+		fakeBackupArtifact = new(fakes.FakeBackupArtifact)
+		fakeBackupArtifact.InstanceNameReturns("redis-server")
+		fakeBackupArtifact.InstanceIndexReturns("0")
+		fakeBackupArtifact.NameReturns("redis")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(backupName)).To(Succeed())
+	})
+
+	writeBackup := func(manager BackupDirectoryManager, contents []byte) {
+		artifact, err := manager.Create("", backupName, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(artifact.CreateMetadataFileWithStartTime(time.Now())).To(Succeed())
+		Expect(artifact.SaveManifest("a manifest with secrets")).To(Succeed())
+
+		writer, err := artifact.CreateArtifact(fakeBackupArtifact)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = writer.Write(contents)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+
+		checksum, err := artifact.CalculateChecksum(fakeBackupArtifact)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(artifact.AddChecksum(fakeBackupArtifact, checksum)).To(Succeed())
+	}
+
+	Context("when the backup is written with an encryption key", func() {
+		BeforeEach(func() {
+			writeBackup(BackupDirectoryManager{EncryptionKey: key}, tarContents)
+		})
+
+		It("does not write the artifact or manifest in clear text", func() {
+			Expect(ioutil.ReadFile(backupName + "/redis-server-0-redis.tar")).NotTo(ContainSubstring("This archive contains"))
+			Expect(ioutil.ReadFile(backupName + "/manifest.yml")).NotTo(ContainSubstring("a manifest with secrets"))
+		})
+
+		It("records the algorithm and key fingerprint in the metadata", func() {
+			contents, err := ioutil.ReadFile(backupName + "/metadata")
+			Expect(err).NotTo(HaveOccurred())
+
+			var parsed struct {
+				Encryption map[string]string `yaml:"encryption"`
+			}
+			Expect(yaml.Unmarshal(contents, &parsed)).To(Succeed())
+			Expect(parsed.Encryption).To(HaveKeyWithValue("algorithm", "aes-256-gcm"))
+			Expect(parsed.Encryption["key_fingerprint"]).To(HaveLen(32))
+			Expect(parsed.Encryption["salt"]).NotTo(BeEmpty())
+		})
+
+		Context("and opened with the same key", func() {
+			var artifact orchestrator.Backup
+
+			BeforeEach(func() {
+				var err error
+				artifact, err = BackupDirectoryManager{EncryptionKey: key}.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("is valid", func() {
+				Expect(artifact.Valid()).To(BeTrue())
+			})
+
+			It("decrypts the artifact when it is read", func() {
+				reader, err := artifact.ReadArtifact(fakeBackupArtifact)
+				Expect(err).NotTo(HaveOccurred())
+				defer reader.Close()
+				Expect(ioutil.ReadAll(reader)).To(Equal(tarContents))
+			})
+		})
+
+		Context("and opened with a different key", func() {
+			It("fails validation", func() {
+				artifact, err := BackupDirectoryManager{EncryptionKey: []byte("wrong")}.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				valid, err := artifact.Valid()
+				Expect(valid).To(BeFalse())
+				Expect(err).To(MatchError(ContainSubstring("encryption key does not match")))
+			})
+		})
+
+		Context("and opened without a key", func() {
+			It("fails validation", func() {
+				artifact, err := BackupDirectoryManager{}.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				valid, err := artifact.Valid()
+				Expect(valid).To(BeFalse())
+				Expect(err).To(MatchError(ContainSubstring("an encryption key is required")))
+			})
+		})
+
+		Context("and the artifact has been truncated", func() {
+			It("fails validation", func() {
+				contents, err := ioutil.ReadFile(backupName + "/redis-server-0-redis.tar")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ioutil.WriteFile(backupName+"/redis-server-0-redis.tar", contents[:len(contents)-20], 0600)).To(Succeed())
+
+				artifact, err := BackupDirectoryManager{EncryptionKey: key}.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				valid, err := artifact.Valid()
+				Expect(valid).To(BeFalse())
+				Expect(err).To(MatchError(ContainSubstring("failed to decrypt artifact")))
+			})
+		})
+	})
+
+	Context("when the artifact spans several chunks and is compressed", func() {
+		var largeContents []byte
+
+		BeforeEach(func() {
+			files := map[string]string{}
+			for i := 0; i < 50; i++ {
+				files[fmt.Sprintf("file-%d", i)] = fmt.Sprintf("%08d", i*i)
+			}
+			random := rand.New(rand.NewSource(int64(GinkgoRandomSeed())))
+			for i := 0; i < 3; i++ {
+				contents := make([]byte, 100*1024+i)
+				random.Read(contents)
+				files[fmt.Sprintf("big-%d", i)] = string(contents)
+			}
+			largeContents = createTarWithContents(files)
+
+			writeBackup(BackupDirectoryManager{EncryptionKey: key, Compression: CompressionGzip}, largeContents)
+		})
+
+		It("reads back the original contents", func() {
+			artifact, err := BackupDirectoryManager{EncryptionKey: key}.Open(backupName, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.Valid()).To(BeTrue())
+
+			reader, err := artifact.ReadArtifact(fakeBackupArtifact)
+			Expect(err).NotTo(HaveOccurred())
+			defer reader.Close()
+			Expect(ioutil.ReadAll(reader)).To(Equal(largeContents))
+		})
+	})
+
+	Describe("ReadEncryptionKey", func() {
+		var originalPassphrase string
+
+		BeforeEach(func() {
+			originalPassphrase = os.Getenv(EncryptionPassphraseEnvVar)
+			os.Setenv(EncryptionPassphraseEnvVar, "")
+		})
+
+		AfterEach(func() {
+			os.Setenv(EncryptionPassphraseEnvVar, originalPassphrase)
+		})
+
+		It("reads the key file, ignoring surrounding whitespace", func() {
+			keyFile, err := ioutil.TempFile("", "bbr-key")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(keyFile.Name())
+			keyFile.Write([]byte("my-key\n"))
+			keyFile.Close()
+
+			Expect(ReadEncryptionKey(keyFile.Name())).To(Equal([]byte("my-key")))
+		})
+
+		It("fails if the key file is empty", func() {
+			keyFile, err := ioutil.TempFile("", "bbr-key")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(keyFile.Name())
+			keyFile.Close()
+
+			_, err = ReadEncryptionKey(keyFile.Name())
+			Expect(err).To(MatchError(ContainSubstring("is empty")))
+		})
+
+		It("falls back to the passphrase in the environment", func() {
+			os.Setenv(EncryptionPassphraseEnvVar, "a passphrase")
+			Expect(ReadEncryptionKey("")).To(Equal([]byte("a passphrase")))
+		})
+
+		It("returns no key when neither is set", func() {
+			Expect(ReadEncryptionKey("")).To(BeNil())
+		})
+	})
+})
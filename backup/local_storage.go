@@ -0,0 +1,52 @@
+package backup
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+type localStorage struct {
+	baseDirName string
+}
+
+func (s localStorage) Create(name string) (io.WriteCloser, error) {
+	return os.Create(s.path(name))
+}
+
+func (s localStorage) CreateDirectory(name string) error {
+	return os.MkdirAll(s.path(name), 0700)
+}
+
+func (s localStorage) Open(name string) (io.ReadCloser, error) {
+	return os.Open(s.path(name))
+}
+
+func (s localStorage) Exists(name string) (bool, error) {
+	_, err := os.Stat(s.path(name))
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s localStorage) Size(name string) (string, error) {
+	cmd := exec.Command("du", "-sh", s.path(name))
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Fields(string(output))[0], nil
+}
+
+func (s localStorage) Location() string {
+	return s.baseDirName
+}
+
+func (s localStorage) path(name string) string {
+	return path.Join(s.baseDirName, name)
+}
@@ -1,8 +1,7 @@
 package backup
 
 import (
-	"io/ioutil"
-
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 )
@@ -15,24 +14,91 @@ type backupActivityMetadata struct {
 type instanceMetadata struct {
 	Name      string             `yaml:"name"`
 	Index     string             `yaml:"index"`
+	ID        string             `yaml:"id,omitempty"`
 	Artifacts []artifactMetadata `yaml:"artifacts"`
 }
 
 type artifactMetadata struct {
-	Name     string            `yaml:"name"`
-	Checksum map[string]string `yaml:"checksums"`
+	Name        string            `yaml:"name"`
+	Checksum    map[string]string `yaml:"checksums"`
+	Compression string            `yaml:"compression,omitempty"`
+	// Size is the number of bytes in the artifact before it was compressed and encrypted
+	Size int64 `yaml:"size,omitempty"`
+}
+
+type verifyMetadata struct {
+	Instance string `yaml:"instance"`
+	Job      string `yaml:"job"`
+	Passed   bool   `yaml:"passed"`
+	Error    string `yaml:"error,omitempty"`
+}
+
+type encryptionMetadata struct {
+	Algorithm      string `yaml:"algorithm"`
+	KeyFingerprint string `yaml:"key_fingerprint"`
+	Salt           string `yaml:"salt"`
+}
+
+type selectionMetadata struct {
+	InstanceGroups        []string `yaml:"instance_groups,omitempty"`
+	ExcludeInstanceGroups []string `yaml:"exclude_instance_groups,omitempty"`
+	Instances             []string `yaml:"instances,omitempty"`
+	ExcludeInstances      []string `yaml:"exclude_instances,omitempty"`
+	AZs                   []string `yaml:"azs,omitempty"`
+	ExcludeAZs            []string `yaml:"exclude_azs,omitempty"`
+	Jobs                  []string `yaml:"jobs,omitempty"`
+	ExcludeJobs           []string `yaml:"exclude_jobs,omitempty"`
+	Artifacts             []string `yaml:"artifacts,omitempty"`
+}
+
+func newSelectionMetadata(selection orchestrator.Selection) *selectionMetadata {
+	return &selectionMetadata{
+		InstanceGroups:        selection.InstanceGroups,
+		ExcludeInstanceGroups: selection.ExcludeInstanceGroups,
+		Instances:             selection.Instances,
+		ExcludeInstances:      selection.ExcludeInstances,
+		AZs:                   selection.AZs,
+		ExcludeAZs:            selection.ExcludeAZs,
+		Jobs:                  selection.Jobs,
+		ExcludeJobs:           selection.ExcludeJobs,
+		Artifacts:             selection.Artifacts,
+	}
+}
+
+func (data *selectionMetadata) selection() orchestrator.Selection {
+	if data == nil {
+		return orchestrator.Selection{}
+	}
+
+	return orchestrator.Selection{
+		InstanceGroups:        data.InstanceGroups,
+		ExcludeInstanceGroups: data.ExcludeInstanceGroups,
+		Instances:             data.Instances,
+		ExcludeInstances:      data.ExcludeInstances,
+		AZs:                   data.AZs,
+		ExcludeAZs:            data.ExcludeAZs,
+		Jobs:                  data.Jobs,
+		ExcludeJobs:           data.ExcludeJobs,
+		Artifacts:             data.Artifacts,
+	}
 }
 
 type metadata struct {
 	MetadataForEachInstance   []*instanceMetadata    `yaml:"instances,omitempty"`
 	MetadataForEachArtifact   []artifactMetadata     `yaml:"custom_artifacts,omitempty"`
 	MetadataForBackupActivity backupActivityMetadata `yaml:"backup_activity"`
+	Kind                      string                 `yaml:"kind,omitempty"`
+	Compression               string                 `yaml:"compression,omitempty"`
+	Encryption                *encryptionMetadata    `yaml:"encryption,omitempty"`
+	Selection                 *selectionMetadata     `yaml:"selection,omitempty"`
+	PostBackupVerify          []verifyMetadata       `yaml:"post_backup_verify,omitempty"`
+	Logs                      []string               `yaml:"logs,omitempty"`
 }
 
-func readMetadata(filename string) (metadata, error) {
+func readMetadata(storage Storage, filename string) (metadata, error) {
 	metadata := metadata{}
 
-	contents, err := ioutil.ReadFile(filename)
+	contents, err := readFile(storage, filename)
 	if err != nil {
 		return metadata, errors.Wrap(err, "failed to read metadata")
 	}
@@ -43,13 +109,13 @@ func readMetadata(filename string) (metadata, error) {
 	return metadata, nil
 }
 
-func (data *metadata) save(filename string) error {
+func (data *metadata) save(storage Storage, filename string) error {
 	contents, err := yaml.Marshal(data)
 	if err != nil {
 		return errors.Wrap(err, "failed to marshal metadata")
 	}
 
-	return ioutil.WriteFile(filename, contents, 0666)
+	return writeFile(storage, filename, contents)
 }
 
 func (data *metadata) findOrCreateInstanceMetadata(name, index string) *instanceMetadata {
@@ -65,3 +131,24 @@ func (data *metadata) findOrCreateInstanceMetadata(name, index string) *instance
 	data.MetadataForEachInstance = append(data.MetadataForEachInstance, newInstanceMetadata)
 	return newInstanceMetadata
 }
+
+func (data *metadata) findArtifactMetadata(artifactIdentifier orchestrator.ArtifactIdentifier) (artifactMetadata, bool) {
+	if artifactIdentifier.HasCustomName() {
+		for _, customArtifactInMetadata := range data.MetadataForEachArtifact {
+			if customArtifactInMetadata.Name == artifactIdentifier.Name() {
+				return customArtifactInMetadata, true
+			}
+		}
+	} else {
+		for _, instanceInMetadata := range data.MetadataForEachInstance {
+			if instanceInMetadata.Index == artifactIdentifier.InstanceIndex() && instanceInMetadata.Name == artifactIdentifier.InstanceName() {
+				for _, artifact := range instanceInMetadata.Artifacts {
+					if artifact.Name == artifactIdentifier.Name() {
+						return artifact, true
+					}
+				}
+			}
+		}
+	}
+	return artifactMetadata{}, false
+}
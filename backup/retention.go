@@ -0,0 +1,250 @@
+package backup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bytesize"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const backupDirectoryTimestampFormat = "20060102T150405Z"
+
+var backupDirectoryPattern = regexp.MustCompile(`^(.+)_(\d{8}T\d{6}Z)$`)
+
+type RetentionPolicy struct {
+	KeepLast     int
+	KeepDaily    int
+	KeepWeekly   int
+	KeepMonthly  int
+	MaxTotalSize int64
+}
+
+func (p RetentionPolicy) IsEmpty() bool {
+	return p.KeepLast == 0 && p.KeepDaily == 0 && p.KeepWeekly == 0 && p.KeepMonthly == 0 && p.MaxTotalSize == 0
+}
+
+type StoredBackup struct {
+	Name string
+	Path string
+	// Kind is KindDeployment or KindDirector, or empty for backups which did not record it
+	Kind       string
+	StartTime  time.Time
+	FinishTime time.Time
+	Size       int64
+}
+
+func (b StoredBackup) Complete() bool {
+	return !b.FinishTime.IsZero()
+}
+
+type PruneDecision struct {
+	Backup StoredBackup
+	Keep   bool
+	Reason string
+}
+
+// FindBackups lists the <name>_<timestamp> backup directories in artifactPath. If name is
+// empty, backups with any name are returned.
+func FindBackups(artifactPath, name string) ([]StoredBackup, error) {
+	if artifactPath == "" {
+		artifactPath = "."
+	}
+
+	entries, err := ioutil.ReadDir(artifactPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list artifact path")
+	}
+
+	var backups []StoredBackup
+	for _, entry := range entries {
+		matches := backupDirectoryPattern.FindStringSubmatch(entry.Name())
+		if !entry.IsDir() || matches == nil || (name != "" && matches[1] != name) {
+			continue
+		}
+
+		backup, err := readStoredBackup(filepath.Join(artifactPath, entry.Name()), matches[1], matches[2])
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, backup)
+	}
+
+	return backups, nil
+}
+
+func readStoredBackup(path, name, timestamp string) (StoredBackup, error) {
+	backup := StoredBackup{Name: name, Path: path}
+	backup.StartTime, _ = time.Parse(backupDirectoryTimestampFormat, timestamp)
+
+	// a backup which never wrote its metadata is treated as incomplete
+	if contents, err := ioutil.ReadFile(filepath.Join(path, metadataFilename)); err == nil {
+		var meta metadata
+		if err := yaml.Unmarshal(contents, &meta); err != nil {
+			return StoredBackup{}, errors.Wrapf(err, "failed to parse metadata of %s", path)
+		}
+
+		if startTime, err := time.Parse(timestampFormat, meta.MetadataForBackupActivity.StartTime); err == nil {
+			backup.StartTime = startTime
+		}
+		backup.FinishTime, _ = time.Parse(timestampFormat, meta.MetadataForBackupActivity.FinishTime)
+		backup.Kind = meta.Kind
+	}
+
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			backup.Size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return StoredBackup{}, errors.Wrapf(err, "failed to calculate size of %s", path)
+	}
+
+	return backup, nil
+}
+
+// Apply decides which backups to keep, separately for each backup name. Incomplete backups
+// are only deleted when force is set.
+func (p RetentionPolicy) Apply(backups []StoredBackup, force bool) []PruneDecision {
+	byName := map[string][]StoredBackup{}
+	var names []string
+	for _, backup := range backups {
+		if _, found := byName[backup.Name]; !found {
+			names = append(names, backup.Name)
+		}
+		byName[backup.Name] = append(byName[backup.Name], backup)
+	}
+	sort.Strings(names)
+
+	var decisions []PruneDecision
+	for _, name := range names {
+		decisions = append(decisions, p.applyToOneName(byName[name], force)...)
+	}
+	return decisions
+}
+
+func (p RetentionPolicy) applyToOneName(backups []StoredBackup, force bool) []PruneDecision {
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].StartTime.After(backups[j].StartTime)
+	})
+
+	decisions := make([]PruneDecision, len(backups))
+	var complete []int
+	for i, backup := range backups {
+		decisions[i] = PruneDecision{Backup: backup}
+		if backup.Complete() {
+			complete = append(complete, i)
+		} else if force {
+			decisions[i].Reason = "incomplete"
+		} else {
+			decisions[i].Keep = true
+			decisions[i].Reason = "incomplete, use --force to delete"
+		}
+	}
+
+	keep := func(index int, reason string) {
+		if !decisions[index].Keep {
+			decisions[index].Keep = true
+			decisions[index].Reason = reason
+		}
+	}
+
+	if p.KeepLast == 0 && p.KeepDaily == 0 && p.KeepWeekly == 0 && p.KeepMonthly == 0 {
+		for _, index := range complete {
+			keep(index, "within max total size")
+		}
+	}
+
+	for n, index := range complete {
+		if n < p.KeepLast {
+			keep(index, fmt.Sprintf("one of the last %d", p.KeepLast))
+		}
+	}
+
+	p.keepOnePerPeriod(decisions, complete, p.KeepDaily, "daily", func(t time.Time) string {
+		return t.Format("2006-01-02")
+	}, keep)
+	p.keepOnePerPeriod(decisions, complete, p.KeepWeekly, "weekly", func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-%d", year, week)
+	}, keep)
+	p.keepOnePerPeriod(decisions, complete, p.KeepMonthly, "monthly", func(t time.Time) string {
+		return t.Format("2006-01")
+	}, keep)
+
+	if p.MaxTotalSize > 0 {
+		var totalSize int64
+		for n, index := range complete {
+			if !decisions[index].Keep {
+				continue
+			}
+			totalSize += decisions[index].Backup.Size
+			// the most recent complete backup is always kept
+			if n > 0 && totalSize > p.MaxTotalSize {
+				decisions[index].Keep = false
+				decisions[index].Reason = fmt.Sprintf("exceeds max total size of %s", bytesize.Format(p.MaxTotalSize))
+			}
+		}
+	}
+
+	for _, index := range complete {
+		if !decisions[index].Keep && decisions[index].Reason == "" {
+			decisions[index].Reason = "not matched by retention policy"
+		}
+	}
+
+	return decisions
+}
+
+func (p RetentionPolicy) keepOnePerPeriod(decisions []PruneDecision, complete []int, periods int, label string, period func(time.Time) string, keep func(int, string)) {
+	seen := map[string]bool{}
+	for _, index := range complete {
+		if len(seen) >= periods {
+			return
+		}
+		key := period(decisions[index].Backup.StartTime.UTC())
+		if !seen[key] {
+			seen[key] = true
+			keep(index, fmt.Sprintf("%s backup for %s", label, key))
+		}
+	}
+}
+
+// LatestBackups picks, for each name, the most recent complete backup in artifactPath. If
+// timestamp is given, the complete backup taken at that time is picked instead.
+func LatestBackups(artifactPath, timestamp string) (map[string]StoredBackup, error) {
+	backups, err := FindBackups(artifactPath, "")
+	if err != nil {
+		return nil, err
+	}
+
+	latest := map[string]StoredBackup{}
+	for _, backup := range backups {
+		if !backup.Complete() {
+			continue
+		}
+		if timestamp != "" && !strings.HasSuffix(filepath.Base(backup.Path), "_"+timestamp) {
+			continue
+		}
+		if current, found := latest[backup.Name]; !found || backup.StartTime.After(current.StartTime) {
+			latest[backup.Name] = backup
+		}
+	}
+
+	return latest, nil
+}
+
+func RemoveStoredBackup(backup StoredBackup) error {
+	return errors.Wrapf(os.RemoveAll(backup.Path), "failed to delete %s", backup.Path)
+}
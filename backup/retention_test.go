@@ -0,0 +1,233 @@
+package backup_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retention", func() {
+	var artifactPath string
+
+	BeforeEach(func() {
+		var err error
+		artifactPath, err = ioutil.TempDir("", "bbr-retention")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(artifactPath)).To(Succeed())
+	})
+
+	createBackup := func(name string, startTime time.Time, finished bool, size int) string {
+		directory := filepath.Join(artifactPath, fmt.Sprintf("%s_%s", name, startTime.Format("20060102T150405Z")))
+		Expect(os.Mkdir(directory, 0700)).To(Succeed())
+
+		metadata := fmt.Sprintf("backup_activity:\n  start_time: %s\n", startTime.Format("2006/01/02 15:04:05 MST"))
+		if finished {
+			metadata += fmt.Sprintf("  finish_time: %s\n", startTime.Add(time.Minute).Format("2006/01/02 15:04:05 MST"))
+		}
+		Expect(ioutil.WriteFile(filepath.Join(directory, "metadata"), []byte(metadata), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(directory, "redis-0-redis.tar"), make([]byte, size), 0600)).To(Succeed())
+		return directory
+	}
+
+	kept := func(decisions []PruneDecision) []string {
+		var paths []string
+		for _, decision := range decisions {
+			if decision.Keep {
+				paths = append(paths, filepath.Base(decision.Backup.Path))
+			}
+		}
+		return paths
+	}
+
+	day := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2017, month, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	Describe("FindBackups", func() {
+		It("finds the backups for the name, reading their metadata and size", func() {
+			complete := createBackup("redis", day(1, 2, 3), true, 100)
+			incomplete := createBackup("redis", day(1, 3, 3), false, 50)
+			createBackup("other", day(1, 2, 3), true, 100)
+			Expect(os.Mkdir(filepath.Join(artifactPath, "not-a-backup"), 0700)).To(Succeed())
+
+			backups, err := FindBackups(artifactPath, "redis")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(backups).To(HaveLen(2))
+			Expect(backups[0].Path).To(Equal(complete))
+			Expect(backups[0].StartTime).To(BeTemporally("==", day(1, 2, 3)))
+			Expect(backups[0].Complete()).To(BeTrue())
+			Expect(backups[0].Size).To(BeNumerically(">", 100))
+			Expect(backups[1].Path).To(Equal(incomplete))
+			Expect(backups[1].Complete()).To(BeFalse())
+		})
+
+		It("finds backups with any name when no name is given", func() {
+			createBackup("redis", day(1, 2, 3), true, 100)
+			createBackup("other_deployment", day(1, 2, 3), true, 100)
+
+			backups, err := FindBackups(artifactPath, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backups).To(HaveLen(2))
+			Expect([]string{backups[0].Name, backups[1].Name}).To(ConsistOf("redis", "other_deployment"))
+		})
+
+		It("reads the kind of backup from the metadata", func() {
+			directory := createBackup("redis", day(1, 2, 3), true, 100)
+			metadata, err := ioutil.ReadFile(filepath.Join(directory, "metadata"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(directory, "metadata"), append(metadata, "kind: deployment\n"...), 0600)).To(Succeed())
+			createBackup("other", day(1, 2, 3), true, 100)
+
+			backups, err := FindBackups(artifactPath, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backups).To(HaveLen(2))
+			kinds := map[string]string{backups[0].Name: backups[0].Kind, backups[1].Name: backups[1].Kind}
+			Expect(kinds).To(Equal(map[string]string{"redis": KindDeployment, "other": ""}))
+		})
+
+		It("treats a backup without metadata as incomplete", func() {
+			Expect(os.Mkdir(filepath.Join(artifactPath, "redis_20170102T030000Z"), 0700)).To(Succeed())
+
+			backups, err := FindBackups(artifactPath, "redis")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backups).To(HaveLen(1))
+			Expect(backups[0].Complete()).To(BeFalse())
+			Expect(backups[0].StartTime).To(BeTemporally("==", day(1, 2, 3)))
+		})
+	})
+
+	Describe("Apply", func() {
+		var backups []StoredBackup
+
+		findBackups := func() {
+			var err error
+			backups, err = FindBackups(artifactPath, "")
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Context("keeping the last N backups", func() {
+			It("keeps the most recent backups of each name", func() {
+				createBackup("redis", day(1, 1, 0), true, 10)
+				createBackup("redis", day(1, 2, 0), true, 10)
+				createBackup("redis", day(1, 3, 0), true, 10)
+				createBackup("other", day(1, 1, 0), true, 10)
+				findBackups()
+
+				decisions := RetentionPolicy{KeepLast: 2}.Apply(backups, false)
+
+				Expect(decisions).To(HaveLen(4))
+				Expect(kept(decisions)).To(ConsistOf(
+					"redis_20170103T000000Z",
+					"redis_20170102T000000Z",
+					"other_20170101T000000Z",
+				))
+			})
+		})
+
+		Context("keeping daily, weekly and monthly backups", func() {
+			It("keeps the most recent backup in each period", func() {
+				createBackup("redis", day(1, 1, 0), true, 10)
+				createBackup("redis", day(1, 30, 0), true, 10)
+				createBackup("redis", day(2, 14, 0), true, 10)
+				createBackup("redis", day(2, 20, 0), true, 10)
+				createBackup("redis", day(2, 21, 0), true, 10)
+				createBackup("redis", day(2, 21, 12), true, 10)
+				findBackups()
+
+				decisions := RetentionPolicy{KeepDaily: 2, KeepWeekly: 3, KeepMonthly: 2}.Apply(backups, false)
+
+				Expect(kept(decisions)).To(ConsistOf(
+					"redis_20170221T120000Z",
+					"redis_20170220T000000Z",
+					"redis_20170214T000000Z",
+					"redis_20170130T000000Z",
+				))
+			})
+		})
+
+		Context("with a maximum total size", func() {
+			It("deletes the oldest backups which exceed it", func() {
+				createBackup("redis", day(1, 1, 0), true, 1000)
+				createBackup("redis", day(1, 2, 0), true, 1000)
+				createBackup("redis", day(1, 3, 0), true, 1000)
+				findBackups()
+
+				decisions := RetentionPolicy{MaxTotalSize: 2500}.Apply(backups, false)
+
+				Expect(kept(decisions)).To(ConsistOf("redis_20170103T000000Z", "redis_20170102T000000Z"))
+			})
+
+			It("always keeps the most recent backup", func() {
+				createBackup("redis", day(1, 1, 0), true, 1000)
+				findBackups()
+
+				decisions := RetentionPolicy{MaxTotalSize: 10}.Apply(backups, false)
+
+				Expect(kept(decisions)).To(ConsistOf("redis_20170101T000000Z"))
+			})
+		})
+
+		Context("with incomplete backups", func() {
+			BeforeEach(func() {
+				createBackup("redis", day(1, 1, 0), false, 10)
+				createBackup("redis", day(1, 2, 0), true, 10)
+				findBackups()
+			})
+
+			It("keeps them", func() {
+				decisions := RetentionPolicy{KeepLast: 1}.Apply(backups, false)
+
+				Expect(kept(decisions)).To(ConsistOf("redis_20170102T000000Z", "redis_20170101T000000Z"))
+				Expect(decisions[1].Reason).To(ContainSubstring("incomplete"))
+			})
+
+			It("deletes them when forced", func() {
+				decisions := RetentionPolicy{KeepLast: 1}.Apply(backups, true)
+
+				Expect(kept(decisions)).To(ConsistOf("redis_20170102T000000Z"))
+			})
+		})
+	})
+
+	Describe("LatestBackups", func() {
+		BeforeEach(func() {
+			createBackup("redis", day(1, 1, 0), true, 10)
+			createBackup("redis", day(1, 2, 0), true, 10)
+			createBackup("redis", day(1, 3, 0), false, 10)
+			createBackup("other", day(1, 1, 0), true, 10)
+		})
+
+		It("picks the most recent complete backup of each name", func() {
+			backups, err := LatestBackups(artifactPath, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(backups).To(HaveLen(2))
+			Expect(filepath.Base(backups["redis"].Path)).To(Equal("redis_20170102T000000Z"))
+			Expect(filepath.Base(backups["other"].Path)).To(Equal("other_20170101T000000Z"))
+		})
+
+		It("picks the backups taken at the given timestamp", func() {
+			backups, err := LatestBackups(artifactPath, "20170101T000000Z")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(backups).To(HaveLen(2))
+			Expect(filepath.Base(backups["redis"].Path)).To(Equal("redis_20170101T000000Z"))
+		})
+
+		It("does not pick incomplete backups", func() {
+			backups, err := LatestBackups(artifactPath, "20170103T000000Z")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backups).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,52 @@
+package backup
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+)
+
+// Storage holds the files which make up a single backup: the artifact tars, the metadata
+// file and the deployment manifest. Names are relative to the root of the backup.
+type Storage interface {
+	Create(name string) (io.WriteCloser, error)
+	CreateDirectory(name string) error
+	Open(name string) (io.ReadCloser, error)
+	Exists(name string) (bool, error)
+	Size(name string) (string, error)
+	Location() string
+}
+
+func readFile(storage Storage, name string) ([]byte, error) {
+	reader, err := storage.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return ioutil.ReadAll(reader)
+}
+
+func writeFile(storage Storage, name string, contents []byte) error {
+	writer, err := storage.Create(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := writer.Write(contents); err != nil {
+		return closeWithError(writer, errors.Wrapf(err, "failed to write %s", name))
+	}
+	return writer.Close()
+}
+
+// closeWithError abandons a file written to storage which allows that, such as an upload to a
+// bucket, and otherwise just closes it. It returns the given error.
+func closeWithError(writer io.WriteCloser, err error) error {
+	if abortable, ok := writer.(orchestrator.AbortableWriter); ok {
+		return abortable.CloseWithError(err)
+	}
+	writer.Close()
+	return err
+}
@@ -0,0 +1,96 @@
+package backup
+
+import (
+	"sort"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+)
+
+type ArtifactVerification struct {
+	Name       string
+	Missing    []string
+	Extra      []string
+	Mismatched []string
+	Err        error
+}
+
+func (v ArtifactVerification) Valid() bool {
+	return v.Err == nil && len(v.Missing) == 0 && len(v.Extra) == 0 && len(v.Mismatched) == 0
+}
+
+type Verification struct {
+	Location  string
+	Complete  bool
+	Artifacts []ArtifactVerification
+}
+
+func (v Verification) Valid() bool {
+	if !v.Complete {
+		return false
+	}
+	for _, artifact := range v.Artifacts {
+		if !artifact.Valid() {
+			return false
+		}
+	}
+	return true
+}
+
+// Verify recalculates the checksum of every artifact listed in the metadata, carrying on
+// past failures so that every problem with the backup is reported.
+func (backupDirectory *BackupDirectory) Verify() (Verification, error) {
+	verification := Verification{Location: backupDirectory.storage.Location()}
+
+	meta, err := readMetadata(backupDirectory.storage, metadataFilename)
+	if err != nil {
+		return verification, backupDirectory.logAndReturn(err, "Error reading metadata from %s", backupDirectory.metadataLocation())
+	}
+	verification.Complete = meta.MetadataForBackupActivity.FinishTime != ""
+
+	if _, err := backupDirectory.cipherKey(); err != nil {
+		return verification, backupDirectory.logAndReturn(err, "Error loading encryption key")
+	}
+
+	for _, inst := range meta.MetadataForEachInstance {
+		for _, artifact := range inst.Artifacts {
+			verification.Artifacts = append(verification.Artifacts,
+				backupDirectory.verifyArtifact(makeDefaultArtifactIdentifier(artifact, inst), artifact.Checksum))
+		}
+	}
+
+	for _, artifact := range meta.MetadataForEachArtifact {
+		verification.Artifacts = append(verification.Artifacts,
+			backupDirectory.verifyArtifact(makeCustomArtifactIdentifier(artifact), artifact.Checksum))
+	}
+
+	return verification, nil
+}
+
+func (backupDirectory *BackupDirectory) verifyArtifact(artifactIdentifier orchestrator.ArtifactIdentifier, expected orchestrator.BackupChecksum) ArtifactVerification {
+	verification := ArtifactVerification{Name: fileName(artifactIdentifier)}
+
+	actual, err := backupDirectory.CalculateChecksum(artifactIdentifier)
+	if err != nil {
+		verification.Err = err
+		return verification
+	}
+
+	for file, checksum := range expected {
+		actualChecksum, found := actual[file]
+		if !found {
+			verification.Missing = append(verification.Missing, file)
+		} else if actualChecksum != checksum {
+			verification.Mismatched = append(verification.Mismatched, file)
+		}
+	}
+	for file := range actual {
+		if _, found := expected[file]; !found {
+			verification.Extra = append(verification.Extra, file)
+		}
+	}
+
+	sort.Strings(verification.Missing)
+	sort.Strings(verification.Extra)
+	sort.Strings(verification.Mismatched)
+	return verification
+}
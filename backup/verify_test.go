@@ -0,0 +1,119 @@
+package backup_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/config"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Verify", func() {
+	var backupName string
+	var logger = boshlog.NewWriterLogger(boshlog.LevelDebug, GinkgoWriter)
+	var instanceArtifact, customArtifact *fakes.FakeBackupArtifact
+	var finished bool
+
+	writeArtifact := func(artifact *fakes.FakeBackupArtifact, files map[string]string) {
+		backup, err := BackupDirectoryManager{}.Open(backupName, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		writer, err := backup.CreateArtifact(artifact)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = writer.Write(createTarWithContents(files))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		backupName = fmt.Sprintf("my-verified-redis-%d_20151021T010203Z", config.GinkgoConfig.ParallelNode)
+		finished = true
+
+		instanceArtifact = new(fakes.FakeBackupArtifact)
+		instanceArtifact.InstanceNameReturns("redis-server")
+		instanceArtifact.InstanceIndexReturns("0")
+		instanceArtifact.NameReturns("redis")
+
+		customArtifact = new(fakes.FakeBackupArtifact)
+		customArtifact.HasCustomNameReturns(true)
+		customArtifact.NameReturns("shared-redis")
+	})
+
+	JustBeforeEach(func() {
+		backup, err := BackupDirectoryManager{}.Create("", backupName, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backup.CreateMetadataFileWithStartTime(time.Now())).To(Succeed())
+
+		for _, artifact := range []*fakes.FakeBackupArtifact{instanceArtifact, customArtifact} {
+			writeArtifact(artifact, map[string]string{"dump.rdb": "data", "config": "config"})
+			checksum, err := backup.CalculateChecksum(artifact)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.AddChecksum(artifact, checksum)).To(Succeed())
+		}
+
+		if finished {
+			Expect(backup.AddFinishTime(time.Now())).To(Succeed())
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(backupName)).To(Succeed())
+	})
+
+	It("reports an untouched backup as valid", func() {
+		verification, err := BackupDirectoryManager{}.Verify(backupName, logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verification.Valid()).To(BeTrue())
+		Expect(verification.Artifacts).To(HaveLen(2))
+		Expect(verification.Artifacts[0].Name).To(Equal("redis-server-0-redis.tar"))
+		Expect(verification.Artifacts[1].Name).To(Equal("shared-redis.tar"))
+	})
+
+	It("reports every missing, extra and mismatched file in every artifact", func() {
+		writeArtifact(instanceArtifact, map[string]string{"dump.rdb": "corrupted", "extra": "extra"})
+		Expect(os.Remove(backupName + "/shared-redis.tar")).To(Succeed())
+
+		verification, err := BackupDirectoryManager{}.Verify(backupName, logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verification.Valid()).To(BeFalse())
+
+		Expect(verification.Artifacts[0].Valid()).To(BeFalse())
+		Expect(verification.Artifacts[0].Mismatched).To(Equal([]string{"dump.rdb"}))
+		Expect(verification.Artifacts[0].Missing).To(Equal([]string{"config"}))
+		Expect(verification.Artifacts[0].Extra).To(Equal([]string{"extra"}))
+
+		Expect(verification.Artifacts[1].Valid()).To(BeFalse())
+		Expect(verification.Artifacts[1].Err).To(HaveOccurred())
+	})
+
+	Context("when the backup has no finish time", func() {
+		BeforeEach(func() {
+			finished = false
+		})
+
+		It("reports the backup as incomplete", func() {
+			verification, err := BackupDirectoryManager{}.Verify(backupName, logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verification.Complete).To(BeFalse())
+			Expect(verification.Valid()).To(BeFalse())
+		})
+	})
+
+	Context("when the metadata cannot be read", func() {
+		It("returns an error", func() {
+			Expect(ioutil.WriteFile(backupName+"/metadata", []byte("not: [yaml"), 0600)).To(Succeed())
+
+			_, err := BackupDirectoryManager{}.Verify(backupName, logger)
+			Expect(err).To(MatchError(ContainSubstring("failed to unmarshal metadata")))
+		})
+	})
+})
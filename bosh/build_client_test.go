@@ -8,6 +8,9 @@ import (
 
 	"io/ioutil"
 
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
@@ -45,7 +48,7 @@ var _ = Describe("BuildClient", func() {
 				mockbosh.Manifest(deploymentName).RespondsWith([]byte("manifest contents")),
 			)
 
-			client, err := BuildClient(director.URL, username, password, caCert, logger)
+			client, err := BuildClient(director.URL, username, password, caCert, ssh.NewSshRemoteRunner, instance.NewJobFinder(logger), logger)
 
 			Expect(err).NotTo(HaveOccurred())
 			manifest, err := client.GetManifest(deploymentName)
@@ -70,7 +73,7 @@ var _ = Describe("BuildClient", func() {
 				mockbosh.Manifest(deploymentName).RespondsWith([]byte("manifest contents")),
 			)
 
-			client, err := BuildClient(director.URL, username, password, caCert, logger)
+			client, err := BuildClient(director.URL, username, password, caCert, ssh.NewSshRemoteRunner, instance.NewJobFinder(logger), logger)
 
 			Expect(err).NotTo(HaveOccurred())
 			manifest, err := client.GetManifest(deploymentName)
@@ -85,7 +88,7 @@ var _ = Describe("BuildClient", func() {
 			director.VerifyAndMock(
 				mockbosh.Info().WithAuthTypeUAA(""),
 			)
-			_, err := BuildClient(director.URL, username, password, caCert, logger)
+			_, err := BuildClient(director.URL, username, password, caCert, ssh.NewSshRemoteRunner, instance.NewJobFinder(logger), logger)
 
 			Expect(err).To(MatchError(ContainSubstring("invalid UAA URL")))
 
@@ -98,7 +101,7 @@ var _ = Describe("BuildClient", func() {
 		caCertPath := "-----BEGIN"
 		basicAuthDirectorUrl := director.URL
 
-		_, err := BuildClient(basicAuthDirectorUrl, username, password, caCertPath, logger)
+		_, err := BuildClient(basicAuthDirectorUrl, username, password, caCertPath, ssh.NewSshRemoteRunner, instance.NewJobFinder(logger), logger)
 		Expect(err).To(MatchError(ContainSubstring("Missing PEM block")))
 	})
 
@@ -108,7 +111,7 @@ var _ = Describe("BuildClient", func() {
 		caCertPath := ""
 		basicAuthDirectorUrl := ""
 
-		_, err := BuildClient(basicAuthDirectorUrl, username, password, caCertPath, logger)
+		_, err := BuildClient(basicAuthDirectorUrl, username, password, caCertPath, ssh.NewSshRemoteRunner, instance.NewJobFinder(logger), logger)
 		Expect(err).To(MatchError(ContainSubstring("invalid bosh URL")))
 	})
 
@@ -120,7 +123,7 @@ var _ = Describe("BuildClient", func() {
 			mockbosh.Info().Fails("fooo!"),
 		)
 
-		_, err := BuildClient(director.URL, username, password, caCert, logger)
+		_, err := BuildClient(director.URL, username, password, caCert, ssh.NewSshRemoteRunner, instance.NewJobFinder(logger), logger)
 		Expect(err).To(MatchError(ContainSubstring("bosh director unreachable or unhealthy")))
 	})
 
@@ -16,7 +16,7 @@ import (
 
 //go:generate counterfeiter -o fakes/fake_bosh_client.go . BoshClient
 type BoshClient interface {
-	FindInstances(deploymentName string) ([]orchestrator.Instance, error)
+	FindInstances(deploymentName string, selection orchestrator.Selection) ([]orchestrator.Instance, error)
 	GetManifest(deploymentName string) (string, error)
 }
 
@@ -53,7 +53,7 @@ type Logger interface {
 	Error(tag, msg string, args ...interface{})
 }
 
-func (c Client) FindInstances(deploymentName string) ([]orchestrator.Instance, error) {
+func (c Client) FindInstances(deploymentName string, selection orchestrator.Selection) ([]orchestrator.Instance, error) {
 	deployment, err := c.Director.FindDeployment(deploymentName)
 	if err != nil {
 		return nil, errors.Wrap(err, "couldn't find deployment "+deploymentName)
@@ -83,80 +83,94 @@ func (c Client) FindInstances(deploymentName string) ([]orchestrator.Instance, e
 		return nil, errors.Wrap(err, "couldn't generate release mapping for deployment "+deploymentName)
 	}
 
+instanceGroups:
 	for _, instanceGroupName := range uniqueInstanceGroupNamesFromVMs(vms) {
-		c.Logger.Debug("bbr", "Setting up SSH for job %s", instanceGroupName)
-
-		allVmInstances, err := director.NewAllOrInstanceGroupOrInstanceSlugFromString(instanceGroupName)
+		selectedSlugs, err := selectedInstanceSlugs(instanceGroupName, vms, selection)
 		if err != nil {
 			cleanupAlreadyMadeConnections(deployment, slugs, sshOpts)
 			return nil, errors.Wrap(err, "invalid instance group name: "+instanceGroupName)
 		}
 
-		sshRes, err := deployment.SetUpSSH(allVmInstances, sshOpts)
-		if err != nil {
-			cleanupAlreadyMadeConnections(deployment, slugs, sshOpts)
-			return nil, errors.Wrap(err, "failed to set up ssh")
+		if len(selectedSlugs) == 0 {
+			c.Logger.Debug("bbr", "Skipping job %s, no instances are selected", instanceGroupName)
+			continue
 		}
-		slugs = append(slugs, allVmInstances)
-
-		for _, host := range sshRes.Hosts {
-
-			var err error
-
-			c.Logger.Debug("bbr", "Attempting to SSH onto %s, %s", host.Host, host.IndexOrID)
-
-			hostPublicKey, _, _, _, err := gossh.ParseAuthorizedKey([]byte(host.HostPublicKey))
-			if err != nil {
-				return nil, errors.Wrap(err, "ssh.NewConnection.ParseAuthorizedKey failed")
-			}
-
-			remoteRunner, err := c.RemoteRunnerFactory(host.Host, host.Username, privateKey, gossh.FixedHostKey(hostPublicKey), []string{hostPublicKey.Type()}, c.Logger)
-			if err != nil {
-				cleanupAlreadyMadeConnections(deployment, slugs, sshOpts)
-				return nil, errors.Wrap(err, "failed to connect using ssh")
-			}
-
-			instanceIdentifier := instance.InstanceIdentifier{InstanceGroupName: instanceGroupName, InstanceId: host.IndexOrID}
-
-			isWindows, err := remoteRunner.IsWindows()
-			if err != nil {
-				cleanupAlreadyMadeConnections(deployment, slugs, sshOpts)
-				return nil, errors.Wrap(err, "failed to check os")
-			}
-
-			if isWindows {
-				c.Logger.Warn("bbr", "skipping Windows instance %s/%s", instanceGroupName, host.IndexOrID)
-				continue
-			}
 
-			jobs, err := c.jobFinder.FindJobs(instanceIdentifier, remoteRunner, releaseMapping)
-			if err != nil {
-				cleanupAlreadyMadeConnections(deployment, slugs, sshOpts)
-				return nil, errors.Wrap(err, "couldn't find jobs")
-			}
+		for _, selectedSlug := range selectedSlugs {
+			c.Logger.Debug("bbr", "Setting up SSH for job %s", instanceGroupName)
 
-			vmIndex, err := findInstanceIndexById(vms, host.IndexOrID)
+			sshRes, err := deployment.SetUpSSH(selectedSlug, sshOpts)
 			if err != nil {
 				cleanupAlreadyMadeConnections(deployment, slugs, sshOpts)
-				return nil, errors.Wrap(err, "couldn't find instance index")
+				return nil, errors.Wrap(err, "failed to set up ssh")
 			}
-
-			instances = append(instances,
-				NewBoshDeployedInstance(
-					instanceGroupName,
-					vmIndex,
-					host.IndexOrID,
-					remoteRunner,
-					deployment,
-					false,
-					c.Logger,
-					jobs,
-				),
-			)
-
-			if len(jobs) == 0 {
-				c.Logger.Debug("bbr", "no scripts found on instance %s/%s, skipping rest of the instances for %s", instanceGroupName, host.IndexOrID, instanceGroupName)
-				break
+			slugs = append(slugs, selectedSlug)
+
+			for _, host := range sshRes.Hosts {
+
+				var err error
+
+				c.Logger.Debug("bbr", "Attempting to SSH onto %s, %s", host.Host, host.IndexOrID)
+
+				hostPublicKey, _, _, _, err := gossh.ParseAuthorizedKey([]byte(host.HostPublicKey))
+				if err != nil {
+					return nil, errors.Wrap(err, "ssh.NewConnection.ParseAuthorizedKey failed")
+				}
+
+				remoteRunner, err := c.RemoteRunnerFactory(host.Host, host.Username, privateKey, gossh.FixedHostKey(hostPublicKey), []string{hostPublicKey.Type()}, c.Logger)
+				if err != nil {
+					cleanupAlreadyMadeConnections(deployment, slugs, sshOpts)
+					return nil, errors.Wrap(err, "failed to connect using ssh")
+				}
+
+				isWindows, err := remoteRunner.IsWindows()
+				if err != nil {
+					cleanupAlreadyMadeConnections(deployment, slugs, sshOpts)
+					return nil, errors.Wrap(err, "failed to check os")
+				}
+
+				if isWindows {
+					c.Logger.Warn("bbr", "skipping Windows instance %s/%s", instanceGroupName, host.IndexOrID)
+					continue
+				}
+
+				vmIndex, err := findInstanceIndexById(vms, host.IndexOrID)
+				if err != nil {
+					cleanupAlreadyMadeConnections(deployment, slugs, sshOpts)
+					return nil, errors.Wrap(err, "couldn't find instance index")
+				}
+
+				instanceIdentifier := instance.InstanceIdentifier{
+					DeploymentName:    deploymentName,
+					InstanceGroupName: instanceGroupName,
+					InstanceId:        host.IndexOrID,
+					InstanceIndex:     vmIndex,
+				}
+
+				jobs, err := c.jobFinder.FindJobs(instanceIdentifier, remoteRunner, releaseMapping)
+				if err != nil {
+					cleanupAlreadyMadeConnections(deployment, slugs, sshOpts)
+					return nil, errors.Wrap(err, "couldn't find jobs")
+				}
+				jobs = selection.FilterJobs(jobs)
+
+				instances = append(instances,
+					NewBoshDeployedInstance(
+						instanceGroupName,
+						vmIndex,
+						host.IndexOrID,
+						remoteRunner,
+						deployment,
+						false,
+						c.Logger,
+						jobs,
+					),
+				)
+
+				if len(jobs) == 0 {
+					c.Logger.Debug("bbr", "no scripts found on instance %s/%s, skipping rest of the instances for %s", instanceGroupName, host.IndexOrID, instanceGroupName)
+					continue instanceGroups
+				}
 			}
 		}
 	}
@@ -172,6 +186,43 @@ func (c Client) GetManifest(deploymentName string) (string, error) {
 	return deployment.Manifest()
 }
 
+// selectedInstanceSlugs returns a single slug for the whole instance group when all of its
+// instances are selected, so that SSH is set up the same way as without a selection.
+func selectedInstanceSlugs(instanceGroupName string, vms []director.VMInfo, selection orchestrator.Selection) ([]director.AllOrInstanceGroupOrInstanceSlug, error) {
+	if !selection.IncludesInstanceGroup(instanceGroupName) {
+		return nil, nil
+	}
+
+	var instanceSlugs []director.AllOrInstanceGroupOrInstanceSlug
+	allSelected := true
+	for _, vm := range vms {
+		if vm.JobName != instanceGroupName {
+			continue
+		}
+
+		index := ""
+		if vm.Index != nil {
+			index = strconv.Itoa(*vm.Index)
+		}
+
+		if selection.IncludesInstance(instanceGroupName, vm.ID, index, vm.AZ) {
+			instanceSlugs = append(instanceSlugs, director.NewAllOrInstanceGroupOrInstanceSlug(instanceGroupName, vm.ID))
+		} else {
+			allSelected = false
+		}
+	}
+
+	if !allSelected {
+		return instanceSlugs, nil
+	}
+
+	allVmInstances, err := director.NewAllOrInstanceGroupOrInstanceSlugFromString(instanceGroupName)
+	if err != nil {
+		return nil, err
+	}
+	return []director.AllOrInstanceGroupOrInstanceSlug{allVmInstances}, nil
+}
+
 func uniqueInstanceGroupNamesFromVMs(vms []director.VMInfo) []string {
 	var jobs []string
 	for _, vm := range vms {
@@ -2,6 +2,7 @@ package bosh_test
 
 import (
 	"log"
+	"reflect"
 
 	"bytes"
 	"io"
@@ -74,10 +75,15 @@ var _ = Describe("Director", func() {
 			actualInstances []orchestrator.Instance
 			actualError     error
 			expectedJobs    orchestrator.Jobs
+			selection       orchestrator.Selection
 		)
 
+		BeforeEach(func() {
+			selection = orchestrator.Selection{}
+		})
+
 		JustBeforeEach(func() {
-			actualInstances, actualError = b.FindInstances(deploymentName)
+			actualInstances, actualError = b.FindInstances(deploymentName, selection)
 		})
 
 		Context("finds instances for the deployment", func() {
@@ -331,6 +337,62 @@ var _ = Describe("Director", func() {
 			})
 		})
 
+		Context("finds the selected instances and jobs", func() {
+			var redisJob, consulJob orchestrator.Job
+
+			BeforeEach(func() {
+				boshDirector.FindDeploymentReturns(boshDeployment, nil)
+				boshDeployment.VMInfosReturns([]director.VMInfo{
+					{JobName: "job1", ID: "id1", Index: newIndex(0), AZ: "z1"},
+					{JobName: "job1", ID: "id2", Index: newIndex(1), AZ: "z2"},
+					{JobName: "job2", ID: "id3", Index: newIndex(0), AZ: "z1"},
+					{JobName: "job3", ID: "id4", Index: newIndex(0), AZ: "z1"},
+				}, nil)
+				optsGenerator.Returns(stubbedSshOpts, "private_key", nil)
+				boshDeployment.SetUpSSHStub = func(slug director.AllOrInstanceGroupOrInstanceSlug, opts director.SSHOpts) (director.SSHResult, error) {
+					id := "id3"
+					if reflect.DeepEqual(slug, director.NewAllOrInstanceGroupOrInstanceSlug("job1", "id1")) {
+						id = "id1"
+					}
+					return director.SSHResult{Hosts: []director.Host{
+						{Username: "username", Host: "hostname-" + id, IndexOrID: id, HostPublicKey: hostsPublicKey},
+					}}, nil
+				}
+				remoteRunnerFactory.Returns(remoteRunner, nil)
+
+				redisJob = instance.NewJob(remoteRunner, "", boshLogger, "",
+					instance.BackupAndRestoreScripts{"/var/vcap/jobs/redis/bin/bbr/backup"}, instance.Metadata{})
+				consulJob = instance.NewJob(remoteRunner, "", boshLogger, "",
+					instance.BackupAndRestoreScripts{"/var/vcap/jobs/consul_agent/bin/bbr/backup"}, instance.Metadata{})
+				fakeJobFinder.FindJobsReturns(orchestrator.Jobs{redisJob, consulJob}, nil)
+
+				releaseMappingFinder.Returns(releaseMapping, nil)
+
+				selection = orchestrator.Selection{
+					ExcludeInstanceGroups: []string{"job3"},
+					AZs:                   []string{"z1"},
+					ExcludeJobs:           []string{"consul_agent"},
+				}
+			})
+
+			It("only sets up ssh for the selected instances", func() {
+				Expect(actualError).NotTo(HaveOccurred())
+				Expect(boshDeployment.SetUpSSHCallCount()).To(Equal(2))
+
+				slug, _ := boshDeployment.SetUpSSHArgsForCall(0)
+				Expect(slug).To(Equal(director.NewAllOrInstanceGroupOrInstanceSlug("job1", "id1")))
+				slug, _ = boshDeployment.SetUpSSHArgsForCall(1)
+				Expect(slug).To(Equal(director.NewAllOrInstanceGroupOrInstanceSlug("job2", "")))
+			})
+
+			It("only includes the selected jobs", func() {
+				Expect(actualInstances).To(Equal([]orchestrator.Instance{
+					bosh.NewBoshDeployedInstance("job1", "0", "id1", remoteRunner, boshDeployment, false, boshLogger, orchestrator.Jobs{redisJob}),
+					bosh.NewBoshDeployedInstance("job2", "0", "id3", remoteRunner, boshDeployment, false, boshLogger, orchestrator.Jobs{redisJob}),
+				}))
+			})
+		})
+
 		Context("finds instances for the deployment, having multiple instances, including a windows vm, in an instance group", func() {
 			var instance0Jobs orchestrator.Jobs
 
@@ -598,17 +660,17 @@ var _ = Describe("Director", func() {
 				Expect(fakeJobFinder.FindJobsCallCount()).To(Equal(3))
 
 				actualInstanceIdentifier, actualRemoteRunner, actualReleaseMapping := fakeJobFinder.FindJobsArgsForCall(0)
-				Expect(actualInstanceIdentifier).To(Equal(instance.InstanceIdentifier{InstanceGroupName: "job1", InstanceId: "id1"}))
+				Expect(actualInstanceIdentifier).To(Equal(instance.InstanceIdentifier{DeploymentName: deploymentName, InstanceGroupName: "job1", InstanceId: "id1", InstanceIndex: "0"}))
 				Expect(actualRemoteRunner).To(Equal(remoteRunner))
 				Expect(actualReleaseMapping).To(Equal(releaseMapping))
 
 				actualInstanceIdentifier, actualRemoteRunner, actualReleaseMapping = fakeJobFinder.FindJobsArgsForCall(1)
-				Expect(actualInstanceIdentifier).To(Equal(instance.InstanceIdentifier{InstanceGroupName: "job2", InstanceId: "id3"}))
+				Expect(actualInstanceIdentifier).To(Equal(instance.InstanceIdentifier{DeploymentName: deploymentName, InstanceGroupName: "job2", InstanceId: "id3", InstanceIndex: "0"}))
 				Expect(actualRemoteRunner).To(Equal(remoteRunner))
 				Expect(actualReleaseMapping).To(Equal(releaseMapping))
 
 				actualInstanceIdentifier, actualRemoteRunner, actualReleaseMapping = fakeJobFinder.FindJobsArgsForCall(2)
-				Expect(actualInstanceIdentifier).To(Equal(instance.InstanceIdentifier{InstanceGroupName: "job2", InstanceId: "id4"}))
+				Expect(actualInstanceIdentifier).To(Equal(instance.InstanceIdentifier{DeploymentName: deploymentName, InstanceGroupName: "job2", InstanceId: "id4", InstanceIndex: "1"}))
 				Expect(actualRemoteRunner).To(Equal(remoteRunner))
 				Expect(actualReleaseMapping).To(Equal(releaseMapping))
 			})
@@ -62,7 +62,20 @@ func (i *BoshDeployedInstance) CleanupPrevious() error {
 	return orchestrator.ConvertErrors(errs)
 }
 
+// SSHUser is the user which was set up on the instance for bbr to connect as
+func (i *BoshDeployedInstance) SSHUser() string {
+	return i.ConnectedUsername()
+}
+
+// RemoveSSHUser removes a user which a previous run of bbr set up on the instance
+func (i *BoshDeployedInstance) RemoveSSHUser(username string) error {
+	i.Logger.Debug("bbr", "Removing SSH user %s on instance %s %s", username, i.Name(), i.ID())
+	return i.Deployment.CleanUpSSH(director.NewAllOrInstanceGroupOrInstanceSlug(i.Name(), i.ID()), director.SSHOpts{Username: username})
+}
+
 func (i *BoshDeployedInstance) cleanupSSHConnections() error {
+	i.CloseConnection()
+
 	i.Logger.Debug("bbr", "Cleaning up SSH connection on instance %s %s", i.Name(), i.ID())
 	return i.Deployment.CleanUpSSH(director.NewAllOrInstanceGroupOrInstanceSlug(i.Name(), i.ID()), director.SSHOpts{Username: i.ConnectedUsername()})
 }
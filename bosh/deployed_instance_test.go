@@ -74,6 +74,10 @@ var _ = Describe("BoshDeployedInstance", func() {
 					Username: "sshUsername",
 				}))
 			})
+
+			It("closes the ssh connection to the instance", func() {
+				Expect(remoteRunner.CloseCallCount()).To(Equal(1))
+			})
 		})
 
 		Context("when the backup artifact directory was not created this time", func() {
@@ -170,6 +174,10 @@ var _ = Describe("BoshDeployedInstance", func() {
 					Username: "sshUsername",
 				}))
 			})
+
+			It("closes the ssh connection to the instance", func() {
+				Expect(remoteRunner.CloseCallCount()).To(Equal(1))
+			})
 		})
 
 		Context("when the backup artifact directory was not created this time", func() {
@@ -244,4 +252,27 @@ var _ = Describe("BoshDeployedInstance", func() {
 			})
 		})
 	})
+
+	Describe("SSHUser", func() {
+		It("is the user which bbr connected as", func() {
+			Expect(backuperInstance.SSHUser()).To(Equal("sshUsername"))
+		})
+	})
+
+	Describe("RemoveSSHUser", func() {
+		It("deletes the user's session from the deployment, without closing the connection", func() {
+			Expect(backuperInstance.RemoveSSHUser("bbr-previous")).To(Succeed())
+
+			Expect(boshDeployment.CleanUpSSHCallCount()).To(Equal(1))
+			slug, sshOpts := boshDeployment.CleanUpSSHArgsForCall(0)
+			Expect(slug).To(Equal(director.NewAllOrInstanceGroupOrInstanceSlug(jobName, jobID)))
+			Expect(sshOpts).To(Equal(director.SSHOpts{Username: "bbr-previous"}))
+			Expect(remoteRunner.CloseCallCount()).To(BeZero())
+		})
+
+		It("fails if the session cannot be deleted", func() {
+			boshDeployment.CleanUpSSHReturns(errors.New("cleanup failed"))
+			Expect(backuperInstance.RemoveSSHUser("bbr-previous")).To(MatchError("cleanup failed"))
+		})
+	})
 })
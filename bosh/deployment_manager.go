@@ -5,19 +5,20 @@ import (
 	"github.com/pkg/errors"
 )
 
-func NewDeploymentManager(boshDirector BoshClient, logger Logger, downloadManifest bool) *DeploymentManager {
-	return &DeploymentManager{BoshClient: boshDirector, Logger: logger, downloadManifest: downloadManifest}
+func NewDeploymentManager(boshDirector BoshClient, logger Logger, downloadManifest bool, selection orchestrator.Selection) *DeploymentManager {
+	return &DeploymentManager{BoshClient: boshDirector, Logger: logger, downloadManifest: downloadManifest, selection: selection}
 }
 
 type DeploymentManager struct {
 	BoshClient
 	Logger
 	downloadManifest bool
+	selection        orchestrator.Selection
 }
 
 func (b *DeploymentManager) Find(deploymentName string) (orchestrator.Deployment, error) {
-	instances, err := b.FindInstances(deploymentName)
-	return orchestrator.NewDeployment(b.Logger, instances), errors.Wrap(err, "failed to find instances for deployment "+deploymentName)
+	instances, err := b.FindInstances(deploymentName, b.selection)
+	return orchestrator.NewDeployment(deploymentName, b.Logger, instances), errors.Wrap(err, "failed to find instances for deployment "+deploymentName)
 }
 
 func (b *DeploymentManager) SaveManifest(deploymentName string, backup orchestrator.Backup) error {
@@ -18,6 +18,7 @@ var _ = Describe("DeploymentManager", func() {
 	var deploymentName = "brownie"
 	var fakeBackup *orchestrator_fakes.FakeBackup
 	var manifest string
+	var selection = orchestrator.Selection{Jobs: []string{"redis"}}
 
 	var deploymentManager *bosh.DeploymentManager
 	BeforeEach(func() {
@@ -25,7 +26,7 @@ var _ = Describe("DeploymentManager", func() {
 		logger = new(fakes.FakeLogger)
 	})
 	JustBeforeEach(func() {
-		deploymentManager = bosh.NewDeploymentManager(boshClient, logger, true)
+		deploymentManager = bosh.NewDeploymentManager(boshClient, logger, true, selection)
 	})
 
 	Context("Find", func() {
@@ -39,12 +40,14 @@ var _ = Describe("DeploymentManager", func() {
 		JustBeforeEach(func() {
 			deployment, findError = deploymentManager.Find(deploymentName)
 		})
-		It("asks the bosh director for instances", func() {
+		It("asks the bosh director for the selected instances", func() {
 			Expect(boshClient.FindInstancesCallCount()).To(Equal(1))
-			Expect(boshClient.FindInstancesArgsForCall(0)).To(Equal(deploymentName))
+			actualDeploymentName, actualSelection := boshClient.FindInstancesArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
+			Expect(actualSelection).To(Equal(selection))
 		})
 		It("returns the deployment manager with instances", func() {
-			Expect(deployment).To(Equal(orchestrator.NewDeployment(logger, instances)))
+			Expect(deployment).To(Equal(orchestrator.NewDeployment(deploymentName, logger, instances)))
 		})
 
 		Context("error finding instances", func() {
@@ -140,7 +143,7 @@ var _ = Describe("DeploymentManager", func() {
 			})
 
 			JustBeforeEach(func() {
-				deploymentManager = bosh.NewDeploymentManager(boshClient, logger, false)
+				deploymentManager = bosh.NewDeploymentManager(boshClient, logger, false, selection)
 				saveManifestError = deploymentManager.SaveManifest(deploymentName, fakeBackup)
 			})
 
@@ -9,10 +9,11 @@ import (
 )
 
 type FakeBoshClient struct {
-	FindInstancesStub        func(deploymentName string) ([]orchestrator.Instance, error)
+	FindInstancesStub        func(deploymentName string, selection orchestrator.Selection) ([]orchestrator.Instance, error)
 	findInstancesMutex       sync.RWMutex
 	findInstancesArgsForCall []struct {
 		deploymentName string
+		selection      orchestrator.Selection
 	}
 	findInstancesReturns struct {
 		result1 []orchestrator.Instance
@@ -39,16 +40,17 @@ type FakeBoshClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeBoshClient) FindInstances(deploymentName string) ([]orchestrator.Instance, error) {
+func (fake *FakeBoshClient) FindInstances(deploymentName string, selection orchestrator.Selection) ([]orchestrator.Instance, error) {
 	fake.findInstancesMutex.Lock()
 	ret, specificReturn := fake.findInstancesReturnsOnCall[len(fake.findInstancesArgsForCall)]
 	fake.findInstancesArgsForCall = append(fake.findInstancesArgsForCall, struct {
 		deploymentName string
-	}{deploymentName})
-	fake.recordInvocation("FindInstances", []interface{}{deploymentName})
+		selection      orchestrator.Selection
+	}{deploymentName, selection})
+	fake.recordInvocation("FindInstances", []interface{}{deploymentName, selection})
 	fake.findInstancesMutex.Unlock()
 	if fake.FindInstancesStub != nil {
-		return fake.FindInstancesStub(deploymentName)
+		return fake.FindInstancesStub(deploymentName, selection)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -62,10 +64,10 @@ func (fake *FakeBoshClient) FindInstancesCallCount() int {
 	return len(fake.findInstancesArgsForCall)
 }
 
-func (fake *FakeBoshClient) FindInstancesArgsForCall(i int) string {
+func (fake *FakeBoshClient) FindInstancesArgsForCall(i int) (string, orchestrator.Selection) {
 	fake.findInstancesMutex.RLock()
 	defer fake.findInstancesMutex.RUnlock()
-	return fake.findInstancesArgsForCall[i].deploymentName
+	return fake.findInstancesArgsForCall[i].deploymentName, fake.findInstancesArgsForCall[i].selection
 }
 
 func (fake *FakeBoshClient) FindInstancesReturns(result1 []orchestrator.Instance, result2 error) {
@@ -0,0 +1,58 @@
+package bytesize
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Parse parses sizes like 512M, 20G or 1.5T using binary multiples. A plain number is
+// a number of bytes.
+func Parse(size string) (int64, error) {
+	units := map[string]float64{"": 1, "K": 1 << 10, "M": 1 << 20, "G": 1 << 30, "T": 1 << 40, "P": 1 << 50}
+
+	value := strings.ToUpper(strings.TrimSpace(size))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "B"), "I")
+
+	unit := ""
+	if len(value) > 0 {
+		if _, found := units[value[len(value)-1:]]; found {
+			unit = value[len(value)-1:]
+			value = value[:len(value)-1]
+		}
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number < 0 {
+		return 0, errors.Errorf("invalid size %q", size)
+	}
+	return int64(math.Ceil(number * units[unit])), nil
+}
+
+// Format formats sizes the way `du -sh` does, so log lines read the same
+// whichever storage the backup is in.
+func Format(size int64) string {
+	units := []string{"K", "M", "G", "T", "P"}
+
+	if size < 1024 {
+		return fmt.Sprintf("%d", size)
+	}
+
+	value := float64(size)
+	unit := ""
+	for _, u := range units {
+		if value < 1024 {
+			break
+		}
+		value = value / 1024
+		unit = u
+	}
+
+	if value < 10 {
+		return fmt.Sprintf("%.1f%s", math.Ceil(value*10)/10, unit)
+	}
+	return fmt.Sprintf("%.0f%s", math.Ceil(value), unit)
+}
@@ -0,0 +1,13 @@
+package bytesize_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestBytesize(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bytesize Suite")
+}
@@ -0,0 +1,34 @@
+package bytesize_test
+
+import (
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/bytesize"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bytesize", func() {
+	Describe("Parse", func() {
+		It("parses sizes with binary units", func() {
+			Expect(Parse("100")).To(Equal(int64(100)))
+			Expect(Parse("2K")).To(Equal(int64(2048)))
+			Expect(Parse("1.5G")).To(Equal(int64(1536 * 1024 * 1024)))
+			Expect(Parse("20GB")).To(Equal(int64(20 * 1024 * 1024 * 1024)))
+			Expect(Parse("1TiB")).To(Equal(int64(1024 * 1024 * 1024 * 1024)))
+		})
+
+		It("rejects invalid sizes", func() {
+			_, err := Parse("lots")
+			Expect(err).To(MatchError(ContainSubstring("invalid size")))
+		})
+	})
+
+	Describe("Format", func() {
+		It("formats sizes the way du -sh does", func() {
+			Expect(Format(100)).To(Equal("100"))
+			Expect(Format(4096)).To(Equal("4.0K"))
+			Expect(Format(1536 * 1024 * 1024)).To(Equal("1.5G"))
+			Expect(Format(20 * 1024 * 1024 * 1024)).To(Equal("20G"))
+		})
+	})
+})
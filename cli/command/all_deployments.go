@@ -12,34 +12,49 @@ import (
 	"github.com/cloudfoundry/bosh-utils/logger"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bosh"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/event"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor/deployment"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/s3"
 	"github.com/urfave/cli"
 )
 
-func runForAllDeployments(action ActionFunc, boshClient bosh.Client, summaryErrorMsg, summarySuccessMsg string, errorHandler deployment.ErrorHandleFunc, executor deployment.DeploymentExecutor) error {
+func runForAllDeployments(out io.Writer, action ActionFunc, boshClient bosh.Client, summaryErrorMsg, summarySuccessMsg string, errorHandler deployment.ErrorHandleFunc, executor deployment.DeploymentExecutor) error {
 	deployments, err := getAllDeployments(boshClient)
 	if err != nil {
 		return processError(orchestrator.NewError(err))
 	}
 
-	printPending(deployments)
+	return runForDeployments(out, deployments, action, summaryErrorMsg, summarySuccessMsg, errorHandler, executor)
+}
+
+func runForDeployments(out io.Writer, deployments []string, action ActionFunc, summaryErrorMsg, summarySuccessMsg string, errorHandler deployment.ErrorHandleFunc, executor deployment.DeploymentExecutor) error {
+	printPending(out, deployments)
 
 	executables := createExecutables(deployments, action)
 	errs := executor.Run(executables)
 	successfulDeployments, failedDeployments := getDeploymentStates(deployments, errs)
 
-	printSuccess(summarySuccessMsg, successfulDeployments)
+	printSuccess(out, summarySuccessMsg, successfulDeployments)
 
 	if len(errs) != 0 {
-		printFailed(failedDeployments)
+		printFailed(out, failedDeployments)
 		errMsg := summaryError(errs, deployments, summaryErrorMsg)
+		event.RecordCommandFinished(1, allDeploymentsErrorTypes(errs))
 		return errorHandler(deployment.AllDeploymentsError{Summary: errMsg, DeploymentErrs: errs})
 	}
 
+	event.RecordCommandFinished(0, nil)
 	return cli.NewExitError("", 0)
+}
 
+func allDeploymentsErrorTypes(errs []deployment.DeploymentError) []string {
+	var allErrs orchestrator.Error
+	for _, depErr := range errs {
+		allErrs = append(allErrs, depErr.Errs...)
+	}
+	return orchestrator.ErrorTypes(allErrs)
 }
 
 func getAllDeployments(boshClient bosh.Client) ([]string, error) {
@@ -60,18 +75,18 @@ func getAllDeployments(boshClient bosh.Client) ([]string, error) {
 	return deploymentNames, nil
 }
 
-func printFailed(failedDeployments []string) {
-	printlnWithTimestamp(fmt.Sprintf("FAILED: %s", strings.Join(failedDeployments, ", ")))
+func printFailed(out io.Writer, failedDeployments []string) {
+	printlnWithTimestamp(out, fmt.Sprintf("FAILED: %s", strings.Join(failedDeployments, ", ")))
 }
 
-func printSuccess(summarySuccessMsg string, successfulDeployments []string) {
-	printlnWithTimestamp("-------------------------")
-	printlnWithTimestamp(fmt.Sprintf("Successfully %s: %s", summarySuccessMsg, strings.Join(successfulDeployments, ", ")))
+func printSuccess(out io.Writer, summarySuccessMsg string, successfulDeployments []string) {
+	printlnWithTimestamp(out, "-------------------------")
+	printlnWithTimestamp(out, fmt.Sprintf("Successfully %s: %s", summarySuccessMsg, strings.Join(successfulDeployments, ", ")))
 }
 
-func printPending(deployments []string) {
-	printlnWithTimestamp(fmt.Sprintf("Pending: %s", strings.Join(deployments, ", ")))
-	printlnWithTimestamp("-------------------------")
+func printPending(out io.Writer, deployments []string) {
+	printlnWithTimestamp(out, fmt.Sprintf("Pending: %s", strings.Join(deployments, ", ")))
+	printlnWithTimestamp(out, "-------------------------")
 }
 
 func summaryError(errs []deployment.DeploymentError, deployments []string, summaryErrorMsg string) string {
@@ -142,6 +157,9 @@ func NewDeploymentExecutable(action ActionFunc, name string) DeploymentExecutabl
 }
 
 func createLogger(timestamp string, artifactPath string, deploymentName string, debug bool) (string, *bytes.Buffer, logger.Logger) {
+	if s3.IsURL(artifactPath) {
+		artifactPath = ""
+	}
 	logFilePath := filepath.Join(artifactPath, fmt.Sprintf("%s_%s.log", deploymentName, timestamp))
 	logFile, _ := os.OpenFile(logFilePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, defaultLogfilePermissions)
 	buffer := new(bytes.Buffer)
@@ -1,9 +1,13 @@
 package command
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"time"
 
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor/deployment"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
@@ -25,34 +29,74 @@ func (d DeploymentBackupCommand) Cli() cli.Command {
 		Aliases: []string{"b"},
 		Usage:   "Backup a deployment",
 		Action:  d.Action,
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			cli.BoolFlag{
 				Name:  "with-manifest",
 				Usage: "Download the deployment manifest",
 			},
 			cli.StringFlag{
 				Name:  "artifact-path",
-				Usage: "Specify an optional path to save the backup artifacts to",
+				Usage: "Specify an optional path to save the backup artifacts to, or an s3://bucket/prefix URL",
 			},
-		},
+			cli.StringFlag{
+				Name:  "compression",
+				Value: "none",
+				Usage: "Compress backup artifacts as they are copied: gzip, zstd or none",
+			},
+			cli.StringFlag{
+				Name:  "encryption-key-file",
+				Usage: "Encrypt artifacts with the key in this file (or set $BBR_ENCRYPTION_PASSPHRASE)",
+			},
+			cli.StringFlag{
+				Name:  "resume",
+				Usage: "Resume draining into an existing backup directory, skipping artifacts that were already copied",
+			},
+		}, selectionFlags()...),
 	}
 }
 
 func (d DeploymentBackupCommand) Action(c *cli.Context) error {
-	trapSigint(true)
+	settings, err := deploymentSettings(c)
+	if err != nil {
+		return err
+	}
+	settings.Abort = factory.BuildAbort()
+	trapSignals(c.App.Writer, true, settings.Abort)
 
 	username, password, target, caCert, debug, deployment, allDeployments := getDeploymentParams(c)
 	withManifest := c.Bool("with-manifest")
 	artifactPath := c.String("artifact-path")
+	compression := c.String("compression")
+
+	if err := flags.ValidateCompression(c); err != nil {
+		return err
+	}
+
+	if err := flags.ValidateResume(c); err != nil {
+		return err
+	}
+
+	selection, err := flags.Selection(c)
+	if err != nil {
+		return err
+	}
+
+	encryptionKey, err := backup.ReadEncryptionKey(c.String("encryption-key-file"))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	ctx, cancel := commandContext(c)
+	defer cancel()
 
 	if allDeployments {
-		return backupAll(target, username, password, caCert, artifactPath, withManifest, debug)
+		return backupAll(ctx, c.App.Writer, target, username, password, caCert, artifactPath, compression, encryptionKey, selection, settings, withManifest, debug)
 	} else {
-		return backupSingleDeployment(deployment, target, username, password, caCert, artifactPath, withManifest, debug)
+		return backupSingleDeployment(ctx, deployment, target, username, password, caCert, artifactPath, c.String("resume"), compression, encryptionKey, selection, settings, withManifest, debug)
 	}
 }
 
-func backupAll(target, username, password, caCert, artifactPath string, withManifest, debug bool) error {
+func backupAll(ctx context.Context, out io.Writer, target, username, password, caCert, artifactPath, compression string, encryptionKey []byte, selection orchestrator.Selection, settings factory.Settings, withManifest, debug bool) error {
 	backupAction := func(deploymentName string) orchestrator.Error {
 		timestamp := time.Now().UTC().Format(artifactTimeStampFormat)
 		logFilePath, buffer, logger := createLogger(timestamp, artifactPath, deploymentName, debug)
@@ -63,21 +107,26 @@ func backupAll(target, username, password, caCert, artifactPath string, withMani
 			password,
 			caCert,
 			withManifest,
+			compression,
+			encryptionKey,
+			selection,
+			settings,
 			logger,
 			timestamp,
+			journalDirectory(artifactPath),
 		)
 		if factoryErr != nil {
 			return orchestrator.NewError(factoryErr)
 		}
 
-		printlnWithTimestamp(fmt.Sprintf("Starting backup of %s, log file: %s", deploymentName, logFilePath))
-		err := backuper.Backup(deploymentName, artifactPath)
+		printlnWithTimestamp(out, fmt.Sprintf("Starting backup of %s, log file: %s", deploymentName, logFilePath))
+		err := backuper.Backup(ctx, deploymentName, artifactPath)
 
 		if err != nil {
-			printlnWithTimestamp(fmt.Sprintf("ERROR: failed to backup %s", deploymentName))
-			fmt.Println(buffer.String())
+			printlnWithTimestamp(out, fmt.Sprintf("ERROR: failed to backup %s", deploymentName))
+			fmt.Fprintln(out, buffer.String())
 		} else {
-			printlnWithTimestamp(fmt.Sprintf("Finished backup of %s", deploymentName))
+			printlnWithTimestamp(out, fmt.Sprintf("Finished backup of %s", deploymentName))
 		}
 
 		return err
@@ -90,31 +139,42 @@ func backupAll(target, username, password, caCert, artifactPath string, withMani
 		return deploymentError.Process()
 	}
 
-	fmt.Println("Starting backup...")
+	fmt.Fprintln(out, "Starting backup...")
 
 	logger, _ := factory.BuildBoshLoggerWithCustomBuffer(debug)
-	boshClient, err := factory.BuildBoshClient(target, username, password, caCert, logger)
+	boshClient, err := factory.BuildBoshClient(target, username, password, caCert, settings, logger)
 	if err != nil {
 		return processError(orchestrator.NewError(err))
 	}
 
-	return runForAllDeployments(backupAction,
+	return runForAllDeployments(out, backupAction,
 		boshClient,
 		"cannot be backed up",
 		"backed up",
 		errorHandler,
-		deployment.NewParallelExecutor())
+		factory.BuildDeploymentExecutor(settings.Concurrency))
 }
-func backupSingleDeployment(deployment, target, username, password, caCert, artifactPath string, withManifest, debug bool) error {
+func backupSingleDeployment(ctx context.Context, deployment, target, username, password, caCert, artifactPath, resumePath, compression string, encryptionKey []byte, selection orchestrator.Selection, settings factory.Settings, withManifest, debug bool) error {
 	logger := factory.BuildBoshLogger(debug)
 	timeStamp := time.Now().UTC().Format(artifactTimeStampFormat)
 
-	backuper, err := factory.BuildDeploymentBackuper(target, username, password, caCert, withManifest, logger, timeStamp)
+	journalDir := journalDirectory(artifactPath)
+	if resumePath != "" {
+		journalDir = backupJournalDirectory(resumePath)
+	}
+
+	backuper, err := factory.BuildDeploymentBackuper(target, username, password, caCert, withManifest, compression, encryptionKey, selection, settings, logger, timeStamp, journalDir)
 	if err != nil {
 		return processError(orchestrator.NewError(err))
 	}
 
-	backupErr := backuper.Backup(deployment, artifactPath)
+	var backupErr orchestrator.Error
+	if resumePath != "" {
+		backupErr = backuper.Resume(ctx, deployment, resumePath)
+	} else {
+		backupErr = backuper.Backup(ctx, deployment, artifactPath)
+	}
+
 	if backupErr.ContainsUnlockOrCleanupOrArtifactDirExists() {
 		return processErrorWithFooter(backupErr, backupCleanupAdvisedNotice)
 	} else {
@@ -122,6 +182,6 @@ func backupSingleDeployment(deployment, target, username, password, caCert, arti
 	}
 }
 
-func printlnWithTimestamp(str string) {
-	fmt.Printf("[%s] %s\n", time.Now().UTC().Format("15:04:05"), str)
+func printlnWithTimestamp(out io.Writer, str string) {
+	fmt.Fprintf(out, "[%s] %s\n", time.Now().UTC().Format("15:04:05"), str)
 }
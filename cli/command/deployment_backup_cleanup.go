@@ -2,6 +2,7 @@ package command
 
 import (
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor/deployment"
@@ -23,13 +24,25 @@ func (d DeploymentBackupCleanupCommand) Cli() cli.Command {
 		Name:   "backup-cleanup",
 		Usage:  "Cleanup a deployment after a backup was interrupted",
 		Action: d.Action,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "artifact-path",
+				Usage: cleanupArtifactPathUsage,
+			},
+		},
 	}
 }
 
 func (d DeploymentBackupCleanupCommand) Action(c *cli.Context) error {
-	trapSigint(true)
+	trapSignals(c.App.Writer, true, nil)
+
+	settings, err := deploymentSettings(c)
+	if err != nil {
+		return err
+	}
 
 	username, password, target, caCert, debug, deployment, allDeployments := getDeploymentParams(c)
+	journalDir := journalDirectory(c.String("artifact-path"))
 
 	if !allDeployments {
 		logger := factory.BuildBoshLogger(debug)
@@ -39,7 +52,9 @@ func (d DeploymentBackupCleanupCommand) Action(c *cli.Context) error {
 			username,
 			password,
 			caCert,
+			settings,
 			logger,
+			journalDir,
 		)
 		if err != nil {
 			return processError(orchestrator.NewError(err))
@@ -49,10 +64,10 @@ func (d DeploymentBackupCleanupCommand) Action(c *cli.Context) error {
 		return processError(cleanupErr)
 	}
 
-	return cleanupAllDeployments(target, username, password, caCert, debug)
+	return cleanupAllDeployments(c.App.Writer, target, username, password, caCert, journalDir, settings, debug)
 }
 
-func cleanupAllDeployments(target, username, password, caCert string, debug bool) error {
+func cleanupAllDeployments(out io.Writer, target, username, password, caCert, journalDir string, settings factory.Settings, debug bool) error {
 	cleanupAction := func(deploymentName string) orchestrator.Error {
 		timestamp := time.Now().UTC().Format(artifactTimeStampFormat)
 		logFilePath, buffer, logger := createLogger(timestamp, "", deploymentName, debug)
@@ -62,21 +77,23 @@ func cleanupAllDeployments(target, username, password, caCert string, debug bool
 			username,
 			password,
 			caCert,
+			settings,
 			logger,
+			journalDir,
 		)
 
 		if factoryError != nil {
 			return orchestrator.NewError(factoryError)
 		}
 
-		printlnWithTimestamp(fmt.Sprintf("Starting cleanup of %s, log file: %s", deploymentName, logFilePath))
-		err := cleanup(cleaner, deploymentName)
+		printlnWithTimestamp(out, fmt.Sprintf("Starting cleanup of %s, log file: %s", deploymentName, logFilePath))
+		err := cleanup(out, cleaner, deploymentName)
 
 		if err != nil {
-			printlnWithTimestamp(fmt.Sprintf("ERROR: failed to cleanup %s", deploymentName))
-			fmt.Println(buffer.String())
+			printlnWithTimestamp(out, fmt.Sprintf("ERROR: failed to cleanup %s", deploymentName))
+			fmt.Fprintln(out, buffer.String())
 		} else {
-			printlnWithTimestamp(fmt.Sprintf("Finished cleanup of %s", deploymentName))
+			printlnWithTimestamp(out, fmt.Sprintf("Finished cleanup of %s", deploymentName))
 		}
 
 		return err
@@ -88,26 +105,27 @@ func cleanupAllDeployments(target, username, password, caCert string, debug bool
 
 	logger, _ := factory.BuildBoshLoggerWithCustomBuffer(debug)
 
-	boshClient, err := factory.BuildBoshClient(target, username, password, caCert, logger)
+	boshClient, err := factory.BuildBoshClient(target, username, password, caCert, settings, logger)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println("Starting cleanup...")
+	fmt.Fprintln(out, "Starting cleanup...")
 
 	return runForAllDeployments(
+		out,
 		cleanupAction,
 		boshClient,
 		"could not be cleaned up",
 		"cleaned up",
 		errorHandler,
-		deployment.NewParallelExecutor())
+		factory.BuildDeploymentExecutor(settings.Concurrency))
 }
 
-func cleanup(cleaner *orchestrator.BackupCleaner, deployment string) orchestrator.Error {
+func cleanup(out io.Writer, cleaner *orchestrator.BackupCleaner, deployment string) orchestrator.Error {
 	err := cleaner.Cleanup(deployment)
 	if err != nil {
-		fmt.Printf("Failed to cleanup deployment '%s'\n", deployment)
+		fmt.Fprintf(out, "Failed to cleanup deployment '%s'\n", deployment)
 		return err
 	}
 	return nil
@@ -1,10 +1,13 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bosh"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor/deployment"
 	"github.com/cloudfoundry/bosh-utils/logger"
+	"io"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
@@ -23,32 +26,46 @@ func (d DeploymentPreBackupCheck) Cli() cli.Command {
 		Aliases: []string{"c"},
 		Usage:   "Check a deployment can be backed up",
 		Action:  d.Action,
-		Flags:   []cli.Flag{},
+		Flags:   selectionFlags(),
 	}
 }
 
 func (d DeploymentPreBackupCheck) Action(c *cli.Context) error {
 	username, password, target, caCert, debug, deployment, allDeployments := getDeploymentParams(c)
+
+	settings, err := deploymentSettings(c)
+	if err != nil {
+		return err
+	}
+
+	selection, err := flags.Selection(c)
+	if err != nil {
+		return err
+	}
+
 	var logger logger.Logger
 	if allDeployments {
 		logger, _ = factory.BuildBoshLoggerWithCustomBuffer(debug)
 	} else {
 		logger = factory.BuildBoshLogger(debug)
 	}
-	boshClient, err := factory.BuildBoshClient(target, username, password, caCert, logger)
+	boshClient, err := factory.BuildBoshClient(target, username, password, caCert, settings, logger)
 	if err != nil {
 		return processError(orchestrator.NewError(err))
 	}
 
-	backupChecker := factory.BuildDeploymentBackupChecker(boshClient, logger, false)
+	backupChecker := factory.BuildDeploymentBackupChecker(boshClient, logger, false, selection, settings.Concurrency)
+
+	ctx, cancel := commandContext(c)
+	defer cancel()
 
 	if allDeployments {
-		errs := allDeploymentsBackupCheck(boshClient, backupChecker)
+		errs := allDeploymentsBackupCheck(ctx, c.App.Writer, boshClient, backupChecker, settings.Concurrency)
 		if errs != nil {
 			return errs
 		}
 	} else {
-		errs := backupableCheck(backupChecker, deployment)
+		errs := backupableCheck(ctx, c.App.Writer, backupChecker, deployment)
 		if errs != nil {
 			if errs.ContainsArtifactDirError() {
 				return processErrorWithFooter(errs, backupCleanupAdvisedNotice)
@@ -60,22 +77,22 @@ func (d DeploymentPreBackupCheck) Action(c *cli.Context) error {
 	return cli.NewExitError("", 0)
 }
 
-func backupableCheck(backupChecker *orchestrator.BackupChecker, deploymentName string) orchestrator.Error {
-	err := backupChecker.Check(deploymentName)
+func backupableCheck(ctx context.Context, out io.Writer, backupChecker *orchestrator.BackupChecker, deploymentName string) orchestrator.Error {
+	err := backupChecker.Check(ctx, deploymentName)
 
 	if err != nil {
-		printlnWithTimestamp(fmt.Sprintf("Deployment '%s' cannot be backed up.", deploymentName))
-		fmt.Println(deployment.IndentBlock(err.Error()))
+		printlnWithTimestamp(out, fmt.Sprintf("Deployment '%s' cannot be backed up.", deploymentName))
+		fmt.Fprintln(out, deployment.IndentBlock(err.Error()))
 		return err
 	}
 
-	printlnWithTimestamp(fmt.Sprintf("Deployment '%s' can be backed up.", deploymentName))
+	printlnWithTimestamp(out, fmt.Sprintf("Deployment '%s' can be backed up.", deploymentName))
 	return nil
 }
 
-func allDeploymentsBackupCheck(boshClient bosh.Client, backupChecker *orchestrator.BackupChecker) error {
+func allDeploymentsBackupCheck(ctx context.Context, out io.Writer, boshClient bosh.Client, backupChecker *orchestrator.BackupChecker, concurrency factory.ConcurrencyLimits) error {
 	backupCheckerAction := func(deploymentName string) orchestrator.Error {
-		return backupableCheck(backupChecker, deploymentName)
+		return backupableCheck(ctx, out, backupChecker, deploymentName)
 	}
 
 	errorHandler := func(deploymentError deployment.AllDeploymentsError) error {
@@ -85,11 +102,11 @@ func allDeploymentsBackupCheck(boshClient bosh.Client, backupChecker *orchestrat
 		return deploymentError.Process()
 	}
 
-	return runForAllDeployments(backupCheckerAction,
+	return runForAllDeployments(out, backupCheckerAction,
 		boshClient,
 		"cannot be backed up",
 		"can be backed up",
 		errorHandler,
-		deployment.NewParallelExecutor(),
+		factory.BuildDeploymentExecutor(concurrency),
 	)
 }
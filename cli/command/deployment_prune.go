@@ -0,0 +1,31 @@
+package command
+
+import (
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/urfave/cli"
+)
+
+type DeploymentPruneCommand struct {
+}
+
+func NewDeploymentPruneCommand() DeploymentPruneCommand {
+	return DeploymentPruneCommand{}
+}
+
+func (d DeploymentPruneCommand) Cli() cli.Command {
+	return cli.Command{
+		Name:   "prune",
+		Usage:  "Delete old backups of a deployment according to a retention policy",
+		Action: d.Action,
+		Flags:  pruneFlags(),
+	}
+}
+
+func (d DeploymentPruneCommand) Action(c *cli.Context) error {
+	_, _, _, _, _, deployment, allDeployments := getDeploymentParams(c)
+
+	if allDeployments {
+		return prune(c, "", backup.KindDeployment)
+	}
+	return prune(c, deployment, backup.KindDeployment)
+}
@@ -1,9 +1,20 @@
 package command
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor/deployment"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/s3"
+	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 )
 
@@ -20,33 +31,195 @@ func (d DeploymentRestoreCommand) Cli() cli.Command {
 		Aliases: []string{"r"},
 		Usage:   "Restore a deployment from backup",
 		Action:  d.Action,
-		Flags: []cli.Flag{cli.StringFlag{
-			Name:  "artifact-path",
-			Usage: "Path to the artifact to restore",
-		}},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "artifact-path",
+				Usage: "Path or s3://bucket/prefix URL of the artifact to restore. With '--all-deployments', the directory containing the backups of every deployment",
+			},
+			cli.StringFlag{
+				Name:  "timestamp",
+				Usage: "With '--all-deployments', restore the backups taken at this timestamp, e.g. 20170102T150405Z, instead of the latest ones",
+			},
+			cli.StringSliceFlag{
+				Name:  "only-artifact",
+				Usage: "Only restore this artifact, named after its job unless the job names it. Can be repeated",
+			},
+			cli.StringSliceFlag{
+				Name:  "only-job",
+				Usage: "Only lock, restore and unlock this job. Can be repeated",
+			},
+			cli.StringSliceFlag{
+				Name:  "only-instance-group",
+				Usage: "Only restore instances of this instance group. Can be repeated",
+			},
+			cli.StringFlag{
+				Name:  "encryption-key-file",
+				Usage: "Decrypt artifacts with the key in this file (or set $BBR_ENCRYPTION_PASSPHRASE)",
+			},
+		},
 	}
 }
 
 func (d DeploymentRestoreCommand) Action(c *cli.Context) error {
-	trapSigint(false)
+	settings, err := deploymentSettings(c)
+	if err != nil {
+		return err
+	}
+	settings.Abort = factory.BuildAbort()
+	trapSignals(c.App.Writer, false, settings.Abort)
 
 	if err := flags.Validate([]string{"artifact-path"}, c); err != nil {
 		return err
 	}
 
-	deployment := c.Parent().String("deployment")
+	if err := flags.ValidateRestoreTimestamp(c); err != nil {
+		return err
+	}
+
+	username, password, target, caCert, debug, deployment, allDeployments := getDeploymentParams(c)
 	artifactPath := c.String("artifact-path")
+	selection := orchestrator.Selection{
+		Artifacts:      c.StringSlice("only-artifact"),
+		Jobs:           c.StringSlice("only-job"),
+		InstanceGroups: c.StringSlice("only-instance-group"),
+	}
 
-	restorer, err := factory.BuildDeploymentRestorer(c.Parent().String("target"),
-		c.Parent().String("username"),
-		c.Parent().String("password"),
-		c.Parent().String("ca-cert"),
-		c.GlobalBool("debug"))
+	encryptionKey, err := backup.ReadEncryptionKey(c.String("encryption-key-file"))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	ctx, cancel := commandContext(c)
+	defer cancel()
 
+	if allDeployments {
+		return restoreAll(ctx, c.App.Writer, target, username, password, caCert, artifactPath, c.String("timestamp"), encryptionKey, selection, settings, debug)
+	}
+
+	logger := factory.BuildLogger(debug)
+	selection = restoreSelection(selection, artifactPath, encryptionKey, logger)
+
+	restorer, err := factory.BuildDeploymentRestorer(target, username, password, caCert, encryptionKey, selection, settings, logger, backupJournalDirectory(artifactPath))
 	if err != nil {
 		return processError(orchestrator.NewError(err))
 	}
 
-	restoreErr := restorer.Restore(deployment, artifactPath)
+	restoreErr := restorer.Restore(ctx, deployment, artifactPath)
 	return processError(restoreErr)
 }
+
+func restoreAll(ctx context.Context, out io.Writer, target, username, password, caCert, artifactPath, timestamp string, encryptionKey []byte, requestedSelection orchestrator.Selection, settings factory.Settings, debug bool) error {
+	if s3.IsURL(artifactPath) {
+		return processError(orchestrator.NewError(errors.New("restoring all deployments from s3 is not supported")))
+	}
+
+	backups, err := backup.LatestBackups(artifactPath, timestamp)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	restoreAction := func(deploymentName string) orchestrator.Error {
+		logFilePath, buffer, logger := createLogger(time.Now().UTC().Format(artifactTimeStampFormat), artifactPath, deploymentName, debug)
+
+		backupPath := backups[deploymentName].Path
+		selection := restoreSelection(requestedSelection, backupPath, encryptionKey, logger)
+
+		restorer, factoryErr := factory.BuildDeploymentRestorer(target, username, password, caCert, encryptionKey, selection, settings, logger, backupJournalDirectory(backupPath))
+		if factoryErr != nil {
+			return orchestrator.NewError(factoryErr)
+		}
+
+		printlnWithTimestamp(out, fmt.Sprintf("Starting restore of %s from %s, log file: %s", deploymentName, backupPath, logFilePath))
+		err := restorer.Restore(ctx, deploymentName, backupPath)
+
+		if err != nil {
+			printlnWithTimestamp(out, fmt.Sprintf("ERROR: failed to restore %s", deploymentName))
+			fmt.Fprintln(out, buffer.String())
+		} else {
+			printlnWithTimestamp(out, fmt.Sprintf("Finished restore of %s", deploymentName))
+		}
+
+		return err
+	}
+
+	errorHandler := func(deploymentError deployment.AllDeploymentsError) error {
+		if deployment.ContainsUnlockOrCleanup(deploymentError.DeploymentErrs) {
+			return deploymentError.ProcessWithFooter(restoreCleanupAllDeploymentsAdvisedNotice)
+		}
+		return deploymentError.Process()
+	}
+
+	fmt.Fprintln(out, "Starting restore...")
+
+	logger, _ := factory.BuildBoshLoggerWithCustomBuffer(debug)
+	boshClient, err := factory.BuildBoshClient(target, username, password, caCert, settings, logger)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	deployments, err := getAllDeployments(boshClient)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	deploymentsToRestore := matchBackupsToDeployments(out, backups, deployments)
+	if len(deploymentsToRestore) == 0 {
+		return processError(orchestrator.NewError(errors.Errorf("Failed to find any backups of the deployments on the director in %s", artifactPath)))
+	}
+
+	return runForDeployments(out, deploymentsToRestore,
+		restoreAction,
+		"cannot be restored",
+		"restored",
+		errorHandler,
+		factory.BuildDeploymentExecutor(settings.Concurrency))
+}
+
+// restoreSelection returns the requested selection, or if none was requested and the backup
+// is partial, the instance groups, jobs and artifacts the backup was taken with. A backup
+// which cannot be read is left for the restore to report.
+func restoreSelection(requested orchestrator.Selection, backupPath string, encryptionKey []byte, logger orchestrator.Logger) orchestrator.Selection {
+	if !requested.IsEmpty() {
+		return requested
+	}
+
+	recorded, err := backup.BackupDirectoryManager{EncryptionKey: encryptionKey}.RecordedSelection(backupPath, logger)
+	if err != nil {
+		logger.Debug("bbr", "Unable to read the selection of backup %s: %s", backupPath, err)
+		return requested
+	}
+	if !recorded.IsEmpty() {
+		logger.Info("bbr", "Backup %s is partial, only restoring the instances and jobs it contains", backupPath)
+	}
+	return recorded
+}
+
+// matchBackupsToDeployments returns the deployments which have a backup, printing the
+// deployments and backups which could not be matched.
+func matchBackupsToDeployments(out io.Writer, backups map[string]backup.StoredBackup, deployments []string) []string {
+	var matched, withoutBackup, withoutDeployment []string
+
+	for _, deploymentName := range deployments {
+		if _, found := backups[deploymentName]; found {
+			matched = append(matched, deploymentName)
+		} else {
+			withoutBackup = append(withoutBackup, deploymentName)
+		}
+	}
+
+	for name := range backups {
+		if !contains(deployments, name) {
+			withoutDeployment = append(withoutDeployment, name)
+		}
+	}
+	sort.Strings(withoutDeployment)
+
+	if len(withoutBackup) > 0 {
+		printlnWithTimestamp(out, fmt.Sprintf("Skipping deployments without a backup: %s", strings.Join(withoutBackup, ", ")))
+	}
+	if len(withoutDeployment) > 0 {
+		printlnWithTimestamp(out, fmt.Sprintf("Skipping backups of deployments which are not on the director: %s", strings.Join(withoutDeployment, ", ")))
+	}
+
+	return matched
+}
@@ -1,6 +1,11 @@
 package command
 
 import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor/deployment"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/urfave/cli"
@@ -18,25 +23,99 @@ func (d DeploymentRestoreCleanupCommand) Cli() cli.Command {
 		Name:   "restore-cleanup",
 		Usage:  "Cleanup a deployment after a restore was interrupted",
 		Action: d.Action,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "artifact-path",
+				Usage: cleanupArtifactPathUsage,
+			},
+		},
 	}
 }
 
 func (d DeploymentRestoreCleanupCommand) Action(c *cli.Context) error {
-	trapSigint(true)
+	trapSignals(c.App.Writer, true, nil)
+
+	settings, err := deploymentSettings(c)
+	if err != nil {
+		return err
+	}
+
+	username, password, target, caCert, debug, deployment, allDeployments := getDeploymentParams(c)
+
+	if allDeployments {
+		return restoreCleanupAllDeployments(c.App.Writer, target, username, password, caCert, journalDirectory(c.String("artifact-path")), c.Bool("with-manifest"), settings, debug)
+	}
 
-	cleaner, err := factory.BuildDeploymentRestoreCleanuper(c.Parent().String("target"),
-		c.Parent().String("username"),
-		c.Parent().String("password"),
-		c.Parent().String("ca-cert"),
+	cleaner, err := factory.BuildDeploymentRestoreCleanuper(target,
+		username,
+		password,
+		caCert,
 		c.Bool("with-manifest"),
-		c.GlobalBool("debug"))
+		settings,
+		factory.BuildLogger(debug),
+		backupJournalDirectory(c.String("artifact-path")))
 
 	if err != nil {
 		return processError(orchestrator.NewError(err))
 	}
 
-	deployment := c.Parent().String("deployment")
 	cleanupErr := cleaner.Cleanup(deployment)
 
 	return processError(cleanupErr)
 }
+
+func restoreCleanupAllDeployments(out io.Writer, target, username, password, caCert, journalDir string, withManifest bool, settings factory.Settings, debug bool) error {
+	cleanupAction := func(deploymentName string) orchestrator.Error {
+		timestamp := time.Now().UTC().Format(artifactTimeStampFormat)
+		logFilePath, buffer, logger := createLogger(timestamp, "", deploymentName, debug)
+
+		cleaner, factoryError := factory.BuildDeploymentRestoreCleanuper(
+			target,
+			username,
+			password,
+			caCert,
+			withManifest,
+			settings,
+			logger,
+			journalDir,
+		)
+
+		if factoryError != nil {
+			return orchestrator.NewError(factoryError)
+		}
+
+		printlnWithTimestamp(out, fmt.Sprintf("Starting restore cleanup of %s, log file: %s", deploymentName, logFilePath))
+		err := cleaner.Cleanup(deploymentName)
+
+		if err != nil {
+			printlnWithTimestamp(out, fmt.Sprintf("ERROR: failed to cleanup %s", deploymentName))
+			fmt.Fprintln(out, buffer.String())
+		} else {
+			printlnWithTimestamp(out, fmt.Sprintf("Finished restore cleanup of %s", deploymentName))
+		}
+
+		return err
+	}
+
+	errorHandler := func(deploymentError deployment.AllDeploymentsError) error {
+		return deploymentError.Process()
+	}
+
+	logger, _ := factory.BuildBoshLoggerWithCustomBuffer(debug)
+
+	boshClient, err := factory.BuildBoshClient(target, username, password, caCert, settings, logger)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	fmt.Fprintln(out, "Starting restore cleanup...")
+
+	return runForAllDeployments(
+		out,
+		cleanupAction,
+		boshClient,
+		"could not be cleaned up",
+		"cleaned up",
+		errorHandler,
+		factory.BuildDeploymentExecutor(settings.Concurrency))
+}
@@ -3,7 +3,10 @@ package command
 import (
 	"time"
 
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/urfave/cli"
 )
 
@@ -23,7 +26,16 @@ func (checkCommand DirectorBackupCommand) Cli() cli.Command {
 		Flags: []cli.Flag{
 			cli.StringFlag{
 				Name:  "artifact-path",
-				Usage: "Specify an optional path to save the backup artifacts to",
+				Usage: "Specify an optional path to save the backup artifacts to, or an s3://bucket/prefix URL",
+			},
+			cli.StringFlag{
+				Name:  "compression",
+				Value: "none",
+				Usage: "Compress backup artifacts as they are copied: gzip, zstd or none",
+			},
+			cli.StringFlag{
+				Name:  "encryption-key-file",
+				Usage: "Encrypt artifacts with the key in this file (or set $BBR_ENCRYPTION_PASSPHRASE)",
 			},
 		},
 	}
@@ -31,7 +43,21 @@ func (checkCommand DirectorBackupCommand) Cli() cli.Command {
 }
 
 func (checkCommand DirectorBackupCommand) Action(c *cli.Context) error {
-	trapSigint(true)
+	settings, err := directorSettings(c)
+	if err != nil {
+		return err
+	}
+	settings.Abort = factory.BuildAbort()
+	trapSignals(c.App.Writer, true, settings.Abort)
+
+	if err := flags.ValidateCompression(c); err != nil {
+		return err
+	}
+
+	encryptionKey, err := backup.ReadEncryptionKey(c.String("encryption-key-file"))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
 
 	directorName := extractNameFromAddress(c.Parent().String("host"))
 	timeStamp := time.Now().UTC().Format(artifactTimeStampFormat)
@@ -40,10 +66,17 @@ func (checkCommand DirectorBackupCommand) Action(c *cli.Context) error {
 		c.Parent().String("host"),
 		c.Parent().String("username"),
 		c.Parent().String("private-key-path"),
+		c.String("compression"),
+		encryptionKey,
 		c.GlobalBool("debug"),
-		timeStamp)
+		settings,
+		timeStamp,
+		journalDirectory(c.String("artifact-path")))
+
+	ctx, cancel := commandContext(c)
+	defer cancel()
 
-	backupErr := backuper.Backup(directorName, c.String("artifact-path"))
+	backupErr := backuper.Backup(ctx, directorName, c.String("artifact-path"))
 
 	if backupErr.ContainsUnlockOrCleanupOrArtifactDirExists() {
 		return processErrorWithFooter(backupErr, backupCleanupAdvisedNotice)
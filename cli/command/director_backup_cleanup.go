@@ -16,11 +16,22 @@ func (d DirectorBackupCleanupCommand) Cli() cli.Command {
 		Name:   "backup-cleanup",
 		Usage:  "Cleanup a director after a backup was interrupted",
 		Action: d.Action,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "artifact-path",
+				Usage: cleanupArtifactPathUsage,
+			},
+		},
 	}
 }
 
 func (d DirectorBackupCleanupCommand) Action(c *cli.Context) error {
-	trapSigint(true)
+	trapSignals(c.App.Writer, true, nil)
+
+	settings, err := directorSettings(c)
+	if err != nil {
+		return err
+	}
 
 	directorName := extractNameFromAddress(c.Parent().String("host"))
 
@@ -28,6 +39,8 @@ func (d DirectorBackupCleanupCommand) Action(c *cli.Context) error {
 		c.Parent().String("username"),
 		c.Parent().String("private-key-path"),
 		c.GlobalBool("debug"),
+		settings,
+		journalDirectory(c.String("artifact-path")),
 	)
 
 	cleanupErr := cleaner.Cleanup(directorName)
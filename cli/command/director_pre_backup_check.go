@@ -24,6 +24,11 @@ func NewDirectorPreBackupCheckCommand() DirectorPreBackupCheckCommand {
 }
 
 func (checkCommand DirectorPreBackupCheckCommand) Action(c *cli.Context) error {
+	settings, err := directorSettings(c)
+	if err != nil {
+		return err
+	}
+
 	directorName := extractNameFromAddress(c.Parent().String("host"))
 
 	backupChecker := factory.BuildDirectorBackupChecker(
@@ -31,20 +36,24 @@ func (checkCommand DirectorPreBackupCheckCommand) Action(c *cli.Context) error {
 		c.Parent().String("username"),
 		c.Parent().String("private-key-path"),
 		c.GlobalBool("debug"),
+		settings,
 	)
 
-	err := backupChecker.Check(directorName)
+	ctx, cancel := commandContext(c)
+	defer cancel()
 
-	if err != nil {
-		fmt.Printf("Director cannot be backed up.\n")
+	checkErr := backupChecker.Check(ctx, directorName)
+
+	if checkErr != nil {
+		fmt.Fprintf(c.App.Writer, "Director cannot be backed up.\n")
 
-		if err.ContainsArtifactDirError() {
-			return processErrorWithFooter(err, backupCleanupAdvisedNotice)
+		if checkErr.ContainsArtifactDirError() {
+			return processErrorWithFooter(checkErr, backupCleanupAdvisedNotice)
 		}
 
-		return processError(err)
+		return processError(checkErr)
 	}
 
-	fmt.Printf("Director can be backed up.\n")
+	fmt.Fprintf(c.App.Writer, "Director can be backed up.\n")
 	return cli.NewExitError("", 0)
 }
@@ -0,0 +1,26 @@
+package command
+
+import (
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/urfave/cli"
+)
+
+type DirectorPruneCommand struct {
+}
+
+func NewDirectorPruneCommand() DirectorPruneCommand {
+	return DirectorPruneCommand{}
+}
+
+func (d DirectorPruneCommand) Cli() cli.Command {
+	return cli.Command{
+		Name:   "prune",
+		Usage:  "Delete old backups of a BOSH Director according to a retention policy",
+		Action: d.Action,
+		Flags:  pruneFlags(),
+	}
+}
+
+func (d DirectorPruneCommand) Action(c *cli.Context) error {
+	return prune(c, extractNameFromAddress(c.Parent().String("host")), backup.KindDirector)
+}
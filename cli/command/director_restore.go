@@ -1,8 +1,10 @@
 package command
 
 import (
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/urfave/cli"
 )
 
@@ -22,14 +24,23 @@ func (cmd DirectorRestoreCommand) Cli() cli.Command {
 		Flags: []cli.Flag{
 			cli.StringFlag{
 				Name:  "artifact-path",
-				Usage: "Path to the artifact to restore",
+				Usage: "Path or s3://bucket/prefix URL of the artifact to restore",
+			},
+			cli.StringFlag{
+				Name:  "encryption-key-file",
+				Usage: "Decrypt artifacts with the key in this file (or set $BBR_ENCRYPTION_PASSPHRASE)",
 			},
 		},
 	}
 }
 
 func (cmd DirectorRestoreCommand) Action(c *cli.Context) error {
-	trapSigint(false)
+	settings, err := directorSettings(c)
+	if err != nil {
+		return err
+	}
+	settings.Abort = factory.BuildAbort()
+	trapSignals(c.App.Writer, false, settings.Abort)
 
 	if err := flags.Validate([]string{"artifact-path"}, c); err != nil {
 		return err
@@ -38,13 +49,24 @@ func (cmd DirectorRestoreCommand) Action(c *cli.Context) error {
 	directorName := extractNameFromAddress(c.Parent().String("host"))
 	artifactPath := c.String("artifact-path")
 
+	encryptionKey, err := backup.ReadEncryptionKey(c.String("encryption-key-file"))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
 	restorer := factory.BuildDirectorRestorer(
 		c.Parent().String("host"),
 		c.Parent().String("username"),
 		c.Parent().String("private-key-path"),
+		encryptionKey,
 		c.GlobalBool("debug"),
+		settings,
+		backupJournalDirectory(artifactPath),
 	)
 
-	restoreErr := restorer.Restore(directorName, artifactPath)
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	restoreErr := restorer.Restore(ctx, directorName, artifactPath)
 	return processError(restoreErr)
 }
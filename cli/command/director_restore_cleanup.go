@@ -16,11 +16,22 @@ func (d DirectorRestoreCleanupCommand) Cli() cli.Command {
 		Name:   "restore-cleanup",
 		Usage:  "Cleanup a director after a restore was interrupted",
 		Action: d.Action,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "artifact-path",
+				Usage: cleanupArtifactPathUsage,
+			},
+		},
 	}
 }
 
 func (d DirectorRestoreCleanupCommand) Action(c *cli.Context) error {
-	trapSigint(true)
+	trapSignals(c.App.Writer, true, nil)
+
+	settings, err := directorSettings(c)
+	if err != nil {
+		return err
+	}
 
 	directorName := extractNameFromAddress(c.Parent().String("host"))
 
@@ -29,6 +40,8 @@ func (d DirectorRestoreCleanupCommand) Action(c *cli.Context) error {
 		c.Parent().String("username"),
 		c.Parent().String("private-key-path"),
 		c.GlobalBool("debug"),
+		settings,
+		backupJournalDirectory(c.String("artifact-path")),
 	)
 
 	cleanupErr := cleaner.Cleanup(directorName)
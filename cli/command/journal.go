@@ -0,0 +1,27 @@
+package command
+
+import (
+	"path/filepath"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/s3"
+)
+
+const cleanupArtifactPathUsage = "Path given to the interrupted command as --artifact-path, where it kept its journal of what to clean up"
+
+// journalDirectory is where a backup into artifactPath keeps the journal of each deployment:
+// the artifact path itself, or the current directory when backing up to s3
+func journalDirectory(artifactPath string) string {
+	if s3.IsURL(artifactPath) {
+		return ""
+	}
+	return artifactPath
+}
+
+// backupJournalDirectory is where a restore or resume of the backup at backupPath keeps its
+// journal: next to the backup, where the backup itself kept it, or the current directory for s3
+func backupJournalDirectory(backupPath string) string {
+	if s3.IsURL(backupPath) {
+		return ""
+	}
+	return filepath.Dir(filepath.Clean(backupPath))
+}
@@ -0,0 +1,119 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bytesize"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/s3"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+func pruneFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:  "artifact-path",
+			Usage: "Path containing the backup directories to prune",
+		},
+		cli.IntFlag{
+			Name:  "keep-last",
+			Usage: "Keep the most recent N backups",
+		},
+		cli.IntFlag{
+			Name:  "keep-daily",
+			Usage: "Keep the most recent backup for each of the last N days with backups",
+		},
+		cli.IntFlag{
+			Name:  "keep-weekly",
+			Usage: "Keep the most recent backup for each of the last N weeks with backups",
+		},
+		cli.IntFlag{
+			Name:  "keep-monthly",
+			Usage: "Keep the most recent backup for each of the last N months with backups",
+		},
+		cli.StringFlag{
+			Name:  "max-size",
+			Usage: "Delete the oldest kept backups once their total size exceeds this, e.g. 500G",
+		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "List the backups that would be deleted without deleting them",
+		},
+		cli.BoolFlag{
+			Name:  "force",
+			Usage: "Also delete incomplete backups, which have no finish time",
+		},
+	}
+}
+
+// prune applies the retention policy to the backups with the given name. With no name, it
+// prunes backups of any name whose metadata records them as being of the given kind, so that
+// other directories which happen to be named like backups are left alone.
+func prune(c *cli.Context, name, kind string) error {
+	policy, err := flags.RetentionPolicy(c)
+	if err != nil {
+		return err
+	}
+
+	artifactPath := c.String("artifact-path")
+	if s3.IsURL(artifactPath) {
+		return processError(orchestrator.NewError(errors.New("pruning backups in s3 is not supported")))
+	}
+
+	backups, err := backup.FindBackups(artifactPath, name)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+	if name == "" {
+		backups = backupsOfKind(backups, kind)
+	}
+
+	out := c.App.Writer
+	dryRun := c.Bool("dry-run")
+	var deleted int
+	var freed int64
+	var errs []error
+
+	for _, decision := range policy.Apply(backups, c.Bool("force")) {
+		stored := decision.Backup
+		if decision.Keep {
+			fmt.Fprintf(out, "Keeping %s (%s): %s\n", stored.Path, bytesize.Format(stored.Size), decision.Reason)
+			continue
+		}
+
+		if dryRun {
+			fmt.Fprintf(out, "Would delete %s (%s): %s\n", stored.Path, bytesize.Format(stored.Size), decision.Reason)
+		} else if err := backup.RemoveStoredBackup(stored); err != nil {
+			errs = append(errs, err)
+			continue
+		} else {
+			fmt.Fprintf(out, "Deleted %s (%s): %s\n", stored.Path, bytesize.Format(stored.Size), decision.Reason)
+		}
+		deleted++
+		freed += stored.Size
+	}
+
+	if dryRun {
+		fmt.Fprintf(out, "Would delete %d backups, freeing %s\n", deleted, bytesize.Format(freed))
+	} else {
+		fmt.Fprintf(out, "Deleted %d backups, freeing %s\n", deleted, bytesize.Format(freed))
+	}
+
+	if len(errs) > 0 {
+		return processError(orchestrator.NewError(errs...))
+	}
+	return nil
+}
+
+func backupsOfKind(backups []backup.StoredBackup, kind string) []backup.StoredBackup {
+	var ofKind []backup.StoredBackup
+	for _, stored := range backups {
+		if stored.Kind == kind {
+			ofKind = append(ofKind, stored)
+		}
+	}
+	return ofKind
+}
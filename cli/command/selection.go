@@ -0,0 +1,40 @@
+package command
+
+import "github.com/urfave/cli"
+
+func selectionFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "instance-group",
+			Usage: "Only include instances of this instance group. Can be repeated",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude-instance-group",
+			Usage: "Exclude instances of this instance group. Can be repeated",
+		},
+		cli.StringSliceFlag{
+			Name:  "instance",
+			Usage: "Only include this instance, given as <instance-group>/<id or index>. Can be repeated",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude-instance",
+			Usage: "Exclude this instance, given as <instance-group>/<id or index>. Can be repeated",
+		},
+		cli.StringSliceFlag{
+			Name:  "az",
+			Usage: "Only include instances in this availability zone. Can be repeated",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude-az",
+			Usage: "Exclude instances in this availability zone. Can be repeated",
+		},
+		cli.StringSliceFlag{
+			Name:  "job",
+			Usage: "Only lock, back up and unlock this job. Can be repeated",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude-job",
+			Usage: "Do not lock, back up or unlock this job. Can be repeated",
+		},
+	}
+}
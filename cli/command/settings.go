@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+	"os"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/progress"
+	"github.com/urfave/cli"
+)
+
+// directorSettings reads the options given to the director command, which have already been
+// validated before its subcommand runs
+func directorSettings(c *cli.Context) (factory.Settings, error) {
+	settings, err := flags.Settings(c.Parent())
+	if err != nil {
+		return settings, err
+	}
+
+	settings.Version = c.App.Version
+	if c.GlobalString("output") == "text" && isTerminal(os.Stdout) {
+		settings.TransferProgress = progress.NewReporter(progress.DefaultInterval, factory.ApplicationLoggerStdout)
+	}
+	return settings, nil
+}
+
+// deploymentSettings reads the options given to the deployment command, which unlike the
+// director command can also limit how many instances and deployments are worked on at once
+func deploymentSettings(c *cli.Context) (factory.Settings, error) {
+	settings, err := directorSettings(c)
+	if err != nil {
+		return settings, err
+	}
+
+	settings.Concurrency, err = flags.Concurrency(c.Parent())
+	return settings, err
+}
+
+// commandContext is done once the command has run for longer than its --timeout, if one was set
+func commandContext(c *cli.Context) (context.Context, context.CancelFunc) {
+	if timeout := c.Parent().Duration("timeout"); timeout > 0 {
+		return context.WithTimeout(context.Background(), timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
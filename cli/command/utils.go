@@ -3,15 +3,18 @@ package command
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
+	"syscall"
 
 	"io/ioutil"
 	"time"
 
 	"net/url"
 
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/event"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/urfave/cli"
@@ -19,33 +22,42 @@ import (
 
 const defaultLogfilePermissions = 0644
 
-func trapSigint(backup bool) {
-	sigintChan := make(chan os.Signal, 1)
-	signal.Notify(sigintChan, os.Interrupt)
+// trapSignals asks for confirmation on SIGINT and then aborts; SIGTERM aborts straight away.
+// With an abort, the running workflows unlock jobs and clean up before bbr exits, and a
+// second signal exits immediately. Without one, bbr exits as soon as it is aborted.
+func trapSignals(out io.Writer, backup bool, abort *orchestrator.Abort) {
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
 
-	var sigintQuestion, stdInErrorMessage, cleanupAdvisedNotice string
+	var sigintQuestion, stdInErrorMessage, cleanupAdvisedNotice, abortingNotice string
 	if backup {
 		sigintQuestion = backupSigintQuestion
 		stdInErrorMessage = backupStdinErrorMessage
 		cleanupAdvisedNotice = backupCleanupAdvisedNotice
+		abortingNotice = backupAbortingNotice
 	} else {
 		sigintQuestion = restoreSigintQuestion
 		stdInErrorMessage = restoreStdinErrorMessage
 		cleanupAdvisedNotice = restoreCleanupAdvisedNotice
+		abortingNotice = restoreAbortingNotice
 	}
 
 	go func() {
-		for range sigintChan {
-			stdinReader := bufio.NewReader(os.Stdin)
+		for sig := range signalChan {
+			if abort.Aborted() {
+				fmt.Fprintln(out, cleanupAdvisedNotice)
+				os.Exit(1)
+			}
+
 			factory.ApplicationLoggerStdout.Pause()
 			factory.ApplicationLoggerStderr.Pause()
-			fmt.Fprintln(os.Stdout, "\n"+sigintQuestion)
-			input, err := stdinReader.ReadString('\n')
-			if err != nil {
-				fmt.Println("\n" + stdInErrorMessage)
-			} else if strings.ToLower(strings.TrimSpace(input)) == "yes" {
-				fmt.Println(cleanupAdvisedNotice)
-				os.Exit(1)
+			if sig == syscall.SIGTERM || confirmAbort(out, sigintQuestion, stdInErrorMessage) {
+				if abort == nil {
+					fmt.Fprintln(out, cleanupAdvisedNotice)
+					os.Exit(1)
+				}
+				fmt.Fprintln(out, abortingNotice)
+				abort.Abort()
 			}
 			factory.ApplicationLoggerStdout.Resume()
 			factory.ApplicationLoggerStderr.Resume()
@@ -53,12 +65,25 @@ func trapSigint(backup bool) {
 	}()
 }
 
+func confirmAbort(out io.Writer, question, stdInErrorMessage string) bool {
+	stdinReader := bufio.NewReader(os.Stdin)
+	fmt.Fprintln(out, "\n"+question)
+	input, err := stdinReader.ReadString('\n')
+	if err != nil {
+		fmt.Fprintln(out, "\n"+stdInErrorMessage)
+		return false
+	}
+	return strings.ToLower(strings.TrimSpace(input)) == "yes"
+}
+
 func processError(err orchestrator.Error) error {
 	return processErrorWithFooter(err, "")
 }
 
 func processErrorWithFooter(err orchestrator.Error, footer string) error {
 	errorCode := orchestrator.BuildExitCode(err)
+	event.RecordCommandFinished(errorCode, orchestrator.ErrorTypes(err))
+
 	errorMessage := err.Error()
 	errorWithStackTrace := err.PrettyError(true)
 
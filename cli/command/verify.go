@@ -0,0 +1,100 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/urfave/cli"
+)
+
+type VerifyCommand struct {
+}
+
+func NewVerifyCommand() VerifyCommand {
+	return VerifyCommand{}
+}
+
+func (v VerifyCommand) Cli() cli.Command {
+	return cli.Command{
+		Name:   "verify",
+		Usage:  "Verify the checksums of a backup's artifacts without connecting to BOSH",
+		Action: v.Action,
+		Before: func(c *cli.Context) error {
+			return flags.Validate([]string{"artifact-path"}, c)
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "artifact-path",
+				Usage: "Path to the backup directory, or an s3://bucket/prefix URL",
+			},
+			cli.StringFlag{
+				Name:  "encryption-key-file",
+				Usage: "Decrypt artifacts with the key in this file (or set $BBR_ENCRYPTION_PASSPHRASE)",
+			},
+			cli.BoolFlag{
+				Name:  "debug",
+				Usage: "Enable debug logs",
+			},
+		},
+	}
+}
+
+func (v VerifyCommand) Action(c *cli.Context) error {
+	encryptionKey, err := backup.ReadEncryptionKey(c.String("encryption-key-file"))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	logger := factory.BuildLogger(c.Bool("debug"))
+	artifactPath := c.String("artifact-path")
+
+	verification, err := backup.BackupDirectoryManager{EncryptionKey: encryptionKey}.Verify(artifactPath, logger)
+	if err != nil {
+		return processError(orchestrator.NewError(
+			orchestrator.NewVerificationError(fmt.Sprintf("backup at %s could not be verified: %s", artifactPath, err.Error())),
+		))
+	}
+
+	printVerification(c.App.Writer, verification)
+
+	if !verification.Valid() {
+		return processError(orchestrator.NewError(
+			orchestrator.NewVerificationError(fmt.Sprintf("backup at %s failed verification", artifactPath)),
+		))
+	}
+	return nil
+}
+
+func printVerification(out io.Writer, verification backup.Verification) {
+	fmt.Fprintf(out, "Verifying %s\n", verification.Location)
+
+	for _, artifact := range verification.Artifacts {
+		if artifact.Valid() {
+			fmt.Fprintf(out, "  %s: OK\n", artifact.Name)
+			continue
+		}
+
+		fmt.Fprintf(out, "  %s: FAILED\n", artifact.Name)
+		if artifact.Err != nil {
+			fmt.Fprintf(out, "    %s\n", artifact.Err.Error())
+		}
+		printVerificationFiles(out, "missing", artifact.Missing)
+		printVerificationFiles(out, "extra", artifact.Extra)
+		printVerificationFiles(out, "checksum mismatch", artifact.Mismatched)
+	}
+
+	if !verification.Complete {
+		fmt.Fprintln(out, "Backup is incomplete: metadata has no finish_time")
+	}
+}
+
+func printVerificationFiles(out io.Writer, problem string, files []string) {
+	if len(files) > 0 {
+		fmt.Fprintf(out, "    %s: %s\n", problem, strings.Join(files, ", "))
+	}
+}
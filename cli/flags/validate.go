@@ -1,11 +1,24 @@
 package flags
 
 import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bytesize"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ratelimit"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
 	"github.com/mgutz/ansi"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 )
 
+const backupTimestampFormat = "20060102T150405Z"
+
 func Validate(requiredFlags []string, c *cli.Context) error {
 	if containsHelpFlag(c) {
 		return nil
@@ -31,6 +44,267 @@ func ValidateDeployment(c *cli.Context) error {
 	return nil
 }
 
+func ValidateCompression(c *cli.Context) error {
+	if err := backup.ValidateCompression(c.String("compression")); err != nil {
+		cli.ShowSubcommandHelp(c)
+		return redCliError(err)
+	}
+	return nil
+}
+
+func ValidateResume(c *cli.Context) error {
+	if c.String("resume") == "" {
+		return nil
+	}
+
+	if c.Parent().Bool("all-deployments") || c.String("artifact-path") != "" {
+		cli.ShowSubcommandHelp(c)
+		return redCliError(errors.New("--resume cannot be used with '--all-deployments' or '--artifact-path'."))
+	}
+	return nil
+}
+
+func ValidateRestoreTimestamp(c *cli.Context) error {
+	timestamp := c.String("timestamp")
+	if timestamp == "" {
+		return nil
+	}
+
+	if !c.Parent().Bool("all-deployments") {
+		cli.ShowSubcommandHelp(c)
+		return redCliError(errors.New("--timestamp can only be used with '--all-deployments'."))
+	}
+
+	if _, err := time.Parse(backupTimestampFormat, timestamp); err != nil {
+		cli.ShowSubcommandHelp(c)
+		return redCliError(errors.Errorf("--timestamp must be in the format %s.", backupTimestampFormat))
+	}
+	return nil
+}
+
+func Selection(c *cli.Context) (orchestrator.Selection, error) {
+	selection := orchestrator.Selection{
+		InstanceGroups:        c.StringSlice("instance-group"),
+		ExcludeInstanceGroups: c.StringSlice("exclude-instance-group"),
+		Instances:             c.StringSlice("instance"),
+		ExcludeInstances:      c.StringSlice("exclude-instance"),
+		AZs:                   c.StringSlice("az"),
+		ExcludeAZs:            c.StringSlice("exclude-az"),
+		Jobs:                  c.StringSlice("job"),
+		ExcludeJobs:           c.StringSlice("exclude-job"),
+	}
+
+	for _, instances := range [][]string{selection.Instances, selection.ExcludeInstances} {
+		for _, instance := range instances {
+			parts := strings.Split(instance, "/")
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				cli.ShowSubcommandHelp(c)
+				return selection, redCliError(errors.Errorf("instance '%s' must be given as <instance-group>/<id or index>.", instance))
+			}
+		}
+	}
+
+	return selection, nil
+}
+
+func ValidateSSHRetries(c *cli.Context) error {
+	if c.Int("ssh-max-attempts") < 1 {
+		cli.ShowSubcommandHelp(c)
+		return redCliError(errors.New("--ssh-max-attempts must be at least 1."))
+	}
+	return nil
+}
+
+func ValidateTimeouts(c *cli.Context) error {
+	if c.Duration("timeout") < 0 || c.Duration("lock-timeout") < 0 {
+		cli.ShowSubcommandHelp(c)
+		return redCliError(errors.New("--timeout and --lock-timeout cannot be negative."))
+	}
+	return nil
+}
+
+// Settings reads the options of the deployment and director commands which configure how
+// their subcommands run
+func Settings(c *cli.Context) (factory.Settings, error) {
+	settings := factory.DefaultSettings()
+
+	throttle, err := TransferThrottle(c)
+	if err != nil {
+		return settings, err
+	}
+	settings.TransferThrottle = throttle
+
+	timeouts, err := ScriptTimeouts(c)
+	if err != nil {
+		return settings, err
+	}
+	settings.ScriptTimeouts = timeouts
+
+	if err := ValidateTimeouts(c); err != nil {
+		return settings, err
+	}
+	settings.LockTimeout = c.Duration("lock-timeout")
+
+	variables, err := ScriptEnvironmentVariables(c)
+	if err != nil {
+		return settings, err
+	}
+	settings.ScriptEnvironmentVariables = variables
+
+	if err := ValidateSSHRetries(c); err != nil {
+		return settings, err
+	}
+	settings.SSHRetryPolicy = SSHRetryPolicy(c)
+
+	return settings, nil
+}
+
+func SSHRetryPolicy(c *cli.Context) ssh.RetryPolicy {
+	return ssh.RetryPolicy{
+		MaxAttempts:    c.Int("ssh-max-attempts"),
+		InitialBackoff: c.Duration("ssh-retry-backoff"),
+		MaxBackoff:     ssh.DefaultRetryPolicy.MaxBackoff,
+		Operations:     ssh.DefaultRetryPolicy.Operations,
+	}
+}
+
+func Concurrency(c *cli.Context) (factory.ConcurrencyLimits, error) {
+	limits := factory.ConcurrencyLimits{
+		Instances:     c.Int("max-in-flight-instances"),
+		Deployments:   c.Int("max-in-flight-deployments"),
+		LockUnlock:    c.Int("max-in-flight-lock"),
+		BackupScripts: c.Int("max-in-flight-backup-scripts"),
+		Drain:         c.Int("max-in-flight-drain"),
+		Serial:        c.Bool("serial"),
+	}
+
+	if limits.Instances < 1 || limits.Deployments < 1 {
+		cli.ShowSubcommandHelp(c)
+		return limits, redCliError(errors.New("--max-in-flight-instances and --max-in-flight-deployments must be at least 1."))
+	}
+
+	if limits.LockUnlock < 0 || limits.BackupScripts < 0 || limits.Drain < 0 {
+		cli.ShowSubcommandHelp(c)
+		return limits, redCliError(errors.New("--max-in-flight-lock, --max-in-flight-backup-scripts and --max-in-flight-drain cannot be negative."))
+	}
+	return limits, nil
+}
+
+func TransferThrottle(c *cli.Context) (ratelimit.Throttle, error) {
+	total, err := transferRate(c, "max-transfer-rate")
+	if err != nil {
+		return ratelimit.Throttle{}, err
+	}
+
+	perStream, err := transferRate(c, "max-transfer-rate-per-stream")
+	if err != nil {
+		return ratelimit.Throttle{}, err
+	}
+
+	return ratelimit.NewThrottle(total, perStream), nil
+}
+
+func transferRate(c *cli.Context, flag string) (int64, error) {
+	if c.String(flag) == "" {
+		return 0, nil
+	}
+
+	rate, err := bytesize.Parse(c.String(flag))
+	if err != nil {
+		cli.ShowSubcommandHelp(c)
+		return 0, redCliError(errors.Wrapf(err, "--%s is invalid", flag))
+	}
+	return rate, nil
+}
+
+func ScriptTimeouts(c *cli.Context) (instance.ScriptTimeouts, error) {
+	timeouts := map[string]string{}
+	var scriptTimeouts []string
+
+	for _, value := range c.StringSlice("script-timeout") {
+		if strings.Contains(value, "=") {
+			scriptTimeouts = append(scriptTimeouts, value)
+			continue
+		}
+		for _, script := range instance.TimeoutScriptNames {
+			timeouts[script] = value
+		}
+	}
+
+	for _, value := range scriptTimeouts {
+		parts := strings.SplitN(value, "=", 2)
+		timeouts[parts[0]] = parts[1]
+	}
+
+	parsed, err := instance.ParseScriptTimeouts(timeouts)
+	if err != nil {
+		cli.ShowSubcommandHelp(c)
+		return nil, redCliError(errors.Wrap(err, "--script-timeout is invalid"))
+	}
+	return parsed, nil
+}
+
+var environmentVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func ScriptEnvironmentVariables(c *cli.Context) (map[string]string, error) {
+	variables := map[string]string{}
+
+	for _, value := range c.StringSlice("script-env") {
+		parts := strings.SplitN(value, "=", 2)
+		name := parts[0]
+
+		var err error
+		switch {
+		case len(parts) != 2 || !environmentVariableName.MatchString(name):
+			err = errors.Errorf("--script-env '%s' must be given as KEY=VALUE.", value)
+		case strings.HasPrefix(name, "BBR_") || name == "ARTIFACT_DIRECTORY":
+			err = errors.Errorf("--script-env cannot set %s, which is set by bbr.", name)
+		}
+		if err != nil {
+			cli.ShowSubcommandHelp(c)
+			return nil, redCliError(err)
+		}
+
+		variables[name] = parts[1]
+	}
+
+	return variables, nil
+}
+
+func RetentionPolicy(c *cli.Context) (backup.RetentionPolicy, error) {
+	policy := backup.RetentionPolicy{
+		KeepLast:    c.Int("keep-last"),
+		KeepDaily:   c.Int("keep-daily"),
+		KeepWeekly:  c.Int("keep-weekly"),
+		KeepMonthly: c.Int("keep-monthly"),
+	}
+
+	if c.String("max-size") != "" {
+		maxSize, err := bytesize.Parse(c.String("max-size"))
+		if err != nil {
+			cli.ShowSubcommandHelp(c)
+			return policy, redCliError(errors.Wrap(err, "--max-size is invalid"))
+		}
+		policy.MaxTotalSize = maxSize
+	}
+
+	if policy.KeepLast < 0 || policy.KeepDaily < 0 || policy.KeepWeekly < 0 || policy.KeepMonthly < 0 {
+		cli.ShowSubcommandHelp(c)
+		return policy, redCliError(errors.New("--keep-* flags cannot be negative."))
+	}
+
+	if policy.IsEmpty() {
+		cli.ShowSubcommandHelp(c)
+		return policy, redCliError(errors.New("provide at least one of '--keep-last', '--keep-daily', '--keep-weekly', '--keep-monthly' or '--max-size' flags."))
+	}
+
+	return policy, nil
+}
+
+func InvalidOutputError(output string) error {
+	return redCliError(errors.Errorf("--output must be 'text' or 'json', not '%s'.", output))
+}
+
 func containsHelpFlag(c *cli.Context) bool {
 	for _, arg := range c.Args() {
 		if arg == "--help" || arg == "-h" {
@@ -9,6 +9,11 @@ import (
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/command"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/event"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/writer"
 )
 
 var version string
@@ -25,6 +30,14 @@ func main() {
 	app.Usage = "BOSH Backup and Restore"
 	app.HideHelp = true
 	app.CommandNotFound = commandNotFoundFunc
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "output",
+			Value: "text",
+			Usage: "Output format, 'text' or 'json'. With 'json', progress events are written to stdout as one JSON object per line and logs to stderr",
+		},
+	}
+	app.Before = configureOutput
 
 	app.Commands = []cli.Command{
 		{
@@ -38,6 +51,7 @@ func main() {
 				command.NewDeploymentRestoreCommand().Cli(),
 				command.NewDeploymentBackupCleanupCommand().Cli(),
 				command.NewDeploymentRestoreCleanupCommand().Cli(),
+				command.NewDeploymentPruneCommand().Cli(),
 			},
 		},
 		{
@@ -51,8 +65,10 @@ func main() {
 				command.NewDirectorRestoreCommand().Cli(),
 				command.NewDirectorBackupCleanupCommand().Cli(),
 				command.NewDirectorRestoreCleanupCommand().Cli(),
+				command.NewDirectorPruneCommand().Cli(),
 			},
 		},
+		command.NewVerifyCommand().Cli(),
 		{
 			Name:    "help",
 			Aliases: []string{"h"},
@@ -71,12 +87,18 @@ func main() {
 	}
 
 	if err := app.Run(os.Args); err != nil {
-		os.Exit(1)
+		exitCode := 1
+		if exitErr, ok := err.(cli.ExitCoder); ok {
+			exitCode = exitErr.ExitCode()
+		}
+		event.RecordCommandFinished(exitCode, orchestrator.ErrorTypes(orchestrator.NewError(err)))
+		os.Exit(exitCode)
 	}
+	event.RecordCommandFinished(0, nil)
 }
 
 func commandNotFoundFunc(c *cli.Context, msg string) {
-	fmt.Printf("Error command '%s' not found\n\n", msg)
+	fmt.Fprintf(c.App.Writer, "Error command '%s' not found\n\n", msg)
 	cli.ShowAppHelp(c)
 }
 
@@ -85,8 +107,35 @@ func versionAction(c *cli.Context) error {
 	return nil
 }
 
+func configureOutput(c *cli.Context) error {
+	switch c.String("output") {
+	case "text":
+		return nil
+	case "json":
+		event.SetOutput(os.Stdout)
+		// everything else bbr prints goes to stderr, leaving stdout for the events alone
+		c.App.Writer = os.Stderr
+		factory.ApplicationLoggerStdout = writer.NewPausableWriter(os.Stderr)
+		ssh.ProxyLogOutput = os.Stderr
+
+		exit := cli.OsExiter
+		cli.OsExiter = func(code int) {
+			event.RecordCommandFinished(code, nil)
+			exit(code)
+		}
+		return nil
+	default:
+		return flags.InvalidOutputError(c.String("output"))
+	}
+}
+
 func validateDeploymentFlags(c *cli.Context) error {
-	err := flags.Validate([]string{"target", "username", "password"}, c)
+	requiredFlags := []string{"target", "username", "password"}
+	if prunesStoredBackups(c) {
+		requiredFlags = nil
+	}
+
+	err := flags.Validate(requiredFlags, c)
 	if err != nil {
 		return err
 	}
@@ -96,11 +145,35 @@ func validateDeploymentFlags(c *cli.Context) error {
 		return err
 	}
 
-	return nil
+	_, err = flags.Concurrency(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = flags.Settings(c)
+	return err
 }
 
 func validateDirectorFlags(c *cli.Context) error {
-	return flags.Validate([]string{"host", "username", "private-key-path"}, c)
+	requiredFlags := []string{"host", "username", "private-key-path"}
+	if prunesStoredBackups(c) {
+		// the host names the backups to prune
+		requiredFlags = []string{"host"}
+	}
+
+	err := flags.Validate(requiredFlags, c)
+	if err != nil {
+		return err
+	}
+
+	_, err = flags.Settings(c)
+	return err
+}
+
+// prunesStoredBackups is whether the subcommand is prune, which only deletes stored backups
+// and so does not need the credentials of a director
+func prunesStoredBackups(c *cli.Context) bool {
+	return c.Args().First() == "prune"
 }
 
 func availableDeploymentFlags() []cli.Flag {
@@ -139,9 +212,69 @@ func availableDeploymentFlags() []cli.Flag {
 			Name:  "debug",
 			Usage: "Enable debug logs",
 		},
+		cli.IntFlag{
+			Name:  "ssh-max-attempts",
+			Value: ssh.DefaultRetryPolicy.MaxAttempts,
+			Usage: "Maximum attempts for idempotent SSH commands, such as checking or checksumming the backup directory, when the connection fails",
+		},
+		cli.DurationFlag{
+			Name:  "ssh-retry-backoff",
+			Value: ssh.DefaultRetryPolicy.InitialBackoff,
+			Usage: "Time to wait before the first SSH retry. Doubles on each further retry",
+		},
+		cli.StringFlag{
+			Name:  "max-transfer-rate",
+			Usage: "Maximum total rate, in bytes per second, for copying artifacts to and from instances, e.g. '50M'. Shared between all parallel transfers",
+		},
+		cli.StringFlag{
+			Name:  "max-transfer-rate-per-stream",
+			Usage: "Maximum rate, in bytes per second, for copying each artifact to or from an instance, e.g. '10M'",
+		},
+		cli.StringSliceFlag{
+			Name:  "script-timeout",
+			Usage: "Default timeout for backup and restore scripts whose job metadata sets none, as '<script>=<duration>', e.g. 'pre-backup-lock=5m', or '<duration>' for every script. Can be repeated",
+		},
+		cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "Maximum time for a backup, restore or pre-backup-check, e.g. '2h'. Once it is reached, running scripts are stopped and jobs are unlocked before the command fails",
+		},
+		cli.DurationFlag{
+			Name:  "lock-timeout",
+			Usage: "Maximum time jobs may stay locked during a backup or restore, e.g. '30m'. Once it is reached, running scripts are stopped and jobs are unlocked before the command fails",
+		},
+		cli.StringSliceFlag{
+			Name:  "script-env",
+			Usage: "Environment variable to give every backup and restore script, as 'KEY=VALUE'. Can be repeated",
+		},
 		cli.BoolFlag{
 			Name:  "all-deployments",
-			Usage: "Run command for all deployments. Omit if '--deployment' is provided. Currently only supported for: pre-backup-check, backup and backup-cleanup",
+			Usage: "Run command for all deployments. Omit if '--deployment' is provided. Currently only supported for: pre-backup-check, backup, backup-cleanup, restore and restore-cleanup",
+		},
+		cli.IntFlag{
+			Name:  "max-in-flight-instances",
+			Value: factory.DefaultConcurrency.Instances,
+			Usage: "Maximum number of instances worked on at once within a deployment",
+		},
+		cli.IntFlag{
+			Name:  "max-in-flight-deployments",
+			Value: factory.DefaultConcurrency.Deployments,
+			Usage: "Maximum number of deployments worked on at once with '--all-deployments'",
+		},
+		cli.BoolFlag{
+			Name:  "serial",
+			Usage: "Work on one instance and one deployment at a time, ignoring the max-in-flight flags",
+		},
+		cli.IntFlag{
+			Name:  "max-in-flight-lock",
+			Usage: "Maximum number of instances locked or unlocked at once. Defaults to '--max-in-flight-instances'",
+		},
+		cli.IntFlag{
+			Name:  "max-in-flight-backup-scripts",
+			Usage: "Maximum number of instances running backup scripts at once. Defaults to '--max-in-flight-instances'",
+		},
+		cli.IntFlag{
+			Name:  "max-in-flight-drain",
+			Usage: "Maximum number of artifacts transferred to or from instances at once. Defaults to '--max-in-flight-instances'",
 		},
 	}
 }
@@ -167,5 +300,39 @@ func availableDirectorFlags() []cli.Flag {
 			Name:  "debug",
 			Usage: "Enable debug logs",
 		},
+		cli.IntFlag{
+			Name:  "ssh-max-attempts",
+			Value: ssh.DefaultRetryPolicy.MaxAttempts,
+			Usage: "Maximum attempts for idempotent SSH commands, such as checking or checksumming the backup directory, when the connection fails",
+		},
+		cli.DurationFlag{
+			Name:  "ssh-retry-backoff",
+			Value: ssh.DefaultRetryPolicy.InitialBackoff,
+			Usage: "Time to wait before the first SSH retry. Doubles on each further retry",
+		},
+		cli.StringFlag{
+			Name:  "max-transfer-rate",
+			Usage: "Maximum total rate, in bytes per second, for copying artifacts to and from instances, e.g. '50M'. Shared between all parallel transfers",
+		},
+		cli.StringFlag{
+			Name:  "max-transfer-rate-per-stream",
+			Usage: "Maximum rate, in bytes per second, for copying each artifact to or from an instance, e.g. '10M'",
+		},
+		cli.StringSliceFlag{
+			Name:  "script-timeout",
+			Usage: "Default timeout for backup and restore scripts whose job metadata sets none, as '<script>=<duration>', e.g. 'pre-backup-lock=5m', or '<duration>' for every script. Can be repeated",
+		},
+		cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "Maximum time for a backup, restore or pre-backup-check, e.g. '2h'. Once it is reached, running scripts are stopped and jobs are unlocked before the command fails",
+		},
+		cli.DurationFlag{
+			Name:  "lock-timeout",
+			Usage: "Maximum time jobs may stay locked during a backup or restore, e.g. '30m'. Once it is reached, running scripts are stopped and jobs are unlocked before the command fails",
+		},
+		cli.StringSliceFlag{
+			Name:  "script-env",
+			Usage: "Environment variable to give every backup and restore script, as 'KEY=VALUE'. Can be repeated",
+		},
 	}
 }
@@ -25,7 +25,12 @@ SUBCOMMANDS:
    backup-cleanup
    restore
    restore-cleanup
-   pre-backup-check{{if .Copyright}}
+   pre-backup-check
+   prune{{if .VisibleFlags}}
+
+GLOBAL OPTIONS:
+   {{range .VisibleFlags}}{{.}}
+   {{end}}{{end}}{{if .Copyright}}
 
 COPYRIGHT:
    {{.Copyright}}{{end}}
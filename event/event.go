@@ -0,0 +1,96 @@
+package event
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	StepStarted        = "step_started"
+	StepFinished       = "step_finished"
+	JobFinished        = "job_finished"
+	InstanceFinished   = "instance_finished"
+	ArtifactDrained    = "artifact_drained"
+	DeploymentFinished = "deployment_finished"
+	CommandFinished    = "command_finished"
+
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+type Event struct {
+	Time       string            `json:"time"`
+	Type       string            `json:"type"`
+	Deployment string            `json:"deployment,omitempty"`
+	Step       string            `json:"step,omitempty"`
+	Action     string            `json:"action,omitempty"`
+	Instance   string            `json:"instance,omitempty"`
+	Job        string            `json:"job,omitempty"`
+	Artifact   string            `json:"artifact,omitempty"`
+	Size       string            `json:"size,omitempty"`
+	Checksums  map[string]string `json:"checksums,omitempty"`
+	Result     string            `json:"result,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	ExitCode   *int              `json:"exit_code,omitempty"`
+	ErrorTypes []string          `json:"error_types,omitempty"`
+}
+
+var (
+	output          io.Writer
+	outputMutex     sync.Mutex
+	commandFinished bool
+)
+
+// SetOutput turns on recording of events, as newline-delimited JSON written to out
+func SetOutput(out io.Writer) {
+	outputMutex.Lock()
+	defer outputMutex.Unlock()
+	output = out
+	commandFinished = false
+}
+
+func Enabled() bool {
+	outputMutex.Lock()
+	defer outputMutex.Unlock()
+	return output != nil
+}
+
+func Record(e Event) {
+	outputMutex.Lock()
+	defer outputMutex.Unlock()
+
+	if output == nil {
+		return
+	}
+	record(e)
+}
+
+func record(e Event) {
+	e.Time = time.Now().UTC().Format(time.RFC3339)
+	json.NewEncoder(output).Encode(e)
+}
+
+func (e Event) WithResult(err error) Event {
+	if err != nil {
+		e.Result = ResultFailure
+		e.Error = err.Error()
+	} else {
+		e.Result = ResultSuccess
+	}
+	return e
+}
+
+// RecordCommandFinished records the outcome of the command. Only the first call has any
+// effect, so the most specific caller should record it before more general ones.
+func RecordCommandFinished(exitCode int, errorTypes []string) {
+	outputMutex.Lock()
+	defer outputMutex.Unlock()
+
+	if output == nil || commandFinished {
+		return
+	}
+	commandFinished = true
+	record(Event{Type: CommandFinished, ExitCode: &exitCode, ErrorTypes: errorTypes})
+}
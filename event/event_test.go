@@ -0,0 +1,78 @@
+package event_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/event"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Event", func() {
+	var output *bytes.Buffer
+
+	BeforeEach(func() {
+		output = new(bytes.Buffer)
+	})
+
+	AfterEach(func() {
+		event.SetOutput(nil)
+	})
+
+	decode := func() []map[string]interface{} {
+		var events []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+			var decoded map[string]interface{}
+			Expect(json.Unmarshal([]byte(line), &decoded)).To(Succeed())
+			events = append(events, decoded)
+		}
+		return events
+	}
+
+	It("does not record anything until an output is set", func() {
+		event.Record(event.Event{Type: event.StepStarted})
+
+		Expect(event.Enabled()).To(BeFalse())
+		Expect(output.String()).To(BeEmpty())
+	})
+
+	It("records each event as a line of JSON", func() {
+		event.SetOutput(output)
+
+		event.Record(event.Event{Type: event.JobFinished, Deployment: "redis", Job: "redis-server"}.WithResult(nil))
+		event.Record(event.Event{Type: event.JobFinished, Deployment: "redis", Job: "other"}.WithResult(errors.New("lock failed")))
+
+		events := decode()
+		Expect(events).To(HaveLen(2))
+		Expect(events[0]).To(HaveKeyWithValue("type", "job_finished"))
+		Expect(events[0]).To(HaveKeyWithValue("deployment", "redis"))
+		Expect(events[0]).To(HaveKeyWithValue("result", "success"))
+		Expect(events[0]).To(HaveKey("time"))
+		Expect(events[0]).NotTo(HaveKey("error"))
+		Expect(events[1]).To(HaveKeyWithValue("result", "failure"))
+		Expect(events[1]).To(HaveKeyWithValue("error", "lock failed"))
+	})
+
+	It("records the exit code of the command, including zero", func() {
+		event.SetOutput(output)
+
+		event.RecordCommandFinished(0, nil)
+
+		Expect(decode()[0]).To(HaveKeyWithValue("exit_code", BeNumerically("==", 0)))
+	})
+
+	It("only records the first outcome of the command", func() {
+		event.SetOutput(output)
+
+		event.RecordCommandFinished(4, []string{"lock"})
+		event.RecordCommandFinished(1, nil)
+
+		events := decode()
+		Expect(events).To(HaveLen(1))
+		Expect(events[0]).To(HaveKeyWithValue("exit_code", BeNumerically("==", 4)))
+		Expect(events[0]).To(HaveKeyWithValue("error_types", ConsistOf("lock")))
+	})
+})
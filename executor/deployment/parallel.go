@@ -1,16 +1,23 @@
 package deployment
 
 func NewParallelExecutor() ParallelExecutor {
-	return ParallelExecutor{}
+	return ParallelExecutor{
+		maxInFlight: 10,
+	}
 }
 
 type ParallelExecutor struct {
+	maxInFlight int
+}
+
+func (s *ParallelExecutor) SetMaxInFlight(maxInFlight int) {
+	s.maxInFlight = maxInFlight
 }
 
 func (s ParallelExecutor) Run(executables []Executable) []DeploymentError {
 	var errors []DeploymentError
 
-	guard := make(chan bool, 10)
+	guard := make(chan bool, s.maxInFlight)
 	errs := make(chan DeploymentError, len(executables))
 
 	for _, executable := range executables {
@@ -1,6 +1,10 @@
 package executor_test
 
 import (
+	"context"
+	"sync/atomic"
+	"time"
+
 	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor/fakes"
@@ -13,37 +17,41 @@ var _ = Describe("Executor", func() {
 	ExecutorTests := func(name string, executor Executor) {
 		Describe(name, func() {
 			var errs []error
+			var ctx context.Context
+			var cancel context.CancelFunc
 			var executable1, executable2, executable3, executable4 *fakes.FakeExecutable
 			var orderOfExecution []string
 
 			BeforeEach(func() {
+				ctx, cancel = context.WithCancel(context.Background())
+
 				executable1 = new(fakes.FakeExecutable)
-				executable1.ExecuteStub = func() error {
+				executable1.ExecuteStub = func(context.Context) error {
 					orderOfExecution = append(orderOfExecution, "executable1")
 					return nil
 				}
 
 				executable2 = new(fakes.FakeExecutable)
-				executable2.ExecuteStub = func() error {
+				executable2.ExecuteStub = func(context.Context) error {
 					orderOfExecution = append(orderOfExecution, "executable2")
 					return nil
 				}
 
 				executable3 = new(fakes.FakeExecutable)
-				executable3.ExecuteStub = func() error {
+				executable3.ExecuteStub = func(context.Context) error {
 					orderOfExecution = append(orderOfExecution, "executable3")
 					return nil
 				}
 
 				executable4 = new(fakes.FakeExecutable)
-				executable4.ExecuteStub = func() error {
+				executable4.ExecuteStub = func(context.Context) error {
 					orderOfExecution = append(orderOfExecution, "executable4")
 					return nil
 				}
 			})
 
 			JustBeforeEach(func() {
-				errs = executor.Run([][]Executable{
+				errs = executor.Run(ctx, [][]Executable{
 					{executable1},
 					{executable2, executable3},
 					{executable4},
@@ -81,9 +89,63 @@ var _ = Describe("Executor", func() {
 					Expect(executable4.ExecuteCallCount()).To(Equal(1))
 				})
 			})
+
+			Context("when the context is done", func() {
+				BeforeEach(func() {
+					executable1.ExecuteStub = func(context.Context) error {
+						cancel()
+						return errors.New("error from executable1")
+					}
+				})
+
+				It("does not start any more executables, and returns the context's error", func() {
+					Expect(errs).To(ConsistOf(
+						MatchError("error from executable1"),
+						Equal(context.Canceled),
+					))
+
+					Expect(executable1.ExecuteCallCount()).To(Equal(1))
+					Expect(executable2.ExecuteCallCount()).To(Equal(0))
+					Expect(executable3.ExecuteCallCount()).To(Equal(0))
+					Expect(executable4.ExecuteCallCount()).To(Equal(0))
+				})
+			})
+
+			It("passes the context to the executables", func() {
+				Expect(executable1.ExecuteArgsForCall(0)).To(Equal(ctx))
+			})
 		})
 	}
 
 	ExecutorTests("SerialExecutor", NewSerialExecutor())
 	ExecutorTests("ParallelExecutor", NewParallelExecutor())
+
+	Describe("ParallelExecutor with a maximum in flight", func() {
+		It("never runs more executables at once than the maximum", func() {
+			var inFlight, mostInFlight int32
+			var executables []Executable
+			for i := 0; i < 10; i++ {
+				executable := new(fakes.FakeExecutable)
+				executable.ExecuteStub = func(context.Context) error {
+					current := atomic.AddInt32(&inFlight, 1)
+					for {
+						most := atomic.LoadInt32(&mostInFlight)
+						if current <= most || atomic.CompareAndSwapInt32(&mostInFlight, most, current) {
+							break
+						}
+					}
+					time.Sleep(10 * time.Millisecond)
+					atomic.AddInt32(&inFlight, -1)
+					return nil
+				}
+				executables = append(executables, executable)
+			}
+
+			executor := NewParallelExecutor()
+			executor.SetMaxInFlight(2)
+
+			Expect(executor.Run(context.Background(), [][]Executable{executables})).To(BeEmpty())
+			Expect(atomic.LoadInt32(&mostInFlight)).To(Equal(int32(2)))
+		})
+	})
 })
@@ -2,16 +2,19 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 )
 
 type FakeExecutable struct {
-	ExecuteStub        func() error
+	ExecuteStub        func(context.Context) error
 	executeMutex       sync.RWMutex
-	executeArgsForCall []struct{}
-	executeReturns     struct {
+	executeArgsForCall []struct {
+		arg1 context.Context
+	}
+	executeReturns struct {
 		result1 error
 	}
 	executeReturnsOnCall map[int]struct {
@@ -21,14 +24,16 @@ type FakeExecutable struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeExecutable) Execute() error {
+func (fake *FakeExecutable) Execute(arg1 context.Context) error {
 	fake.executeMutex.Lock()
 	ret, specificReturn := fake.executeReturnsOnCall[len(fake.executeArgsForCall)]
-	fake.executeArgsForCall = append(fake.executeArgsForCall, struct{}{})
-	fake.recordInvocation("Execute", []interface{}{})
+	fake.executeArgsForCall = append(fake.executeArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Execute", []interface{}{arg1})
 	fake.executeMutex.Unlock()
 	if fake.ExecuteStub != nil {
-		return fake.ExecuteStub()
+		return fake.ExecuteStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -42,6 +47,12 @@ func (fake *FakeExecutable) ExecuteCallCount() int {
 	return len(fake.executeArgsForCall)
 }
 
+func (fake *FakeExecutable) ExecuteArgsForCall(i int) context.Context {
+	fake.executeMutex.RLock()
+	defer fake.executeMutex.RUnlock()
+	return fake.executeArgsForCall[i].arg1
+}
+
 func (fake *FakeExecutable) ExecuteReturns(result1 error) {
 	fake.ExecuteStub = nil
 	fake.executeReturns = struct {
@@ -2,16 +2,18 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 )
 
 type FakeExecutor struct {
-	RunStub        func([][]executor.Executable) []error
+	RunStub        func(context.Context, [][]executor.Executable) []error
 	runMutex       sync.RWMutex
 	runArgsForCall []struct {
-		arg1 [][]executor.Executable
+		arg1 context.Context
+		arg2 [][]executor.Executable
 	}
 	runReturns struct {
 		result1 []error
@@ -23,21 +25,22 @@ type FakeExecutor struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeExecutor) Run(arg1 [][]executor.Executable) []error {
-	var arg1Copy [][]executor.Executable
-	if arg1 != nil {
-		arg1Copy = make([][]executor.Executable, len(arg1))
-		copy(arg1Copy, arg1)
+func (fake *FakeExecutor) Run(arg1 context.Context, arg2 [][]executor.Executable) []error {
+	var arg2Copy [][]executor.Executable
+	if arg2 != nil {
+		arg2Copy = make([][]executor.Executable, len(arg2))
+		copy(arg2Copy, arg2)
 	}
 	fake.runMutex.Lock()
 	ret, specificReturn := fake.runReturnsOnCall[len(fake.runArgsForCall)]
 	fake.runArgsForCall = append(fake.runArgsForCall, struct {
-		arg1 [][]executor.Executable
-	}{arg1Copy})
-	fake.recordInvocation("Run", []interface{}{arg1Copy})
+		arg1 context.Context
+		arg2 [][]executor.Executable
+	}{arg1, arg2Copy})
+	fake.recordInvocation("Run", []interface{}{arg1, arg2Copy})
 	fake.runMutex.Unlock()
 	if fake.RunStub != nil {
-		return fake.RunStub(arg1)
+		return fake.RunStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -51,10 +54,10 @@ func (fake *FakeExecutor) RunCallCount() int {
 	return len(fake.runArgsForCall)
 }
 
-func (fake *FakeExecutor) RunArgsForCall(i int) [][]executor.Executable {
+func (fake *FakeExecutor) RunArgsForCall(i int) (context.Context, [][]executor.Executable) {
 	fake.runMutex.RLock()
 	defer fake.runMutex.RUnlock()
-	return fake.runArgsForCall[i].arg1
+	return fake.runArgsForCall[i].arg1, fake.runArgsForCall[i].arg2
 }
 
 func (fake *FakeExecutor) RunReturns(result1 []error) {
@@ -1,5 +1,7 @@
 package executor
 
+import "context"
+
 func NewParallelExecutor() ParallelExecutor {
 	return ParallelExecutor{
 		maxInFlight: 10,
@@ -10,30 +12,42 @@ type ParallelExecutor struct {
 	maxInFlight int
 }
 
-func (s ParallelExecutor) SetMaxInFlight(maxInFlight int) {
+func (s *ParallelExecutor) SetMaxInFlight(maxInFlight int) {
 	s.maxInFlight = maxInFlight
 }
 
-func (s ParallelExecutor) Run(executablesList [][]Executable) []error {
+// Run runs each batch of executables in turn, running the executables of a batch at once.
+// Once ctx is done, no more executables are started and its error is returned with theirs.
+func (s ParallelExecutor) Run(ctx context.Context, executablesList [][]Executable) []error {
 	var errors []error
 	for _, executables := range executablesList {
 		guard := make(chan bool, s.maxInFlight)
 		errs := make(chan error, len(executables))
 
+		started := 0
 		for _, executable := range executables {
 			guard <- true
+			if ctx.Err() != nil {
+				break
+			}
+
+			started++
 			go func(executable Executable) {
-				errs <- executable.Execute()
+				errs <- executable.Execute(ctx)
 				<-guard
 			}(executable)
 		}
 
-		for range executables {
+		for i := 0; i < started; i++ {
 			err := <-errs
 			if err != nil {
 				errors = append(errors, err)
 			}
 		}
+
+		if started < len(executables) {
+			return append(errors, ctx.Err())
+		}
 	}
 
 	return errors
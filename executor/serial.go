@@ -1,5 +1,7 @@
 package executor
 
+import "context"
+
 func NewSerialExecutor() SerialExecutor {
 	return SerialExecutor{}
 }
@@ -7,11 +9,15 @@ func NewSerialExecutor() SerialExecutor {
 type SerialExecutor struct {
 }
 
-func (s SerialExecutor) Run(executablesList [][]Executable) []error {
+func (s SerialExecutor) Run(ctx context.Context, executablesList [][]Executable) []error {
 	var errors []error
 	for _, executables := range executablesList {
 		for _, executable := range executables {
-			if err := executable.Execute(); err != nil {
+			if ctx.Err() != nil {
+				return append(errors, ctx.Err())
+			}
+
+			if err := executable.Execute(ctx); err != nil {
 				errors = append(errors, err)
 			}
 		}
@@ -2,13 +2,18 @@ package factory
 
 import (
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bosh"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 
 	boshcmd "github.com/cloudfoundry/bosh-cli/cmd"
 	boshsys "github.com/cloudfoundry/bosh-utils/system"
 )
 
-func BuildBoshClient(targetUrl, username, password, caCertPathOrValue string, logger boshlog.Logger) (bosh.Client, error) {
+func BuildBoshClient(targetUrl, username, password, caCertPathOrValue string, settings Settings, logger boshlog.Logger) (bosh.Client, error) {
+	return buildBoshClient(targetUrl, username, password, caCertPathOrValue, settings, buildJobFinder(settings, logger, buildScriptEnvironment(settings), nil), logger)
+}
+
+func buildBoshClient(targetUrl, username, password, caCertPathOrValue string, settings Settings, jobFinder instance.JobFinder, logger boshlog.Logger) (bosh.Client, error) {
 	var boshClient bosh.Client
 	var err error
 	fs := boshsys.NewOsFileSystem(logger)
@@ -20,7 +25,7 @@ func BuildBoshClient(targetUrl, username, password, caCertPathOrValue string, lo
 		return boshClient, err
 	}
 
-	boshClient, err = bosh.BuildClient(targetUrl, username, password, caCertArg.Content, logger)
+	boshClient, err = bosh.BuildClient(targetUrl, username, password, caCertArg.Content, buildRemoteRunnerFactory(settings), jobFinder, logger)
 	if err != nil {
 		return boshClient, err
 	}
@@ -0,0 +1,43 @@
+package factory
+
+import (
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor/deployment"
+)
+
+// ConcurrencyLimits caps how many instances and deployments are worked on at once. A phase
+// limit of 0 falls back to the instance limit, and Serial runs everything one at a time.
+type ConcurrencyLimits struct {
+	Instances     int
+	Deployments   int
+	LockUnlock    int
+	BackupScripts int
+	Drain         int
+	Serial        bool
+}
+
+var DefaultConcurrency = ConcurrencyLimits{Instances: 10, Deployments: 10}
+
+func BuildDeploymentExecutor(concurrency ConcurrencyLimits) deployment.DeploymentExecutor {
+	if concurrency.Serial {
+		return deployment.NewSerialExecutor()
+	}
+
+	execr := deployment.NewParallelExecutor()
+	execr.SetMaxInFlight(concurrency.Deployments)
+	return execr
+}
+
+func buildInstanceExecutor(concurrency ConcurrencyLimits, phaseLimit int) executor.Executor {
+	if concurrency.Serial {
+		return executor.NewSerialExecutor()
+	}
+
+	execr := executor.NewParallelExecutor()
+	if phaseLimit > 0 {
+		execr.SetMaxInFlight(phaseLimit)
+	} else {
+		execr.SetMaxInFlight(concurrency.Instances)
+	}
+	return execr
+}
@@ -2,7 +2,6 @@ package factory
 
 import (
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bosh"
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
 	"github.com/cloudfoundry/bosh-utils/logger"
@@ -13,10 +12,12 @@ func BuildDeploymentBackupCleanuper(
 	username string,
 	password string,
 	caCert string,
+	settings Settings,
 	logger logger.Logger,
+	journalDir string,
 ) (*orchestrator.BackupCleaner, error) {
 
-	boshClient, err := BuildBoshClient(target, username, password, caCert, logger)
+	boshClient, err := BuildBoshClient(target, username, password, caCert, settings, logger)
 
 	if err != nil {
 		return nil, err
@@ -24,8 +25,9 @@ func BuildDeploymentBackupCleanuper(
 
 	return orchestrator.NewBackupCleaner(
 		logger,
-		bosh.NewDeploymentManager(boshClient, logger, false),
+		bosh.NewDeploymentManager(boshClient, logger, false, orchestrator.Selection{}),
 		orderer.NewKahnBackupLockOrderer(),
-		executor.NewParallelExecutor(),
+		buildInstanceExecutor(settings.Concurrency, settings.Concurrency.Instances),
+		orchestrator.NewJournals(journalDir),
 	), nil
 }
@@ -5,7 +5,6 @@ import (
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bosh"
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
@@ -17,24 +16,36 @@ func BuildDeploymentBackuper(
 	password,
 	caCert string,
 	withManifest bool,
+	compression string,
+	encryptionKey []byte,
+	selection orchestrator.Selection,
+	settings Settings,
 	logger boshlog.Logger,
 	timestamp string,
+	journalDir string,
 ) (*orchestrator.Backuper, error) {
-	boshClient, err := BuildBoshClient(target, username, password, caCert, logger)
+	scriptEnvironment := buildScriptEnvironment(settings)
+	scriptLogs := orchestrator.NewScriptLogs()
+	boshClient, err := buildBoshClient(target, username, password, caCert, settings, buildJobFinder(settings, logger, scriptEnvironment, scriptLogs), logger)
 	if err != nil {
 		return nil, err
 	}
 
-	execr := executor.NewParallelExecutor()
-
 	return orchestrator.NewBackuper(
-		backup.BackupDirectoryManager{},
+		backup.BackupDirectoryManager{Kind: backup.KindDeployment, Compression: compression, EncryptionKey: encryptionKey, BackupSelection: selection},
 		logger,
-		bosh.NewDeploymentManager(boshClient, logger, withManifest),
+		bosh.NewDeploymentManager(boshClient, logger, withManifest, selection),
 		orderer.NewKahnBackupLockOrderer(),
-		execr,
+		buildInstanceExecutor(settings.Concurrency, settings.Concurrency.LockUnlock),
+		buildInstanceExecutor(settings.Concurrency, settings.Concurrency.BackupScripts),
 		time.Now,
-		orchestrator.NewArtifactCopier(execr, logger),
+		orchestrator.NewArtifactCopier(buildInstanceExecutor(settings.Concurrency, settings.Concurrency.Drain), settings.TransferThrottle, settings.TransferProgress, logger),
 		timestamp,
+		scriptEnvironment,
+		scriptLogs,
+		orchestrator.NewJournals(journalDir),
+		settings.Abort,
+		settings.LockTimeout,
+		"Run `bbr deployment backup --resume <backup directory>` to drain the rest of it, or `bbr deployment backup-cleanup` to remove it.",
 	), nil
 }
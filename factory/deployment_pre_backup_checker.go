@@ -8,7 +8,10 @@ import (
 
 func BuildDeploymentBackupChecker(boshClient bosh.Client,
 	logger bosh.Logger,
-	withManifest bool) *orchestrator.BackupChecker {
+	withManifest bool,
+	selection orchestrator.Selection,
+	concurrency ConcurrencyLimits) *orchestrator.BackupChecker {
 	return orchestrator.NewBackupChecker(logger,
-		bosh.NewDeploymentManager(boshClient, logger, withManifest), orderer.NewKahnBackupLockOrderer())
+		bosh.NewDeploymentManager(boshClient, logger, withManifest, selection), orderer.NewKahnBackupLockOrderer(),
+		buildInstanceExecutor(concurrency, concurrency.LockUnlock))
 }
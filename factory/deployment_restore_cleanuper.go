@@ -5,22 +5,24 @@ import (
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
+	"github.com/cloudfoundry/bosh-utils/logger"
 )
 
 func BuildDeploymentRestoreCleanuper(target,
 	usename,
 	password,
 	caCert string,
-	withManifest,
-	isDebug bool) (*orchestrator.RestoreCleaner, error) {
-
-	logger := BuildLogger(isDebug)
+	withManifest bool,
+	settings Settings,
+	logger logger.Logger,
+	journalDir string) (*orchestrator.RestoreCleaner, error) {
 
 	boshClient, err := BuildBoshClient(
 		target,
 		usename,
 		password,
 		caCert,
+		settings,
 		logger,
 	)
 
@@ -29,5 +31,5 @@ func BuildDeploymentRestoreCleanuper(target,
 	}
 
 	return orchestrator.NewRestoreCleaner(logger,
-		bosh.NewDeploymentManager(boshClient, logger, withManifest), orderer.NewKahnRestoreLockOrderer(), executor.NewSerialExecutor()), nil
+		bosh.NewDeploymentManager(boshClient, logger, withManifest, orchestrator.Selection{}), orderer.NewKahnRestoreLockOrderer(), executor.NewSerialExecutor(), orchestrator.NewJournals(journalDir)), nil
 }
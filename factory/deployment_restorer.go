@@ -6,15 +6,18 @@ import (
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 )
 
-func BuildDeploymentRestorer(target, username, password, caCert string, debug bool) (*orchestrator.Restorer, error) {
-	logger := BuildLogger(debug)
-	boshClient, err := BuildBoshClient(
+func BuildDeploymentRestorer(target, username, password, caCert string, encryptionKey []byte, selection orchestrator.Selection, settings Settings, logger boshlog.Logger, journalDir string) (*orchestrator.Restorer, error) {
+	scriptEnvironment := buildScriptEnvironment(settings)
+	boshClient, err := buildBoshClient(
 		target,
 		username,
 		password,
 		caCert,
+		settings,
+		buildJobFinder(settings, logger, scriptEnvironment, nil),
 		logger,
 	)
 	if err != nil {
@@ -22,11 +25,15 @@ func BuildDeploymentRestorer(target, username, password, caCert string, debug bo
 	}
 
 	return orchestrator.NewRestorer(
-		backup.BackupDirectoryManager{},
+		backup.BackupDirectoryManager{EncryptionKey: encryptionKey, RestoreSelection: selection},
 		logger,
-		bosh.NewDeploymentManager(boshClient, logger, false),
+		bosh.NewDeploymentManager(boshClient, logger, false, selection),
 		orderer.NewKahnRestoreLockOrderer(),
 		executor.NewSerialExecutor(),
-		orchestrator.NewArtifactCopier(executor.NewParallelExecutor(), logger),
+		orchestrator.NewArtifactCopier(buildInstanceExecutor(settings.Concurrency, settings.Concurrency.Drain), settings.TransferThrottle, settings.TransferProgress, logger),
+		scriptEnvironment,
+		orchestrator.NewJournals(journalDir),
+		settings.Abort,
+		settings.LockTimeout,
 	), nil
 }
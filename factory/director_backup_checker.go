@@ -1,22 +1,21 @@
 package factory
 
 import (
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/standalone"
 )
 
-func BuildDirectorBackupChecker(host, username, privateKeyPath string, hasDebug bool) *orchestrator.BackupChecker {
+func BuildDirectorBackupChecker(host, username, privateKeyPath string, hasDebug bool, settings Settings) *orchestrator.BackupChecker {
 	logger := BuildLogger(hasDebug)
 	deploymentManager := standalone.NewDeploymentManager(logger,
 		host,
 		username,
 		privateKeyPath,
-		instance.NewJobFinder(logger),
-		ssh.NewSshRemoteRunner,
+		buildJobFinder(settings, logger, buildScriptEnvironment(settings), nil),
+		buildRemoteRunnerFactory(settings),
 	)
 
-	return orchestrator.NewBackupChecker(logger, deploymentManager, orderer.NewDirectorLockOrderer())
+	return orchestrator.NewBackupChecker(logger, deploymentManager, orderer.NewDirectorLockOrderer(), executor.NewParallelExecutor())
 }
@@ -2,26 +2,26 @@ package factory
 
 import (
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/standalone"
 )
 
 func BuildDirectorBackupCleaner(host,
 	username,
 	privateKeyPath string,
-	hasDebug bool) *orchestrator.BackupCleaner {
+	hasDebug bool,
+	settings Settings,
+	journalDir string) *orchestrator.BackupCleaner {
 
 	logger := BuildLogger(hasDebug)
 	deploymentManager := standalone.NewDeploymentManager(logger,
 		host,
 		username,
 		privateKeyPath,
-		instance.NewJobFinder(logger),
-		ssh.NewSshRemoteRunner,
+		buildJobFinder(settings, logger, buildScriptEnvironment(settings), nil),
+		buildRemoteRunnerFactory(settings),
 	)
 
-	return orchestrator.NewBackupCleaner(logger, deploymentManager, orderer.NewDirectorLockOrderer(), executor.NewParallelExecutor())
+	return orchestrator.NewBackupCleaner(logger, deploymentManager, orderer.NewDirectorLockOrderer(), executor.NewParallelExecutor(), orchestrator.NewJournals(journalDir))
 }
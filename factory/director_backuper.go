@@ -5,32 +5,39 @@ import (
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/standalone"
 )
 
-func BuildDirectorBackuper(host, username, privateKeyPath string, hasDebug bool, timeStamp string) *orchestrator.Backuper {
+func BuildDirectorBackuper(host, username, privateKeyPath, compression string, encryptionKey []byte, hasDebug bool, settings Settings, timeStamp, journalDir string) *orchestrator.Backuper {
 	logger := BuildLogger(hasDebug)
+	scriptEnvironment := buildScriptEnvironment(settings)
+	scriptLogs := orchestrator.NewScriptLogs()
 	deploymentManager := standalone.NewDeploymentManager(logger,
 		host,
 		username,
 		privateKeyPath,
-		instance.NewJobFinder(logger),
-		ssh.NewSshRemoteRunner,
+		buildJobFinder(settings, logger, scriptEnvironment, scriptLogs),
+		buildRemoteRunnerFactory(settings),
 	)
 	execr := executor.NewParallelExecutor()
 
 	return orchestrator.NewBackuper(
-		backup.BackupDirectoryManager{},
+		backup.BackupDirectoryManager{Kind: backup.KindDirector, Compression: compression, EncryptionKey: encryptionKey},
 		logger,
 		deploymentManager,
 		orderer.NewDirectorLockOrderer(),
 		execr,
+		execr,
 		time.Now,
-		orchestrator.NewArtifactCopier(execr, logger),
+		orchestrator.NewArtifactCopier(execr, settings.TransferThrottle, settings.TransferProgress, logger),
 		timeStamp,
+		scriptEnvironment,
+		scriptLogs,
+		orchestrator.NewJournals(journalDir),
+		settings.Abort,
+		settings.LockTimeout,
+		"Run `bbr director backup-cleanup` to remove it.",
 	)
 }
@@ -2,17 +2,17 @@ package factory
 
 import (
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/standalone"
 )
 
 func BuildDirectorRestoreCleaner(host,
 	username,
 	privateKeyPath string,
-	hasDebug bool) *orchestrator.RestoreCleaner {
+	hasDebug bool,
+	settings Settings,
+	journalDir string) *orchestrator.RestoreCleaner {
 
 	logger := BuildLogger(hasDebug)
 
@@ -20,9 +20,9 @@ func BuildDirectorRestoreCleaner(host,
 		host,
 		username,
 		privateKeyPath,
-		instance.NewJobFinder(logger),
-		ssh.NewSshRemoteRunner,
+		buildJobFinder(settings, logger, buildScriptEnvironment(settings), nil),
+		buildRemoteRunnerFactory(settings),
 	)
 
-	return orchestrator.NewRestoreCleaner(logger, deploymentManager, orderer.NewDirectorLockOrderer(), executor.NewSerialExecutor())
+	return orchestrator.NewRestoreCleaner(logger, deploymentManager, orderer.NewDirectorLockOrderer(), executor.NewSerialExecutor(), orchestrator.NewJournals(journalDir))
 }
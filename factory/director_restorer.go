@@ -3,29 +3,32 @@ package factory
 import (
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/standalone"
 )
 
-func BuildDirectorRestorer(host, username, privateKeyPath string, hasDebug bool) *orchestrator.Restorer {
+func BuildDirectorRestorer(host, username, privateKeyPath string, encryptionKey []byte, hasDebug bool, settings Settings, journalDir string) *orchestrator.Restorer {
 	logger := BuildLogger(hasDebug)
+	scriptEnvironment := buildScriptEnvironment(settings)
 	deploymentManager := standalone.NewDeploymentManager(logger,
 		host,
 		username,
 		privateKeyPath,
-		instance.NewJobFinder(logger),
-		ssh.NewSshRemoteRunner,
+		buildJobFinder(settings, logger, scriptEnvironment, nil),
+		buildRemoteRunnerFactory(settings),
 	)
 
 	return orchestrator.NewRestorer(
-		backup.BackupDirectoryManager{},
+		backup.BackupDirectoryManager{EncryptionKey: encryptionKey},
 		logger,
 		deploymentManager,
 		orderer.NewDirectorLockOrderer(),
 		executor.NewSerialExecutor(),
-		orchestrator.NewArtifactCopier(executor.NewParallelExecutor(), logger),
+		orchestrator.NewArtifactCopier(executor.NewParallelExecutor(), settings.TransferThrottle, settings.TransferProgress, logger),
+		scriptEnvironment,
+		orchestrator.NewJournals(journalDir),
+		settings.Abort,
+		settings.LockTimeout,
 	)
 }
@@ -0,0 +1,24 @@
+package factory
+
+import (
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
+)
+
+func buildScriptEnvironment(settings Settings) *orchestrator.ScriptEnvironment {
+	return orchestrator.NewScriptEnvironment(settings.Version, settings.ScriptEnvironmentVariables)
+}
+
+func buildJobFinder(settings Settings, logger instance.Logger, scriptEnvironment *orchestrator.ScriptEnvironment, scriptLogs *orchestrator.ScriptLogs) instance.JobFinder {
+	return &instance.JobFinderFromScripts{
+		Logger:            logger,
+		ScriptTimeouts:    settings.ScriptTimeouts,
+		ScriptEnvironment: scriptEnvironment,
+		ScriptLogs:        scriptLogs,
+	}
+}
+
+func buildRemoteRunnerFactory(settings Settings) ssh.RemoteRunnerFactory {
+	return ssh.NewSshRemoteRunnerFactory(settings.SSHRetryPolicy)
+}
@@ -0,0 +1,43 @@
+package factory
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/progress"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ratelimit"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
+)
+
+// Settings are the options of a command which configure how the backupers, restorers and
+// cleaners built for it run.
+type Settings struct {
+	Version                    string
+	Concurrency                ConcurrencyLimits
+	SSHRetryPolicy             ssh.RetryPolicy
+	TransferThrottle           ratelimit.Throttle
+	TransferProgress           *progress.Reporter
+	ScriptTimeouts             instance.ScriptTimeouts
+	ScriptEnvironmentVariables map[string]string
+
+	// LockTimeout is the longest jobs may stay locked during a backup or restore, if not zero
+	LockTimeout time.Duration
+
+	// Abort is shared by the backupers and restorers of a command, so that a signal aborts
+	// all of them. Without one, they cannot be aborted.
+	Abort *orchestrator.Abort
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		Concurrency:      DefaultConcurrency,
+		SSHRetryPolicy:   ssh.DefaultRetryPolicy,
+		TransferProgress: progress.NewReporter(progress.DefaultInterval, nil),
+	}
+}
+
+// BuildAbort returns an abort which interrupts the commands running on instances
+func BuildAbort() *orchestrator.Abort {
+	return orchestrator.NewAbort(ssh.Sessions)
+}
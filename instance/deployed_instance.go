@@ -1,6 +1,7 @@
 package instance
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
@@ -38,6 +39,12 @@ func (i *DeployedInstance) RemoveArtifactDir() error {
 	return i.remoteRunner.RemoveDirectory(orchestrator.ArtifactDirectory)
 }
 
+func (i *DeployedInstance) CloseConnection() {
+	if err := i.remoteRunner.Close(); err != nil {
+		i.Logger.Debug("bbr", "Failed to close SSH connection to %s/%s: %s", i.instanceGroupName, i.instanceID, err)
+	}
+}
+
 func (i *DeployedInstance) IsBackupable() bool {
 	return i.jobs.AnyAreBackupable()
 }
@@ -50,6 +57,11 @@ func (i *DeployedInstance) MarkArtifactDirCreated() {
 	i.artifactDirCreated = true
 }
 
+// KeepArtifactDir stops Cleanup from removing the artifact directory
+func (i *DeployedInstance) KeepArtifactDir() {
+	i.artifactDirCreated = false
+}
+
 func (i *DeployedInstance) CustomBackupArtifactNames() []string {
 	return i.jobs.CustomBackupArtifactNames()
 }
@@ -62,10 +74,10 @@ func (i *DeployedInstance) Jobs() []orchestrator.Job {
 	return i.jobs
 }
 
-func (i *DeployedInstance) Backup() error {
+func (i *DeployedInstance) Backup(ctx context.Context) error {
 	var backupErrors []error
 	for _, job := range i.jobs {
-		if err := job.Backup(); err != nil {
+		if err := job.Backup(ctx); err != nil {
 			backupErrors = append(backupErrors, err)
 		}
 	}
@@ -84,10 +96,10 @@ func artifactDirectoryVariables(artifactDirectory string) map[string]string {
 	}
 }
 
-func (i *DeployedInstance) Restore() error {
+func (i *DeployedInstance) Restore(ctx context.Context) error {
 	var restoreErrors []error
 	for _, job := range i.jobs {
-		if err := job.Restore(); err != nil {
+		if err := job.Restore(ctx); err != nil {
 			restoreErrors = append(restoreErrors, err)
 		}
 	}
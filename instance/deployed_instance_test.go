@@ -1,9 +1,12 @@
 package instance_test
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
@@ -206,7 +209,7 @@ var _ = Describe("DeployedInstance", func() {
 		var err error
 
 		JustBeforeEach(func() {
-			err = deployedInstance.Backup()
+			err = deployedInstance.Backup(context.Background())
 		})
 
 		Context("when there are multiple backup scripts in multiple job directories", func() {
@@ -238,26 +241,26 @@ var _ = Describe("DeployedInstance", func() {
 					"/var/vcap/store/bbr-backup/baz",
 				))
 
-				specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				_, specifiedScriptPath, specifiedEnvVars, _, _, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/foo/bin/bbr/backup"))
-				Expect(specifiedEnvVars).To(Equal(map[string]string{
-					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/foo/",
-					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/foo/",
-				}))
+				Expect(specifiedEnvVars).To(SatisfyAll(
+					HaveKeyWithValue("ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/foo/"),
+					HaveKeyWithValue("BBR_ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/foo/"),
+				))
 
-				specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
+				_, specifiedScriptPath, specifiedEnvVars, _, _, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/bar/bin/bbr/backup"))
-				Expect(specifiedEnvVars).To(Equal(map[string]string{
-					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/bar/",
-					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/bar/",
-				}))
+				Expect(specifiedEnvVars).To(SatisfyAll(
+					HaveKeyWithValue("ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/bar/"),
+					HaveKeyWithValue("BBR_ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/bar/"),
+				))
 
-				specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(2)
+				_, specifiedScriptPath, specifiedEnvVars, _, _, _ = remoteRunner.RunScriptWithEnvArgsForCall(2)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/baz/bin/bbr/backup"))
-				Expect(specifiedEnvVars).To(Equal(map[string]string{
-					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/baz/",
-					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/baz/",
-				}))
+				Expect(specifiedEnvVars).To(SatisfyAll(
+					HaveKeyWithValue("ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/baz/"),
+					HaveKeyWithValue("BBR_ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/baz/"),
+				))
 			})
 
 			It("logs the paths to the scripts being run", func() {
@@ -289,6 +292,11 @@ var _ = Describe("DeployedInstance", func() {
 				Expect(deployedInstance.ArtifactDirCreated()).To(BeTrue())
 			})
 
+			It("stops counting the artifact directory as created once it is kept", func() {
+				deployedInstance.KeepArtifactDir()
+				Expect(deployedInstance.ArtifactDirCreated()).To(BeFalse())
+			})
+
 			It("succeeds", func() {
 				Expect(err).NotTo(HaveOccurred())
 			})
@@ -318,19 +326,19 @@ var _ = Describe("DeployedInstance", func() {
 					"/var/vcap/store/bbr-backup/foo",
 					"/var/vcap/store/bbr-backup/special-backup",
 				))
-				specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				_, specifiedScriptPath, specifiedEnvVars, _, _, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/foo/bin/bbr/backup"))
-				Expect(specifiedEnvVars).To(Equal(map[string]string{
-					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/foo/",
-					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/foo/",
-				}))
+				Expect(specifiedEnvVars).To(SatisfyAll(
+					HaveKeyWithValue("ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/foo/"),
+					HaveKeyWithValue("BBR_ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/foo/"),
+				))
 
-				specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
+				_, specifiedScriptPath, specifiedEnvVars, _, _, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/baz/bin/bbr/backup"))
-				Expect(specifiedEnvVars).To(Equal(map[string]string{
-					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/special-backup/",
-					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/special-backup/",
-				}))
+				Expect(specifiedEnvVars).To(SatisfyAll(
+					HaveKeyWithValue("ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/special-backup/"),
+					HaveKeyWithValue("BBR_ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/special-backup/"),
+				))
 
 			})
 		})
@@ -365,7 +373,7 @@ var _ = Describe("DeployedInstance", func() {
 					}, instance.Metadata{}),
 				})
 
-				remoteRunner.RunScriptWithEnvStub = func(cmd string, envVars map[string]string, label string) (string, error) {
+				remoteRunner.RunScriptWithEnvStub = func(ctx context.Context, cmd string, envVars map[string]string, label string, timeout time.Duration, output io.Writer) (string, error) {
 					if strings.Contains(cmd, "jobs/bar") {
 						return "", fmt.Errorf("no space left on device")
 					} else if strings.Contains(cmd, "jobs/baz") {
@@ -399,7 +407,7 @@ var _ = Describe("DeployedInstance", func() {
 		var actualError error
 
 		JustBeforeEach(func() {
-			actualError = deployedInstance.Restore()
+			actualError = deployedInstance.Restore(context.Background())
 		})
 
 		Context("when there are multiple restore scripts in multiple job directories", func() {
@@ -420,26 +428,26 @@ var _ = Describe("DeployedInstance", func() {
 			It("uses the remote runner to run each restore script providing the correct ARTIFACT_DIRECTORY", func() {
 				Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(3))
 
-				specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				_, specifiedScriptPath, specifiedEnvVars, _, _, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/foo/bin/bbr/restore"))
-				Expect(specifiedEnvVars).To(Equal(map[string]string{
-					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/foo/",
-					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/foo/",
-				}))
+				Expect(specifiedEnvVars).To(SatisfyAll(
+					HaveKeyWithValue("ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/foo/"),
+					HaveKeyWithValue("BBR_ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/foo/"),
+				))
 
-				specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
+				_, specifiedScriptPath, specifiedEnvVars, _, _, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/bar/bin/bbr/restore"))
-				Expect(specifiedEnvVars).To(Equal(map[string]string{
-					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/bar/",
-					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/bar/",
-				}))
+				Expect(specifiedEnvVars).To(SatisfyAll(
+					HaveKeyWithValue("ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/bar/"),
+					HaveKeyWithValue("BBR_ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/bar/"),
+				))
 
-				specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(2)
+				_, specifiedScriptPath, specifiedEnvVars, _, _, _ = remoteRunner.RunScriptWithEnvArgsForCall(2)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/baz/bin/bbr/restore"))
-				Expect(specifiedEnvVars).To(Equal(map[string]string{
-					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/baz/",
-					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/baz/",
-				}))
+				Expect(specifiedEnvVars).To(SatisfyAll(
+					HaveKeyWithValue("ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/baz/"),
+					HaveKeyWithValue("BBR_ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/baz/"),
+				))
 			})
 
 			It("logs the paths to the scripts being run", func() {
@@ -495,26 +503,26 @@ var _ = Describe("DeployedInstance", func() {
 			It("uses the remote runner to create each job's backup folder and run each backup script providing the correct BBR_ARTIFACT_DIRECTORY and ARTIFACT_DIRECTORY", func() {
 				Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(3))
 
-				specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				_, specifiedScriptPath, specifiedEnvVars, _, _, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/foo/bin/bbr/restore"))
-				Expect(specifiedEnvVars).To(Equal(map[string]string{
-					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/foo/",
-					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/foo/",
-				}))
+				Expect(specifiedEnvVars).To(SatisfyAll(
+					HaveKeyWithValue("ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/foo/"),
+					HaveKeyWithValue("BBR_ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/foo/"),
+				))
 
-				specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
+				_, specifiedScriptPath, specifiedEnvVars, _, _, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/bar/bin/bbr/restore"))
-				Expect(specifiedEnvVars).To(Equal(map[string]string{
-					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/bar/",
-					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/bar/",
-				}))
+				Expect(specifiedEnvVars).To(SatisfyAll(
+					HaveKeyWithValue("ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/bar/"),
+					HaveKeyWithValue("BBR_ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/bar/"),
+				))
 
-				specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(2)
+				_, specifiedScriptPath, specifiedEnvVars, _, _, _ = remoteRunner.RunScriptWithEnvArgsForCall(2)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/baz/bin/bbr/restore"))
-				Expect(specifiedEnvVars).To(Equal(map[string]string{
-					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/special-backup/",
-					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/special-backup/",
-				}))
+				Expect(specifiedEnvVars).To(SatisfyAll(
+					HaveKeyWithValue("ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/special-backup/"),
+					HaveKeyWithValue("BBR_ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/special-backup/"),
+				))
 			})
 		})
 
@@ -532,7 +540,7 @@ var _ = Describe("DeployedInstance", func() {
 					}, instance.Metadata{}),
 				})
 
-				remoteRunner.RunScriptWithEnvStub = func(cmd string, envVars map[string]string, label string) (string, error) {
+				remoteRunner.RunScriptWithEnvStub = func(ctx context.Context, cmd string, envVars map[string]string, label string, timeout time.Duration, output io.Writer) (string, error) {
 					if strings.Contains(cmd, "jobs/bar") {
 						return "", fmt.Errorf("no space left on device")
 					} else if strings.Contains(cmd, "jobs/baz") {
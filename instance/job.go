@@ -1,7 +1,10 @@
 package instance
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"strconv"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
@@ -9,6 +12,11 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	backupOperation  = "backup"
+	restoreOperation = "restore"
+)
+
 func NewJob(remoteRunner ssh.RemoteRunner, instanceIdentifier string, logger Logger, release string,
 	jobScripts BackupAndRestoreScripts, metadata Metadata) Job {
 	jobName := jobScripts[0].JobName()
@@ -21,9 +29,11 @@ func NewJob(remoteRunner ssh.RemoteRunner, instanceIdentifier string, logger Log
 		metadata:           metadata,
 		backupScript:       jobScripts.BackupOnly().firstOrBlank(),
 		restoreScript:      jobScripts.RestoreOnly().firstOrBlank(),
+		preBackupCheck:     jobScripts.PreBackupCheckOnly().firstOrBlank(),
 		preBackupScript:    jobScripts.PreBackupLockOnly().firstOrBlank(),
 		preRestoreScript:   jobScripts.PreRestoreLockOnly().firstOrBlank(),
 		postBackupScript:   jobScripts.PostBackupUnlockOnly().firstOrBlank(),
+		postBackupVerify:   jobScripts.PostBackupVerifyOnly().firstOrBlank(),
 		postRestoreScript:  jobScripts.SinglePostRestoreUnlockScript(),
 	}
 }
@@ -34,13 +44,33 @@ type Job struct {
 	release            string
 	metadata           Metadata
 	backupScript       Script
+	preBackupCheck     Script
 	preBackupScript    Script
 	postBackupScript   Script
+	postBackupVerify   Script
 	preRestoreScript   Script
 	restoreScript      Script
 	postRestoreScript  Script
 	remoteRunner       ssh.RemoteRunner
 	instanceIdentifier string
+	instance           InstanceIdentifier
+	environment        *orchestrator.ScriptEnvironment
+	logs               *orchestrator.ScriptLogs
+}
+
+// WithScriptEnvironment returns the job with scripts which are told about the instance
+// they run on and the backup being taken or restored.
+func (j Job) WithScriptEnvironment(instance InstanceIdentifier, environment *orchestrator.ScriptEnvironment) Job {
+	j.instance = instance
+	j.environment = environment
+	return j
+}
+
+// WithScriptLogs returns the job with lock, backup and unlock scripts whose output is
+// collected into the given logs.
+func (j Job) WithScriptLogs(logs *orchestrator.ScriptLogs) Job {
+	j.logs = logs
+	return j
 }
 
 func (j Job) Name() string {
@@ -83,6 +113,10 @@ func (j Job) HasRestore() bool {
 	return j.RestoreScript() != ""
 }
 
+func (j Job) HasPostBackupVerify() bool {
+	return j.postBackupVerify != ""
+}
+
 func (j Job) HasNamedBackupArtifact() bool {
 	return j.metadata.BackupName != ""
 }
@@ -91,7 +125,7 @@ func (j Job) HasNamedRestoreArtifact() bool {
 	return j.metadata.RestoreName != ""
 }
 
-func (j Job) Backup() error {
+func (j Job) Backup(ctx context.Context) error {
 	if j.backupScript != "" {
 		j.Logger.Debug("bbr", "> %s", j.backupScript)
 		j.Logger.Info("bbr", "Backing up %s on %s...", j.name, j.instanceIdentifier)
@@ -101,11 +135,13 @@ func (j Job) Backup() error {
 			return err
 		}
 
-		env := artifactDirectoryVariables(j.BackupArtifactDirectory())
-		_, err = j.remoteRunner.RunScriptWithEnv(
-			string(j.backupScript),
+		env := j.scriptEnvironment(backupOperation, artifactDirectoryVariables(j.BackupArtifactDirectory()))
+		err = j.runLoggedScript(
+			ctx,
+			j.backupScript,
 			env,
 			fmt.Sprintf("backup %s on %s", j.name, j.instanceIdentifier),
+			backupScriptName,
 		)
 
 		if err != nil {
@@ -124,14 +160,78 @@ func (j Job) Backup() error {
 	return nil
 }
 
-func (j Job) PreBackupLock() error {
+// PostBackupVerify runs the job's post-backup-verify script, if it has one, against the
+// artifact its backup script has just written.
+func (j Job) PostBackupVerify(ctx context.Context) error {
+	if j.postBackupVerify != "" {
+		j.Logger.Debug("bbr", "> %s", j.postBackupVerify)
+		j.Logger.Info("bbr", "Verifying backup of %s on %s...", j.name, j.instanceIdentifier)
+
+		err := j.runLoggedScript(
+			ctx,
+			j.postBackupVerify,
+			j.scriptEnvironment(backupOperation, artifactDirectoryVariables(j.BackupArtifactDirectory())),
+			fmt.Sprintf("post-backup verify %s on %s", j.name, j.instanceIdentifier),
+			postBackupVerifyScriptName,
+		)
+		if err != nil {
+			j.Logger.Error("bbr", "Error verifying backup of %s on %s.", j.name, j.instanceIdentifier)
+
+			return errors.Wrap(err, fmt.Sprintf(
+				"Error attempting to run post-backup-verify for job %s on %s",
+				j.Name(),
+				j.instanceIdentifier,
+			))
+		}
+
+		j.Logger.Info("bbr", "Finished verifying backup of %s on %s.", j.name, j.instanceIdentifier)
+	}
+
+	return nil
+}
+
+// PreBackupCheck runs the job's pre-backup-check script, if it has one, which fails when the
+// job is not in a state that can be backed up.
+func (j Job) PreBackupCheck(ctx context.Context) error {
+	if j.preBackupCheck != "" {
+		j.Logger.Debug("bbr", "> %s", j.preBackupCheck)
+		j.Logger.Info("bbr", "Checking %s on %s can be backed up...", j.name, j.instanceIdentifier)
+
+		_, err := j.remoteRunner.RunScriptWithEnv(
+			ctx,
+			string(j.preBackupCheck),
+			j.scriptEnvironment(backupOperation, nil),
+			fmt.Sprintf("pre-backup check %s on %s", j.name, j.instanceIdentifier),
+			j.metadata.ScriptTimeouts[preBackupCheckScriptName],
+			nil,
+		)
+		if err != nil {
+			j.Logger.Error("bbr", "Error checking %s on %s.", j.name, j.instanceIdentifier)
+
+			return errors.Wrap(err, fmt.Sprintf(
+				"Error attempting to run pre-backup-check for job %s on %s",
+				j.Name(),
+				j.instanceIdentifier,
+			))
+		}
+
+		j.Logger.Info("bbr", "Finished checking %s on %s.", j.name, j.instanceIdentifier)
+	}
+
+	return nil
+}
+
+func (j Job) PreBackupLock(ctx context.Context) error {
 	if j.preBackupScript != "" {
 		j.Logger.Debug("bbr", "> %s", j.preBackupScript)
 		j.Logger.Info("bbr", "Locking %s on %s for backup...", j.name, j.instanceIdentifier)
 
-		_, err := j.remoteRunner.RunScript(
-			string(j.preBackupScript),
+		err := j.runLoggedScript(
+			ctx,
+			j.preBackupScript,
+			j.scriptEnvironment(backupOperation, nil),
 			fmt.Sprintf("pre-backup lock %s on %s", j.name, j.instanceIdentifier),
+			preBackupLockScriptName,
 		)
 		if err != nil {
 			j.Logger.Error("bbr", "Error locking %s on %s.", j.name, j.instanceIdentifier)
@@ -149,17 +249,19 @@ func (j Job) PreBackupLock() error {
 	return nil
 }
 
-func (j Job) PostBackupUnlock(afterSuccessfulBackup bool) error {
+func (j Job) PostBackupUnlock(ctx context.Context, afterSuccessfulBackup bool) error {
 	if j.postBackupScript != "" {
 		j.Logger.Debug("bbr", "> %s", j.postBackupScript)
 		j.Logger.Info("bbr", "Unlocking %s on %s...", j.name, j.instanceIdentifier)
-		env := map[string]string{
+		env := j.scriptEnvironment(backupOperation, map[string]string{
 			"BBR_AFTER_BACKUP_SCRIPTS_SUCCESSFUL": strconv.FormatBool(afterSuccessfulBackup),
-		}
-		_, err := j.remoteRunner.RunScriptWithEnv(
-			string(j.postBackupScript),
+		})
+		err := j.runLoggedScript(
+			ctx,
+			j.postBackupScript,
 			env,
 			fmt.Sprintf("post-backup unlock %s on %s", j.name, j.instanceIdentifier),
+			postBackupUnlockScriptName,
 		)
 		if err != nil {
 			j.Logger.Error("bbr", "Error unlocking %s on %s.", j.name, j.instanceIdentifier)
@@ -177,14 +279,18 @@ func (j Job) PostBackupUnlock(afterSuccessfulBackup bool) error {
 	return nil
 }
 
-func (j Job) PreRestoreLock() error {
+func (j Job) PreRestoreLock(ctx context.Context) error {
 	if j.preRestoreScript != "" {
 		j.Logger.Debug("bbr", "> %s", j.preRestoreScript)
 		j.Logger.Info("bbr", "Locking %s on %s for restore...", j.name, j.instanceIdentifier)
 
-		_, err := j.remoteRunner.RunScript(
+		_, err := j.remoteRunner.RunScriptWithEnv(
+			ctx,
 			string(j.preRestoreScript),
+			j.scriptEnvironment(restoreOperation, nil),
 			fmt.Sprintf("pre-restore lock %s on %s", j.name, j.instanceIdentifier),
+			j.metadata.ScriptTimeouts[preRestoreLockScriptName],
+			nil,
 		)
 		if err != nil {
 			j.Logger.Error("bbr", "Error locking %s on %s.", j.name, j.instanceIdentifier)
@@ -202,15 +308,18 @@ func (j Job) PreRestoreLock() error {
 	return nil
 }
 
-func (j Job) Restore() error {
+func (j Job) Restore(ctx context.Context) error {
 	if j.restoreScript != "" {
 		j.Logger.Debug("bbr", "> %s", j.restoreScript)
 		j.Logger.Info("bbr", "Restoring %s on %s...", j.name, j.instanceIdentifier)
 
-		env := artifactDirectoryVariables(j.RestoreArtifactDirectory())
+		env := j.scriptEnvironment(restoreOperation, artifactDirectoryVariables(j.RestoreArtifactDirectory()))
 		_, err := j.remoteRunner.RunScriptWithEnv(
+			ctx,
 			string(j.restoreScript), env,
 			fmt.Sprintf("restore %s on %s", j.name, j.instanceIdentifier),
+			j.metadata.ScriptTimeouts[restoreScriptName],
+			nil,
 		)
 		if err != nil {
 			j.Logger.Error("bbr", "Error restoring %s on %s.", j.name, j.instanceIdentifier)
@@ -228,14 +337,18 @@ func (j Job) Restore() error {
 	return nil
 }
 
-func (j Job) PostRestoreUnlock() error {
+func (j Job) PostRestoreUnlock(ctx context.Context) error {
 	if j.postRestoreScript != "" {
 		j.Logger.Debug("bbr", "> %s", j.postRestoreScript)
 		j.Logger.Info("bbr", "Unlocking %s on %s...", j.name, j.instanceIdentifier)
 
-		_, err := j.remoteRunner.RunScript(
+		_, err := j.remoteRunner.RunScriptWithEnv(
+			ctx,
 			string(j.postRestoreScript),
+			j.scriptEnvironment(restoreOperation, nil),
 			fmt.Sprintf("post-restore unlock %s on %s", j.name, j.instanceIdentifier),
+			j.metadata.ScriptTimeouts[postRestoreUnlockScriptName],
+			nil,
 		)
 		if err != nil {
 			j.Logger.Error("bbr", "Error unlocking %s on %s.", j.name, j.instanceIdentifier)
@@ -253,6 +366,55 @@ func (j Job) PostRestoreUnlock() error {
 	return nil
 }
 
+// runLoggedScript runs a backup lifecycle script, adding its output to the job's script
+// logs whether or not it succeeds.
+func (j Job) runLoggedScript(ctx context.Context, script Script, env map[string]string, label, scriptName string) error {
+	var output io.Writer
+	buffer := new(bytes.Buffer)
+	if j.logs != nil {
+		output = buffer
+	}
+
+	_, err := j.remoteRunner.RunScriptWithEnv(ctx, string(script), env, label, j.metadata.ScriptTimeouts[scriptName], output)
+
+	if j.logs != nil {
+		j.logs.Add(j.logInstanceName(), j.name, scriptName, buffer.Bytes())
+	}
+	return err
+}
+
+func (j Job) logInstanceName() string {
+	if j.instance.InstanceIndex != "" {
+		return fmt.Sprintf("%s/%s", j.instance.InstanceGroupName, j.instance.InstanceIndex)
+	}
+	return j.instanceIdentifier
+}
+
+// scriptEnvironment returns the variables given to every lifecycle script: BBR_DEPLOYMENT,
+// BBR_INSTANCE_GROUP, BBR_INSTANCE_ID, BBR_INSTANCE_INDEX, BBR_JOB and BBR_OPERATION, those
+// of the run (see orchestrator.ScriptEnvironment) and those of the script itself.
+func (j Job) scriptEnvironment(operation string, scriptVariables map[string]string) map[string]string {
+	env := j.environment.Variables(j.instance.InstanceGroupName, j.instance.InstanceIndex)
+
+	env["BBR_JOB"] = j.name
+	env["BBR_OPERATION"] = operation
+	for name, value := range map[string]string{
+		"BBR_DEPLOYMENT":     j.instance.DeploymentName,
+		"BBR_INSTANCE_GROUP": j.instance.InstanceGroupName,
+		"BBR_INSTANCE_ID":    j.instance.InstanceId,
+		"BBR_INSTANCE_INDEX": j.instance.InstanceIndex,
+	} {
+		if value != "" {
+			env[name] = value
+		}
+	}
+
+	for name, value := range scriptVariables {
+		env[name] = value
+	}
+	return env
+}
+
 func (j Job) backupArtifactOrJobName() string {
 	if j.HasNamedBackupArtifact() {
 		return j.BackupArtifactName()
@@ -9,8 +9,10 @@ import (
 )
 
 type InstanceIdentifier struct {
+	DeploymentName    string
 	InstanceGroupName string
 	InstanceId        string
+	InstanceIndex     string
 }
 
 func (i InstanceIdentifier) String() string {
@@ -23,7 +25,10 @@ type JobFinder interface {
 }
 
 type JobFinderFromScripts struct {
-	Logger Logger
+	Logger            Logger
+	ScriptTimeouts    ScriptTimeouts
+	ScriptEnvironment *orchestrator.ScriptEnvironment
+	ScriptLogs        *orchestrator.ScriptLogs
 }
 
 func NewJobFinder(logger Logger) *JobFinderFromScripts {
@@ -126,7 +131,10 @@ func (j *JobFinderFromScripts) buildJobs(remoteRunner ssh.RemoteRunner,
 			releaseName = ""
 		}
 
-		jobs = append(jobs, NewJob(remoteRunner, instanceIdentifier.String(), logger, releaseName, jobScripts, metadata[jobName]))
+		jobMetadata := metadata[jobName]
+		jobMetadata.ScriptTimeouts = jobMetadata.ScriptTimeouts.WithDefaults(j.ScriptTimeouts)
+		job := NewJob(remoteRunner, instanceIdentifier.String(), logger, releaseName, jobScripts, jobMetadata)
+		jobs = append(jobs, job.WithScriptEnvironment(instanceIdentifier, j.ScriptEnvironment).WithScriptLogs(j.ScriptLogs))
 	}
 
 	return jobs, nil
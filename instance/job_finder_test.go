@@ -92,7 +92,7 @@ var _ = Describe("JobFinderFromScripts", func() {
 						"/var/vcap/jobs/consul_agent/bin/bbr/post-restore-unlock",
 						"/var/vcap/jobs/consul_agent/bin/bbr/pre-backup-lock",
 						"/var/vcap/jobs/consul_agent/bin/bbr/pre-restore-lock",
-					}, Metadata{})))
+					}, Metadata{}).WithScriptEnvironment(instanceIdentifier, nil)))
 			})
 		})
 
@@ -191,7 +191,7 @@ backup_name: consul_backup`, nil)
 								}, Metadata{
 									BackupName: "consul_backup",
 								},
-							),
+							).WithScriptEnvironment(instanceIdentifier, nil),
 						))
 					})
 
@@ -1,14 +1,21 @@
 package instance_test
 
 import (
+	"context"
+
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 
 	"log"
 
 	"fmt"
+	"io"
+	"time"
 
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
 	sshfakes "github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh/fakes"
+	"github.com/pkg/errors"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -238,7 +245,7 @@ var _ = Describe("Job", func() {
 		var backupError error
 
 		JustBeforeEach(func() {
-			backupError = job.Backup()
+			backupError = job.Backup(context.Background())
 		})
 
 		Context("job has no backup script", func() {
@@ -265,12 +272,14 @@ var _ = Describe("Job", func() {
 				Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(1))
 
 				Expect(remoteRunner.CreateDirectoryArgsForCall(0)).To(Equal("/var/vcap/store/bbr-backup/jobname"))
-				specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				_, specifiedScriptPath, specifiedEnvVars, _, _, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/jobname/bin/bbr/backup"))
 				Expect(specifiedEnvVars).To(SatisfyAll(
-					HaveLen(2),
+					HaveLen(4),
 					HaveKeyWithValue("ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/jobname/"),
 					HaveKeyWithValue("BBR_ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/jobname/"),
+					HaveKeyWithValue("BBR_JOB", "jobname"),
+					HaveKeyWithValue("BBR_OPERATION", "backup"),
 				))
 			})
 
@@ -293,6 +302,93 @@ var _ = Describe("Job", func() {
 					Expect(backupError).To(MatchError(ContainSubstring("some weird error")))
 				})
 			})
+
+			Context("the job metadata sets a backup timeout", func() {
+				BeforeEach(func() {
+					metadata = instance.Metadata{ScriptTimeouts: instance.ScriptTimeouts{"backup": 2 * time.Hour}}
+				})
+
+				It("runs the script with the timeout", func() {
+					_, _, _, _, timeout, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+					Expect(timeout).To(Equal(2 * time.Hour))
+				})
+			})
+
+			Context("the job has a script environment", func() {
+				JustBeforeEach(func() {
+					scriptEnvironment := orchestrator.NewScriptEnvironment("1.2.3", map[string]string{"SITE": "london"})
+					scriptEnvironment.SetBackup("redis_20170102T150405Z", "20170102T150405Z")
+
+					backupError = job.WithScriptEnvironment(instance.InstanceIdentifier{
+						DeploymentName:    "redis",
+						InstanceGroupName: "redis-server",
+						InstanceId:        "3e2fe0fd-b7f5-4a2c-a5d6-9b3a4c0c5c31",
+						InstanceIndex:     "1",
+					}, scriptEnvironment).Backup(context.Background())
+				})
+
+				It("tells the script about the instance, the job and the backup", func() {
+					Expect(backupError).NotTo(HaveOccurred())
+					_, _, env, _, _, _ := remoteRunner.RunScriptWithEnvArgsForCall(1)
+					Expect(env).To(Equal(map[string]string{
+						"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/jobname/",
+						"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/jobname/",
+						"BBR_DEPLOYMENT":         "redis",
+						"BBR_INSTANCE_GROUP":     "redis-server",
+						"BBR_INSTANCE_ID":        "3e2fe0fd-b7f5-4a2c-a5d6-9b3a4c0c5c31",
+						"BBR_INSTANCE_INDEX":     "1",
+						"BBR_JOB":                "jobname",
+						"BBR_OPERATION":          "backup",
+						"BBR_VERSION":            "1.2.3",
+						"BBR_BACKUP_ID":          "redis_20170102T150405Z",
+						"BBR_BACKUP_TIMESTAMP":   "20170102T150405Z",
+						"SITE":                   "london",
+					}))
+				})
+			})
+
+			Context("the job collects script logs", func() {
+				var scriptLogs *orchestrator.ScriptLogs
+
+				BeforeEach(func() {
+					scriptLogs = orchestrator.NewScriptLogs()
+					remoteRunner.RunScriptWithEnvStub = func(ctx context.Context, path string, env map[string]string, label string, timeout time.Duration, output io.Writer) (string, error) {
+						if output != nil {
+							output.Write([]byte("dumping\nwarning: slow disk\n"))
+						}
+						return "dumping\n", fmt.Errorf("some weird error")
+					}
+				})
+
+				JustBeforeEach(func() {
+					backupError = job.WithScriptEnvironment(instance.InstanceIdentifier{
+						InstanceGroupName: "redis-server",
+						InstanceId:        "3e2fe0fd-b7f5-4a2c-a5d6-9b3a4c0c5c31",
+						InstanceIndex:     "1",
+					}, nil).WithScriptLogs(scriptLogs).Backup(context.Background())
+				})
+
+				It("collects the output of the script, even when it fails", func() {
+					Expect(backupError).To(MatchError(ContainSubstring("some weird error")))
+					Expect(scriptLogs.Take()).To(ConsistOf(orchestrator.ScriptLog{
+						Instance: "redis-server/1",
+						Job:      "jobname",
+						Phase:    "backup",
+						Output:   []byte("dumping\nwarning: slow disk\n"),
+					}))
+				})
+			})
+
+			Context("backup script times out", func() {
+				BeforeEach(func() {
+					remoteRunner.RunScriptWithEnvReturns("", ssh.ScriptTimeoutError{Label: "backup", After: time.Minute})
+				})
+
+				It("fails with an error caused by the timeout", func() {
+					Expect(backupError).To(MatchError(ContainSubstring("backup timed out after 1m0s")))
+					Expect(errors.Cause(backupError)).To(BeAssignableToTypeOf(ssh.ScriptTimeoutError{}))
+				})
+			})
 		})
 	})
 
@@ -300,7 +396,7 @@ var _ = Describe("Job", func() {
 		var restoreError error
 
 		JustBeforeEach(func() {
-			restoreError = job.Restore()
+			restoreError = job.Restore(context.Background())
 		})
 
 		Context("job has no restore script", func() {
@@ -325,12 +421,14 @@ var _ = Describe("Job", func() {
 			It("uses the remote runner to run the script", func() {
 				Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(1))
 
-				specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				_, specifiedScriptPath, specifiedEnvVars, _, _, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/jobname/bin/bbr/restore"))
 				Expect(specifiedEnvVars).To(SatisfyAll(
-					HaveLen(2),
+					HaveLen(4),
 					HaveKeyWithValue("ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/jobname/"),
 					HaveKeyWithValue("BBR_ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/jobname/"),
+					HaveKeyWithValue("BBR_JOB", "jobname"),
+					HaveKeyWithValue("BBR_OPERATION", "restore"),
 				))
 			})
 
@@ -357,11 +455,104 @@ var _ = Describe("Job", func() {
 		})
 	})
 
+	Describe("PostBackupVerify", func() {
+		var verifyError error
+
+		JustBeforeEach(func() {
+			verifyError = job.PostBackupVerify(context.Background())
+		})
+
+		Context("job has no post-backup-verify script", func() {
+			It("should not call the remote runner", func() {
+				Expect(job.HasPostBackupVerify()).To(BeFalse())
+				Expect(remoteRunner.Invocations()).To(HaveLen(0))
+			})
+		})
+
+		Context("job has a post-backup-verify script", func() {
+			BeforeEach(func() {
+				jobScripts = instance.BackupAndRestoreScripts{
+					"/var/vcap/jobs/jobname/bin/bbr/backup",
+					"/var/vcap/jobs/jobname/bin/bbr/post-backup-verify",
+				}
+			})
+
+			It("runs the script against the artifact written by the backup script", func() {
+				Expect(job.HasPostBackupVerify()).To(BeTrue())
+				Expect(verifyError).NotTo(HaveOccurred())
+				_, cmd, env, _, _, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				Expect(cmd).To(Equal("/var/vcap/jobs/jobname/bin/bbr/post-backup-verify"))
+				Expect(env).To(SatisfyAll(
+					HaveKeyWithValue("BBR_ARTIFACT_DIRECTORY", "/var/vcap/store/bbr-backup/jobname/"),
+					HaveKeyWithValue("BBR_OPERATION", "backup"),
+				))
+			})
+
+			Context("post-backup-verify script fails", func() {
+				BeforeEach(func() {
+					remoteRunner.RunScriptWithEnvReturns("", fmt.Errorf("dump is truncated - exit code 1"))
+				})
+
+				It("fails", func() {
+					Expect(verifyError).To(MatchError(ContainSubstring("post-backup-verify for job jobname")))
+					Expect(verifyError).To(MatchError(ContainSubstring("dump is truncated - exit code 1")))
+				})
+			})
+		})
+	})
+
+	Describe("PreBackupCheck", func() {
+		var preBackupCheckError error
+
+		JustBeforeEach(func() {
+			preBackupCheckError = job.PreBackupCheck(context.Background())
+		})
+
+		Context("job has no pre-backup-check script", func() {
+			It("should not call the remote runner", func() {
+				Expect(remoteRunner.Invocations()).To(HaveLen(0))
+				Expect(preBackupCheckError).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("job has a pre-backup-check script", func() {
+			BeforeEach(func() {
+				jobScripts = instance.BackupAndRestoreScripts{
+					"/var/vcap/jobs/jobname/bin/bbr/backup",
+					"/var/vcap/jobs/jobname/bin/bbr/pre-backup-check",
+				}
+				metadata = instance.Metadata{ScriptTimeouts: instance.ScriptTimeouts{"pre-backup-check": time.Minute}}
+			})
+
+			It("runs the script", func() {
+				Expect(preBackupCheckError).NotTo(HaveOccurred())
+				Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(1))
+				_, cmd, env, _, timeout, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				Expect(cmd).To(Equal("/var/vcap/jobs/jobname/bin/bbr/pre-backup-check"))
+				Expect(env).To(HaveKeyWithValue("BBR_OPERATION", "backup"))
+				Expect(timeout).To(Equal(time.Minute))
+			})
+
+			Context("pre-backup-check script fails", func() {
+				BeforeEach(func() {
+					remoteRunner.RunScriptWithEnvReturns("", fmt.Errorf("replication is lagging - exit code 1"))
+				})
+
+				It("fails with the error of the script", func() {
+					Expect(preBackupCheckError).To(MatchError(fmt.Sprintf(
+						"Error attempting to run pre-backup-check for job jobname on %s: replication is lagging - exit code 1",
+						instanceIdentifier,
+					)))
+				})
+			})
+		})
+	})
+
 	Describe("PreBackupLock", func() {
 		var preBackupLockError error
 
 		JustBeforeEach(func() {
-			preBackupLockError = job.PreBackupLock()
+			preBackupLockError = job.PreBackupLock(context.Background())
 		})
 
 		Context("job has no pre-backup-lock script", func() {
@@ -385,8 +576,8 @@ var _ = Describe("Job", func() {
 
 			It("runs the script", func() {
 				By("calling the remote runner", func() {
-					Expect(remoteRunner.RunScriptCallCount()).To(Equal(1))
-					cmd, _ := remoteRunner.RunScriptArgsForCall(0)
+					Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(1))
+					_, cmd, _, _, _, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 					Expect(cmd).To(Equal("/var/vcap/jobs/jobname/bin/bbr/pre-backup-lock"))
 				})
 
@@ -408,7 +599,7 @@ var _ = Describe("Job", func() {
 
 			Context("pre-backup-lock script runs successfully", func() {
 				BeforeEach(func() {
-					remoteRunner.RunScriptReturns("stdout", nil)
+					remoteRunner.RunScriptWithEnvReturns("stdout", nil)
 				})
 
 				It("succeeds", func() {
@@ -418,7 +609,7 @@ var _ = Describe("Job", func() {
 
 			Context("pre-backup-lock script errors", func() {
 				BeforeEach(func() {
-					remoteRunner.RunScriptReturns("", fmt.Errorf("some strange error"))
+					remoteRunner.RunScriptWithEnvReturns("", fmt.Errorf("some strange error"))
 				})
 
 				It("fails", func() {
@@ -441,7 +632,7 @@ var _ = Describe("Job", func() {
 		})
 
 		JustBeforeEach(func() {
-			postBackupUnlockError = job.PostBackupUnlock(afterSuccessfulBackup)
+			postBackupUnlockError = job.PostBackupUnlock(context.Background(), afterSuccessfulBackup)
 		})
 
 		Context("job has no post-backup-unlock script", func() {
@@ -466,7 +657,7 @@ var _ = Describe("Job", func() {
 
 				It("uses remote runner to run the script", func() {
 					Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(1))
-					cmd, envVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+					_, cmd, envVars, _, _, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 					Expect(cmd).To(Equal("/var/vcap/jobs/jobname/bin/bbr/post-backup-unlock"))
 					Expect(envVars).To(HaveKeyWithValue("BBR_AFTER_BACKUP_SCRIPTS_SUCCESSFUL", "true"))
 				})
@@ -492,7 +683,7 @@ var _ = Describe("Job", func() {
 
 				It("uses remote runner to run the script", func() {
 					Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(1))
-					cmd, envVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+					_, cmd, envVars, _, _, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 					Expect(cmd).To(Equal("/var/vcap/jobs/jobname/bin/bbr/post-backup-unlock"))
 					Expect(envVars).To(HaveKeyWithValue("BBR_AFTER_BACKUP_SCRIPTS_SUCCESSFUL", "false"))
 				})
@@ -521,7 +712,7 @@ var _ = Describe("Job", func() {
 		var preRestoreLockError error
 
 		JustBeforeEach(func() {
-			preRestoreLockError = job.PreRestoreLock()
+			preRestoreLockError = job.PreRestoreLock(context.Background())
 		})
 
 		Context("job has no pre-restore-lock script", func() {
@@ -545,8 +736,8 @@ var _ = Describe("Job", func() {
 
 			It("runs the script", func() {
 				By("using the remote runner", func() {
-					Expect(remoteRunner.RunScriptCallCount()).To(Equal(1))
-					cmd, _ := remoteRunner.RunScriptArgsForCall(0)
+					Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(1))
+					_, cmd, _, _, _, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 					Expect(cmd).To(Equal("/var/vcap/jobs/jobname/bin/bbr/pre-restore-lock"))
 				})
 
@@ -568,7 +759,7 @@ var _ = Describe("Job", func() {
 
 			Context("pre-restore-lock script runs successfully", func() {
 				BeforeEach(func() {
-					remoteRunner.RunScriptReturns("stdout", nil)
+					remoteRunner.RunScriptWithEnvReturns("stdout", nil)
 				})
 
 				It("succeeds", func() {
@@ -578,7 +769,7 @@ var _ = Describe("Job", func() {
 
 			Context("pre-restore-lock script fails", func() {
 				BeforeEach(func() {
-					remoteRunner.RunScriptReturns("", fmt.Errorf("some strange error"))
+					remoteRunner.RunScriptWithEnvReturns("", fmt.Errorf("some strange error"))
 				})
 
 				It("fails", func() {
@@ -594,7 +785,7 @@ var _ = Describe("Job", func() {
 		var postRestoreUnlockError error
 
 		JustBeforeEach(func() {
-			postRestoreUnlockError = job.PostRestoreUnlock()
+			postRestoreUnlockError = job.PostRestoreUnlock(context.Background())
 		})
 
 		Context("job has no post-restore-unlock script", func() {
@@ -617,14 +808,14 @@ var _ = Describe("Job", func() {
 			})
 
 			It("uses the remote runner to run the script", func() {
-				Expect(remoteRunner.RunScriptCallCount()).To(Equal(1))
-				cmd, _ := remoteRunner.RunScriptArgsForCall(0)
+				Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(1))
+				_, cmd, _, _, _, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 				Expect(cmd).To(Equal("/var/vcap/jobs/jobname/bin/bbr/post-restore-unlock"))
 			})
 
 			Context("post-restore-unlock script runs successfully", func() {
 				BeforeEach(func() {
-					remoteRunner.RunScriptReturns("stdout", nil)
+					remoteRunner.RunScriptWithEnvReturns("stdout", nil)
 				})
 
 				It("succeeds", func() {
@@ -634,7 +825,7 @@ var _ = Describe("Job", func() {
 
 			Context("post-restore-unlock script fails", func() {
 				BeforeEach(func() {
-					remoteRunner.RunScriptReturns("", fmt.Errorf("oh no"))
+					remoteRunner.RunScriptWithEnvReturns("", fmt.Errorf("oh no"))
 				})
 
 				It("fails", func() {
@@ -1,6 +1,9 @@
 package instance
 
 import (
+	"strings"
+	"time"
+
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 )
@@ -11,10 +14,86 @@ type LockBefore struct {
 }
 
 type Metadata struct {
-	BackupName                  string       `yaml:"backup_name"`
-	RestoreName                 string       `yaml:"restore_name"`
-	BackupShouldBeLockedBefore  []LockBefore `yaml:"backup_should_be_locked_before"`
-	RestoreShouldBeLockedBefore []LockBefore `yaml:"restore_should_be_locked_before"`
+	BackupName                  string         `yaml:"backup_name"`
+	RestoreName                 string         `yaml:"restore_name"`
+	BackupShouldBeLockedBefore  []LockBefore   `yaml:"backup_should_be_locked_before"`
+	RestoreShouldBeLockedBefore []LockBefore   `yaml:"restore_should_be_locked_before"`
+	ScriptTimeouts              ScriptTimeouts `yaml:"timeouts"`
+}
+
+// ScriptTimeouts limits how long each script of a job may run, keyed by script name, for
+// example backup or pre-backup-lock. Scripts without a timeout run until they finish.
+type ScriptTimeouts map[string]time.Duration
+
+// TimeoutScriptNames are the scripts which can be given a timeout
+var TimeoutScriptNames = []string{
+	backupScriptName,
+	restoreScriptName,
+	preBackupCheckScriptName,
+	preBackupLockScriptName,
+	preRestoreLockScriptName,
+	postBackupUnlockScriptName,
+	postBackupVerifyScriptName,
+	postRestoreUnlockScriptName,
+}
+
+// ParseScriptTimeouts parses durations such as 5m or 2h. Script names may be written with
+// underscores, as in pre_backup_lock, the way other job metadata keys are.
+func ParseScriptTimeouts(timeouts map[string]string) (ScriptTimeouts, error) {
+	if len(timeouts) == 0 {
+		return nil, nil
+	}
+
+	scriptTimeouts := ScriptTimeouts{}
+	for name, value := range timeouts {
+		scriptName := strings.Replace(name, "_", "-", -1)
+		if !isTimeoutScriptName(scriptName) {
+			return nil, errors.Errorf("cannot set a timeout for unknown script '%s'", name)
+		}
+
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return nil, errors.Errorf("invalid timeout '%s' for script '%s'", value, name)
+		}
+		scriptTimeouts[scriptName] = timeout
+	}
+	return scriptTimeouts, nil
+}
+
+func (t *ScriptTimeouts) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var timeouts map[string]string
+	err := unmarshal(&timeouts)
+	if err != nil {
+		return err
+	}
+
+	*t, err = ParseScriptTimeouts(timeouts)
+	return err
+}
+
+// WithDefaults fills in the timeouts of scripts which have none from defaults
+func (t ScriptTimeouts) WithDefaults(defaults ScriptTimeouts) ScriptTimeouts {
+	if len(defaults) == 0 {
+		return t
+	}
+
+	merged := ScriptTimeouts{}
+	for name, timeout := range defaults {
+		merged[name] = timeout
+	}
+	for name, timeout := range t {
+		merged[name] = timeout
+	}
+	return merged
+}
+
+func isTimeoutScriptName(name string) bool {
+	for _, scriptName := range TimeoutScriptNames {
+		if scriptName == name {
+			return true
+		}
+	}
+	return false
 }
 
 func ParseJobMetadata(data string) (*Metadata, error) {
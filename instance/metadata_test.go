@@ -1,6 +1,8 @@
 package instance_test
 
 import (
+	"time"
+
 	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
 
 	. "github.com/onsi/ginkgo"
@@ -109,4 +111,43 @@ restore_should_be_locked_before:
 
 		Expect(err).To(MatchError(ContainSubstring("both job name and release should be specified for should be locked before")))
 	})
+
+	It("has an optional `timeouts` field", func() {
+		rawMetadata := `---
+timeouts:
+  backup: 2h
+  pre_backup_lock: 5m
+`
+
+		m, err := ParseJobMetadata(rawMetadata)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.ScriptTimeouts).To(Equal(ScriptTimeouts{
+			"backup":          2 * time.Hour,
+			"pre-backup-lock": 5 * time.Minute,
+		}))
+	})
+
+	It("errors if a timeout is for an unknown script", func() {
+		_, err := ParseJobMetadata("timeouts: {metadata: 5m}")
+
+		Expect(err).To(MatchError(ContainSubstring("cannot set a timeout for unknown script 'metadata'")))
+	})
+
+	It("errors if a timeout is not a duration", func() {
+		_, err := ParseJobMetadata("timeouts: {backup: forever}")
+
+		Expect(err).To(MatchError(ContainSubstring("invalid timeout 'forever' for script 'backup'")))
+	})
+
+	Describe("ScriptTimeouts", func() {
+		It("prefers its own timeouts to the defaults", func() {
+			timeouts := ScriptTimeouts{"backup": time.Hour}
+
+			Expect(timeouts.WithDefaults(ScriptTimeouts{"backup": time.Minute, "restore": time.Minute})).To(Equal(ScriptTimeouts{
+				"backup":  time.Hour,
+				"restore": time.Minute,
+			}))
+		})
+	})
 })
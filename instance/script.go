@@ -11,9 +11,11 @@ const (
 	backupScriptName            = "backup"
 	restoreScriptName           = "restore"
 	metadataScriptName          = "metadata"
+	preBackupCheckScriptName    = "pre-backup-check"
 	preBackupLockScriptName     = "pre-backup-lock"
 	preRestoreLockScriptName    = "pre-restore-lock"
 	postBackupUnlockScriptName  = "post-backup-unlock"
+	postBackupVerifyScriptName  = "post-backup-verify"
 	postRestoreUnlockScriptName = "post-restore-unlock"
 
 	jobBaseDirectory               = "/var/vcap/jobs/"
@@ -21,9 +23,11 @@ const (
 	backupScriptMatcher            = jobDirectoryMatcher + backupScriptName
 	restoreScriptMatcher           = jobDirectoryMatcher + restoreScriptName
 	metadataScriptMatcher          = jobDirectoryMatcher + metadataScriptName
+	preBackupCheckScriptMatcher    = jobDirectoryMatcher + preBackupCheckScriptName
 	preBackupLockScriptMatcher     = jobDirectoryMatcher + preBackupLockScriptName
 	preRestoreLockScriptMatcher    = jobDirectoryMatcher + preRestoreLockScriptName
 	postBackupUnlockScriptMatcher  = jobDirectoryMatcher + postBackupUnlockScriptName
+	postBackupVerifyScriptMatcher  = jobDirectoryMatcher + postBackupVerifyScriptName
 	postRestoreUnlockScriptMatcher = jobDirectoryMatcher + postRestoreUnlockScriptName
 )
 
@@ -42,6 +46,11 @@ func (s Script) isMetadata() bool {
 	return match
 }
 
+func (s Script) isPreBackupCheck() bool {
+	match, _ := filepath.Match(preBackupCheckScriptMatcher, string(s))
+	return match
+}
+
 func (s Script) isPreBackupUnlock() bool {
 	match, _ := filepath.Match(preBackupLockScriptMatcher, string(s))
 	return match
@@ -57,6 +66,11 @@ func (s Script) isPostBackupUnlock() bool {
 	return match
 }
 
+func (s Script) isPostBackupVerify() bool {
+	match, _ := filepath.Match(postBackupVerifyScriptMatcher, string(s))
+	return match
+}
+
 func (s Script) isPostRestoreUnlock() bool {
 	match, _ := filepath.Match(postRestoreUnlockScriptMatcher, string(s))
 	return match
@@ -65,9 +79,11 @@ func (s Script) isPostRestoreUnlock() bool {
 func (s Script) isPlatformScript() bool {
 	return s.isBackup() ||
 		s.isRestore() ||
+		s.isPreBackupCheck() ||
 		s.isPreBackupUnlock() ||
 		s.isPreRestoreLock() ||
 		s.isPostBackupUnlock() ||
+		s.isPostBackupVerify() ||
 		s.isPostRestoreUnlock() ||
 		s.isMetadata()
 }
@@ -54,6 +54,16 @@ func (s BackupAndRestoreScripts) RestoreOnly() BackupAndRestoreScripts {
 	return scripts
 }
 
+func (s BackupAndRestoreScripts) PreBackupCheckOnly() BackupAndRestoreScripts {
+	scripts := BackupAndRestoreScripts{}
+	for _, script := range s {
+		if script.isPreBackupCheck() {
+			scripts = append(scripts, script)
+		}
+	}
+	return scripts
+}
+
 func (s BackupAndRestoreScripts) PreBackupLockOnly() BackupAndRestoreScripts {
 	scripts := BackupAndRestoreScripts{}
 	for _, script := range s {
@@ -84,6 +94,16 @@ func (s BackupAndRestoreScripts) PostBackupUnlockOnly() BackupAndRestoreScripts
 	return scripts
 }
 
+func (s BackupAndRestoreScripts) PostBackupVerifyOnly() BackupAndRestoreScripts {
+	scripts := BackupAndRestoreScripts{}
+	for _, script := range s {
+		if script.isPostBackupVerify() {
+			scripts = append(scripts, script)
+		}
+	}
+	return scripts
+}
+
 func (s BackupAndRestoreScripts) SinglePostRestoreUnlockScript() Script {
 	for _, script := range s {
 		if script.isPostRestoreUnlock() {
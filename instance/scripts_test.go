@@ -99,6 +99,17 @@ var _ = Describe("Backup and Restore Scripts", func() {
 			})
 		})
 
+		Context("PreBackupCheck", func() {
+			It("returns the matching scripts", func() {
+				var allScripts = []string{"/var/vcap/jobs/cloud_controller_clock/bin/baz",
+					"/var/vcap/jobs/cloud_controller_clock/bin/bbr/pre-backup-check",
+					"/var/vcap/jobs/cloud_controller_clock/bin/pre-start"}
+				Expect(NewBackupAndRestoreScripts(allScripts)).To(Equal(BackupAndRestoreScripts{
+					"/var/vcap/jobs/cloud_controller_clock/bin/bbr/pre-backup-check",
+				}))
+			})
+		})
+
 		Context("PreBackupLock", func() {
 			It("returns the matching scripts", func() {
 				var allScripts = []string{"/var/vcap/jobs/cloud_controller_clock/bin/baz",
@@ -123,6 +134,17 @@ var _ = Describe("Backup and Restore Scripts", func() {
 			})
 		})
 
+		Context("PostBackupVerify", func() {
+			It("returns the matching scripts", func() {
+				var allScripts = []string{"/var/vcap/jobs/cloud_controller_clock/bin/baz",
+					"/var/vcap/jobs/cloud_controller_clock/bin/bbr/post-backup-verify",
+					"/var/vcap/jobs/cloud_controller_clock/bin/pre-start"}
+				Expect(NewBackupAndRestoreScripts(allScripts)).To(Equal(BackupAndRestoreScripts{
+					"/var/vcap/jobs/cloud_controller_clock/bin/bbr/post-backup-verify",
+				}))
+			})
+		})
+
 		Context("Metadata", func() {
 			It("returns the matching scripts", func() {
 				var allScripts = []string{"/var/vcap/jobs/cloud_controller_clock/bin/baz",
@@ -223,6 +245,15 @@ var _ = Describe("Backup and Restore Scripts", func() {
 		})
 	})
 
+	Describe("PreBackupCheckOnly", func() {
+		It("returns the pre-backup-check scripts", func() {
+			s := BackupAndRestoreScripts{"/var/vcap/jobs/cloud_controller_clock/bin/bbr/backup",
+				"/var/vcap/jobs/cloud_controller_clock/bin/bbr/pre-backup-check",
+				"/var/vcap/jobs/cloud_controller_clock/bin/bbr/pre-backup-lock"}
+			Expect(s.PreBackupCheckOnly()).To(Equal(BackupAndRestoreScripts{"/var/vcap/jobs/cloud_controller_clock/bin/bbr/pre-backup-check"}))
+		})
+	})
+
 	Describe("PreBackupLockOnly", func() {
 		It("returns the pre-backup-lock scripts when it only has one", func() {
 			s := BackupAndRestoreScripts{"/var/vcap/jobs/cloud_controller_clock/bin/baz",
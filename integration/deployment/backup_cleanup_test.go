@@ -88,6 +88,82 @@ instance_groups:
 		})
 	})
 
+	Context("when the interrupted backup left a journal behind", func() {
+		var session *gexec.Session
+		var instance1 *testcluster.Instance
+		var deploymentName string
+		manifest := `---
+instance_groups:
+- name: redis-dedicated-node
+  instances: 1
+  jobs:
+  - name: redis
+    release: redis
+`
+
+		BeforeEach(func() {
+			cleanupWorkspace, _ = ioutil.TempDir(".", "cleanup-workspace-")
+
+			instance1 = testcluster.NewInstance()
+
+			deploymentName = "my-new-deployment"
+			director = mockbosh.NewTLS()
+			director.ExpectedBasicAuth("admin", "admin")
+			director.VerifyAndMock(AppendBuilders(
+				InfoWithBasicAuth(),
+				VmsForDeployment(deploymentName, []mockbosh.VMsOutput{
+					{
+						IPs:     []string{"10.0.0.1"},
+						JobName: "redis-dedicated-node",
+						ID:      "fake-uuid",
+						Index:   newIndex(0),
+					}}),
+				DownloadManifest(deploymentName, manifest),
+				SetupSSH(deploymentName, "redis-dedicated-node", "fake-uuid", 0, instance1),
+				CleanupSSH(deploymentName, "redis-dedicated-node"),
+				CleanupSSH(deploymentName, "redis-dedicated-node"),
+			)...)
+
+			instance1.CreateScript("/var/vcap/jobs/redis/bin/bbr/backup", ``)
+			instance1.CreateScript("/var/vcap/jobs/redis/bin/bbr/post-backup-unlock", `touch /tmp/unlocked`)
+			instance1.CreateDir("/var/vcap/store/bbr-backup")
+
+			Expect(ioutil.WriteFile(filepath.Join(cleanupWorkspace, deploymentName+".bbr-journal"), []byte(`{
+  "operation": "backup",
+  "ssh_users": [{"instance": "redis-dedicated-node/fake-uuid", "username": "bbr-crashed"}],
+  "artifact_directories": ["redis-dedicated-node/fake-uuid"]
+}`), 0644)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			session = binary.Run(
+				cleanupWorkspace,
+				[]string{"BOSH_CLIENT_SECRET=admin"},
+				"deployment",
+				"--ca-cert", sslCertPath,
+				"--username", "admin",
+				"--debug",
+				"--target", director.URL,
+				"--deployment", deploymentName,
+				"backup-cleanup",
+			)
+		})
+
+		AfterEach(func() {
+			instance1.DieInBackground()
+			director.VerifyMocks()
+			Expect(os.RemoveAll(cleanupWorkspace)).To(Succeed())
+		})
+
+		It("only cleans up what the journal lists, and then removes the journal", func() {
+			Eventually(session.ExitCode()).Should(Equal(0))
+			Expect(session.Out).To(gbytes.Say("Cleaning up after the backup of '%s', according to its journal", deploymentName))
+			Expect(instance1.FileExists("/var/vcap/store/bbr-backup")).To(BeFalse())
+			Expect(instance1.FileExists("/tmp/unlocked")).To(BeFalse())
+			Expect(filepath.Join(cleanupWorkspace, deploymentName+".bbr-journal")).NotTo(BeAnExistingFile())
+		})
+	})
+
 	Context("when running with --all-deployments", func() {
 		Context("with single deployment", func() {
 			var session *gexec.Session
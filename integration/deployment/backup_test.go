@@ -192,8 +192,9 @@ printf "backupcontent2" > $BBR_ARTIFACT_DIRECTORY/backupdump2
 							Eventually(session, 10).Should(gexec.Exit(1))
 						})
 
-						By("outputting a warning about cleanup", func() {
-							Eventually(session).Should(gbytes.Say("It is recommended that you run `bbr backup-cleanup` to ensure that any temp files are cleaned up and all jobs are unlocked."))
+						By("aborting the backup, unlocking and cleaning up before exiting", func() {
+							Expect(session.Out).To(gbytes.Say("Aborting the backup: unlocking jobs and cleaning up. Send the signal again to exit immediately."))
+							Expect(session.Err).To(gbytes.Say("bbr was aborted"))
 						})
 
 						By("not creating an artifact tar from the interrupted backup script", func() {
@@ -203,6 +204,23 @@ printf "backupcontent2" > $BBR_ARTIFACT_DIRECTORY/backupdump2
 					})
 				})
 
+				Context("and it receives SIGTERM instead", func() {
+					BeforeEach(func() {
+						verifyMocks = false
+					})
+
+					It("aborts without asking for confirmation", func() {
+						Eventually(session, "30s").Should(gbytes.Say("Backing up"))
+						session.Terminate()
+
+						Eventually(session).Should(gbytes.Say("Aborting the backup: unlocking jobs and cleaning up."))
+						Expect(string(session.Out.Contents())).NotTo(ContainSubstring("[yes/no]"))
+
+						Eventually(session, 10).Should(gexec.Exit(1))
+						Expect(session.Err).To(gbytes.Say("bbr was aborted"))
+					})
+				})
+
 				Context("and the user decides to continue backup", func() {
 					It("continues to run", func() {
 						session.Interrupt()
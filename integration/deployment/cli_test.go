@@ -412,6 +412,48 @@ instances: []`))
 			})
 		})
 
+		Context("prune", func() {
+			It("does not need the credentials of the director", func() {
+				session := binary.Run(backupWorkspace, []string{},
+					"deployment",
+					"--deployment", "my-new-deployment",
+					"prune",
+					"--keep-last", "1")
+				Eventually(session).Should(gexec.Exit())
+
+				Expect(session.ExitCode()).To(BeZero())
+				Expect(string(session.Err.Contents())).NotTo(ContainSubstring("flag is required"))
+			})
+
+			It("only prunes backups of deployments with --all-deployments", func() {
+				createBackup := func(name, kind string) string {
+					directory := backupWorkspace + "/" + name
+					Expect(os.Mkdir(directory, 0700)).To(Succeed())
+					metadata := fmt.Sprintf("backup_activity:\n  start_time: 2017/01/01 00:00:00 UTC\n  finish_time: 2017/01/01 00:01:00 UTC\nkind: %s\n", kind)
+					Expect(ioutil.WriteFile(directory+"/metadata", []byte(metadata), 0600)).To(Succeed())
+					return directory
+				}
+				oldDeploymentBackup := createBackup("redis_20170101T000000Z", "deployment")
+				newDeploymentBackup := createBackup("redis_20170102T000000Z", "deployment")
+				oldDirectorBackup := createBackup("10.0.0.6_20170101T000000Z", "director")
+				createBackup("10.0.0.6_20170102T000000Z", "director")
+				unrelated := backupWorkspace + "/notes_20170101T000000Z"
+				Expect(os.Mkdir(unrelated, 0700)).To(Succeed())
+
+				session := binary.Run(backupWorkspace, []string{},
+					"deployment",
+					"--all-deployments",
+					"prune",
+					"--keep-last", "1")
+				Eventually(session).Should(gexec.Exit(0))
+
+				Expect(oldDeploymentBackup).NotTo(BeADirectory())
+				Expect(newDeploymentBackup).To(BeADirectory())
+				Expect(oldDirectorBackup).To(BeADirectory())
+				Expect(unrelated).To(BeADirectory())
+			})
+		})
+
 		Context("--help", func() {
 			It("displays the usable flags", func() {
 				session := binary.Run(backupWorkspace, []string{"BOSH_CLIENT_SECRET=admin"}, "deployment", "--help")
@@ -466,6 +508,81 @@ instances: []`))
 			})
 		})
 
+		Context("given --max-in-flight-instances is less than 1", func() {
+			var session *gexec.Session
+
+			BeforeEach(func() {
+				session = binary.Run(backupWorkspace, []string{},
+					"deployment",
+					"--ca-cert", sslCertPath,
+					"--username", "admin",
+					"--password", "admin",
+					"--target", director.URL,
+					"--deployment", "my-new-deployment",
+					"--max-in-flight-instances", "0",
+					"backup")
+				Eventually(session).Should(gexec.Exit())
+			})
+
+			It("exits non-zero", func() {
+				Expect(session.ExitCode()).NotTo(BeZero())
+			})
+
+			It("displays a failure message", func() {
+				Expect(session.Err).To(gbytes.Say("--max-in-flight-instances and --max-in-flight-deployments must be at least 1."))
+			})
+		})
+
+		Context("given a --script-timeout for an unknown script", func() {
+			var session *gexec.Session
+
+			BeforeEach(func() {
+				session = binary.Run(backupWorkspace, []string{},
+					"deployment",
+					"--ca-cert", sslCertPath,
+					"--username", "admin",
+					"--password", "admin",
+					"--target", director.URL,
+					"--deployment", "my-new-deployment",
+					"--script-timeout", "pre-backup-sleep=5m",
+					"backup")
+				Eventually(session).Should(gexec.Exit())
+			})
+
+			It("exits non-zero", func() {
+				Expect(session.ExitCode()).NotTo(BeZero())
+			})
+
+			It("displays a failure message", func() {
+				Expect(session.Err).To(gbytes.Say("--script-timeout is invalid: cannot set a timeout for unknown script 'pre-backup-sleep'"))
+			})
+		})
+
+		Context("given a --script-env which sets a bbr variable", func() {
+			var session *gexec.Session
+
+			BeforeEach(func() {
+				session = binary.Run(backupWorkspace, []string{},
+					"deployment",
+					"--ca-cert", sslCertPath,
+					"--username", "admin",
+					"--password", "admin",
+					"--target", director.URL,
+					"--deployment", "my-new-deployment",
+					"--script-env", "BBR_DEPLOYMENT=other",
+					"backup")
+				Eventually(session).Should(gexec.Exit())
+			})
+
+			It("exits non-zero", func() {
+				Expect(session.ExitCode()).NotTo(BeZero())
+			})
+
+			It("displays a failure message", func() {
+				Expect(session.Err).To(gbytes.Say("--script-env cannot set BBR_DEPLOYMENT, which is set by bbr."))
+			})
+		})
+
 		Context("no arguments", func() {
 			It("displays the usable flags", func() {
 				session := binary.Run(backupWorkspace, []string{"BOSH_CLIENT_SECRET=admin"}, "deployment")
@@ -483,6 +600,9 @@ func assertDeploymentHelpText(session *gexec.Session) {
 		gbytes.Say("--deployment"), gbytes.Say("Name of BOSH deployment. Omit if '--all-deployments' is provided"), gbytes.Say("BOSH_DEPLOYMENT"),
 		gbytes.Say("--ca-cert"), gbytes.Say("Path or value of BOSH Director custom CA certificate"), gbytes.Say("CA_CERT"), gbytes.Say("BOSH_CA_CERT"),
 		gbytes.Say("--debug"), gbytes.Say("Enable debug logs"),
-		gbytes.Say("--all-deployments"), gbytes.Say("Run command for all deployments. Omit if '--deployment' is provided. Currently only supported for: pre-backup-check, backup and backup-cleanup"),
+		gbytes.Say("--all-deployments"), gbytes.Say("Run command for all deployments. Omit if '--deployment' is provided. Currently only supported for: pre-backup-check, backup, backup-cleanup, restore and restore-cleanup"),
+		gbytes.Say("--max-in-flight-instances"), gbytes.Say("Maximum number of instances worked on at once within a deployment"),
+		gbytes.Say("--max-in-flight-deployments"), gbytes.Say("Maximum number of deployments worked on at once with '--all-deployments'"),
+		gbytes.Say("--serial"), gbytes.Say("Work on one instance and one deployment at a time"),
 	))
 }
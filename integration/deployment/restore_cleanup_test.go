@@ -1,12 +1,14 @@
 package deployment
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/testcluster"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/gexec"
 	"github.com/pivotal-cf-experimental/cf-webmock/mockbosh"
 	"github.com/pivotal-cf-experimental/cf-webmock/mockhttp"
@@ -78,3 +80,81 @@ instance_groups:
 		Expect(instance.FileExists("/var/vcap/store/bbr-backup")).To(BeFalse())
 	})
 })
+
+var _ = Describe("Restore cleanup --all-deployments", func() {
+	var cleanupWorkspace string
+	var director *mockhttp.Server
+
+	var session *gexec.Session
+	var instance *testcluster.Instance
+	var deploymentName = "dep1"
+	manifest := `---
+instance_groups:
+- name: redis-dedicated-node
+  instances: 1
+  jobs:
+  - name: redis
+    release: redis
+`
+
+	BeforeEach(func() {
+		cleanupWorkspace, _ = ioutil.TempDir(".", "cleanup-workspace-")
+
+		instance = testcluster.NewInstance()
+
+		director = mockbosh.NewTLS()
+		director.ExpectedBasicAuth("admin", "admin")
+		director.VerifyAndMock(AppendBuilders(
+			InfoWithBasicAuth(),
+			Deployments([]string{deploymentName}),
+			InfoWithBasicAuth(),
+			VmsForDeployment(deploymentName, []mockbosh.VMsOutput{
+				{
+					IPs:     []string{"10.0.0.1"},
+					JobName: "redis-dedicated-node",
+					ID:      "fake-uuid",
+					Index:   newIndex(0),
+				}}),
+			DownloadManifest(deploymentName, manifest),
+			SetupSSH(deploymentName, "redis-dedicated-node", "fake-uuid", 0, instance),
+			CleanupSSH(deploymentName, "redis-dedicated-node"),
+		)...)
+
+		instance.CreateScript("/var/vcap/jobs/redis/bin/bbr/restore", ``)
+		instance.CreateDir("/var/vcap/store/bbr-backup")
+	})
+
+	JustBeforeEach(func() {
+		session = binary.Run(
+			cleanupWorkspace,
+			[]string{"BOSH_CLIENT_SECRET=admin"},
+			"deployment",
+			"--ca-cert", sslCertPath,
+			"--username", "admin",
+			"--debug",
+			"--target", director.URL,
+			"--all-deployments",
+			"restore-cleanup",
+		)
+	})
+
+	AfterEach(func() {
+		director.VerifyMocks()
+		instance.DieInBackground()
+		Expect(os.RemoveAll(cleanupWorkspace)).To(Succeed())
+	})
+
+	It("successfully cleans up all deployments after a failed restore", func() {
+		Eventually(session.ExitCode()).Should(Equal(0))
+		Expect(instance.FileExists("/var/vcap/store/bbr-backup")).To(BeFalse())
+
+		logfilePath := fmt.Sprintf("%s_%s.log", deploymentName, `(\d){8}T(\d){6}Z\b`)
+		AssertOutputWithTimestamp(session.Out, []string{
+			fmt.Sprintf("Pending: %s", deploymentName),
+			fmt.Sprintf("Starting restore cleanup of %s, log file: %s", deploymentName, logfilePath),
+			fmt.Sprintf("Finished restore cleanup of %s", deploymentName),
+			fmt.Sprintf("Successfully cleaned up: %s", deploymentName),
+		})
+		Expect(session.Out).NotTo(gbytes.Say("FAILED"))
+	})
+})
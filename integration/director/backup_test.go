@@ -300,8 +300,9 @@ backup_should_be_locked_before:
 						Eventually(session, 10*time.Second).Should(gexec.Exit(1))
 					})
 
-					By("outputting a warning about cleanup", func() {
-						Eventually(session).Should(gbytes.Say("It is recommended that you run `bbr backup-cleanup` to ensure that any temp files are cleaned up and all jobs are unlocked."))
+					By("aborting the backup, unlocking and cleaning up before exiting", func() {
+						Expect(session.Out).To(gbytes.Say("Aborting the backup: unlocking jobs and cleaning up. Send the signal again to exit immediately."))
+						Expect(session.Err).To(gbytes.Say("bbr was aborted"))
 					})
 
 					By("not creating an artifact tar from the interrupted director backup script", func() {
@@ -37,6 +37,20 @@ var _ = Describe("CLI Interface", func() {
 	})
 
 	Context("bbr director", func() {
+		Context("prune", func() {
+			It("only needs the host of the director", func() {
+				session := binary.Run(backupWorkspace, []string{},
+					"director",
+					"--host", "10.0.0.5",
+					"prune",
+					"--keep-last", "1")
+				Eventually(session).Should(gexec.Exit())
+
+				Expect(session.ExitCode()).To(BeZero())
+				Expect(string(session.Err.Contents())).NotTo(ContainSubstring("flag is required"))
+			})
+		})
+
 		Describe("backup with invalid command line arguments", func() {
 			Context("private-key-path flag", func() {
 				var (
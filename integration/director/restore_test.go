@@ -165,12 +165,13 @@ cat $BBR_ARTIFACT_DIRECTORY/backup > /var/vcap/store/bosh/restored_file
 
 							stdin.Write([]byte("yes\n"))
 
-							By("waiting for the restore to finish successfully", func() {
+							By("then exiting with a failure", func() {
 								Eventually(session, 10).Should(gexec.Exit(1))
 							})
 
-							By("outputting a warning about cleanup", func() {
-								Eventually(session).Should(gbytes.Say("It is recommended that you run `bbr restore-cleanup` to ensure that any temp files are cleaned up and all jobs are unlocked."))
+							By("aborting the restore, unlocking and cleaning up before exiting", func() {
+								Expect(session.Out).To(gbytes.Say("Aborting the restore: unlocking jobs and cleaning up. Send the signal again to exit immediately."))
+								Expect(session.Err).To(gbytes.Say("bbr was aborted"))
 							})
 
 							By("not completing the restore", func() {
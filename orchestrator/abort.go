@@ -0,0 +1,96 @@
+package orchestrator
+
+import "sync"
+
+// Interrupter stops the commands running on instances, until it is resumed
+type Interrupter interface {
+	Interrupt()
+	Resume()
+}
+
+// Abort stops running workflows early. Once aborted, a workflow's in-flight commands are
+// interrupted and it follows its failure edges, running only the steps which unlock jobs
+// and clean up, so that the deployment is not left locked.
+//
+// Commands are not interrupted while any workflow sharing the abort is cleaning up, as
+// that would stop it half way; the other workflows stop once their current step finishes.
+type Abort struct {
+	mux         sync.Mutex
+	interrupter Interrupter
+	aborted     bool
+	cleaningUp  int
+
+	// interrupted is closed once the interrupter has stopped the commands which were running
+	// when the abort began, if it was asked to
+	interrupted chan struct{}
+}
+
+func NewAbort(interrupter Interrupter) *Abort {
+	return &Abort{interrupter: interrupter}
+}
+
+// Abort interrupts commands without holding the lock, as killing them on their instances
+// can take a while and would hold up the workflows checking whether they were aborted.
+func (a *Abort) Abort() {
+	a.mux.Lock()
+	if a.aborted {
+		a.mux.Unlock()
+		return
+	}
+	a.aborted = true
+
+	var interrupted chan struct{}
+	if a.cleaningUp == 0 {
+		interrupted = make(chan struct{})
+		a.interrupted = interrupted
+	}
+	a.mux.Unlock()
+
+	if interrupted != nil {
+		a.interrupter.Interrupt()
+		close(interrupted)
+	}
+}
+
+func (a *Abort) Aborted() bool {
+	if a == nil {
+		return false
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	return a.aborted
+}
+
+// startCleanup lets the commands of a cleanup step run, even if the workflow was aborted.
+// It waits for any interruption in progress to finish first, so that it does not stop them.
+func (a *Abort) startCleanup() func() {
+	if a == nil {
+		return func() {}
+	}
+
+	a.mux.Lock()
+	a.cleaningUp++
+	aborted, interrupted := a.aborted, a.interrupted
+	a.mux.Unlock()
+
+	if aborted {
+		if interrupted != nil {
+			<-interrupted
+		}
+		a.interrupter.Resume()
+	}
+
+	return func() {
+		a.mux.Lock()
+		defer a.mux.Unlock()
+		a.cleaningUp--
+	}
+}
+
+// cleanupStep is implemented by steps which undo what a workflow has done, such as
+// unlocking jobs and cleaning up. They still run once the workflow has been aborted.
+type cleanupStep interface {
+	Step
+	cleansUp()
+}
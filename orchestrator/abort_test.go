@@ -0,0 +1,233 @@
+package orchestrator_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("Abort", func() {
+	var (
+		interrupter       *fakes.FakeInterrupter
+		abort             *orchestrator.Abort
+		deployment        *fakes.FakeDeployment
+		deploymentManager *fakes.FakeDeploymentManager
+		backupManager     *fakes.FakeBackupManager
+		artifactCopier    *fakes.FakeArtifactCopier
+		backuper          *orchestrator.Backuper
+		lockTimeout       time.Duration
+		ctx               context.Context
+		backupErr         orchestrator.Error
+	)
+
+	BeforeEach(func() {
+		interrupter = new(fakes.FakeInterrupter)
+		abort = orchestrator.NewAbort(interrupter)
+
+		deployment = new(fakes.FakeDeployment)
+		deployment.IsBackupableReturns(true)
+		deployment.HasUniqueCustomArtifactNamesReturns(true)
+		deploymentManager = new(fakes.FakeDeploymentManager)
+		deploymentManager.FindReturns(deployment, nil)
+		backupManager = new(fakes.FakeBackupManager)
+		backupManager.CreateReturns(new(fakes.FakeBackup), nil)
+		artifactCopier = new(fakes.FakeArtifactCopier)
+		lockTimeout = 0
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		backuper = orchestrator.NewBackuper(backupManager, new(fakes.FakeLogger), deploymentManager, new(fakes.FakeLockOrderer),
+			executor.NewParallelExecutor(), executor.NewParallelExecutor(), time.Now, artifactCopier, "", nil, nil, nil, abort, lockTimeout, "")
+		backupErr = backuper.Backup(ctx, "redis", "")
+	})
+
+	Context("when aborted while backing up", func() {
+		BeforeEach(func() {
+			deployment.BackupStub = func(context.Context, executor.Executor) error {
+				abort.Abort()
+				Expect(interrupter.InterruptCallCount()).To(Equal(1))
+				return errors.New("backup script was interrupted")
+			}
+		})
+
+		It("unlocks the jobs after a failed backup and cleans up", func() {
+			Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
+			_, afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
+			Expect(afterSuccessfulBackup).To(BeFalse())
+			Expect(deployment.CleanupCallCount()).To(Equal(1))
+		})
+
+		It("lets the unlock and cleanup commands run", func() {
+			Expect(interrupter.ResumeCallCount()).To(BeNumerically(">", 0))
+		})
+
+		It("does not verify or drain the backup", func() {
+			Expect(deployment.PostBackupVerifyCallCount()).To(BeZero())
+			Expect(artifactCopier.DownloadBackupFromDeploymentCallCount()).To(BeZero())
+		})
+
+		It("fails with the step's error and an abort error", func() {
+			Expect(backupErr).To(ConsistOf(
+				MatchError(ContainSubstring("backup script was interrupted")),
+				BeAssignableToTypeOf(orchestrator.AbortError{}),
+			))
+			Expect(backupErr).To(ContainElement(MatchError("bbr was aborted")))
+		})
+	})
+
+	Context("when aborted while commands take a while to interrupt", func() {
+		var (
+			interrupting    chan struct{}
+			checked         chan struct{}
+			reportedAborted bool
+			calls           []string
+			callsMux        sync.Mutex
+		)
+
+		BeforeEach(func() {
+			interrupting = make(chan struct{})
+			checked = make(chan struct{})
+			reportedAborted = false
+			calls = nil
+			interrupter.InterruptStub = func() {
+				close(interrupting)
+				<-checked
+				callsMux.Lock()
+				defer callsMux.Unlock()
+				calls = append(calls, "interrupted")
+			}
+			interrupter.ResumeStub = func() {
+				callsMux.Lock()
+				defer callsMux.Unlock()
+				calls = append(calls, "resumed")
+			}
+			deployment.BackupStub = func(context.Context, executor.Executor) error {
+				go abort.Abort()
+				<-interrupting
+
+				aborted := make(chan bool)
+				go func() { aborted <- abort.Aborted() }()
+				select {
+				case reportedAborted = <-aborted:
+				case <-time.After(100 * time.Millisecond):
+				}
+				close(checked)
+				return errors.New("backup script was interrupted")
+			}
+		})
+
+		It("reports being aborted before the commands have been interrupted", func() {
+			Expect(reportedAborted).To(BeTrue())
+			Expect(backupErr).To(ContainElement(BeAssignableToTypeOf(orchestrator.AbortError{})))
+		})
+
+		It("only lets the unlock commands run once the interruption has finished", func() {
+			Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
+			callsMux.Lock()
+			defer callsMux.Unlock()
+			Expect(calls[0]).To(Equal("interrupted"))
+			Expect(calls[1:]).To(ContainElement("resumed"))
+		})
+	})
+
+	Context("when aborted during a step which succeeds", func() {
+		BeforeEach(func() {
+			deployment.PreBackupLockStub = func(context.Context, orchestrator.LockOrderer, executor.Executor) error {
+				abort.Abort()
+				return nil
+			}
+		})
+
+		It("does not run the steps which follow", func() {
+			Expect(deployment.BackupCallCount()).To(BeZero())
+		})
+
+		It("unlocks the jobs and fails with an abort error", func() {
+			Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
+			Expect(backupErr).To(ConsistOf(BeAssignableToTypeOf(orchestrator.AbortError{})))
+		})
+	})
+
+	Context("when aborted while unlocking", func() {
+		BeforeEach(func() {
+			deployment.PostBackupUnlockStub = func(context.Context, bool, orchestrator.LockOrderer, executor.Executor) error {
+				abort.Abort()
+				return nil
+			}
+		})
+
+		It("does not interrupt the unlock", func() {
+			Expect(interrupter.InterruptCallCount()).To(BeZero())
+		})
+
+		It("still cleans up, but does not drain the backup", func() {
+			Expect(deployment.CleanupCallCount()).To(Equal(1))
+			Expect(artifactCopier.DownloadBackupFromDeploymentCallCount()).To(BeZero())
+			Expect(backupErr).To(ConsistOf(BeAssignableToTypeOf(orchestrator.AbortError{})))
+		})
+	})
+
+	Context("when the jobs stay locked for longer than the lock timeout", func() {
+		BeforeEach(func() {
+			lockTimeout = time.Millisecond
+			deployment.PreBackupLockStub = func(context.Context, orchestrator.LockOrderer, executor.Executor) error {
+				time.Sleep(10 * time.Millisecond)
+				return nil
+			}
+		})
+
+		It("does not back up, but unlocks the jobs after a failed backup and cleans up", func() {
+			Expect(deployment.BackupCallCount()).To(BeZero())
+			Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
+			_, afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
+			Expect(afterSuccessfulBackup).To(BeFalse())
+			Expect(deployment.CleanupCallCount()).To(Equal(1))
+		})
+
+		It("fails with a timeout error", func() {
+			Expect(backupErr).To(ConsistOf(BeAssignableToTypeOf(orchestrator.TimeoutError{})))
+			Expect(backupErr.Error()).To(ContainSubstring("jobs were locked for longer than the lock timeout of 1ms"))
+		})
+	})
+
+	Context("when the backup is still running at the deadline of its context", func() {
+		var cancel context.CancelFunc
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+			deployment.BackupStub = func(ctx context.Context, _ executor.Executor) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+		})
+
+		AfterEach(func() {
+			cancel()
+		})
+
+		It("stops the backup, then unlocks the jobs and cleans up", func() {
+			Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
+			Expect(deployment.CleanupCallCount()).To(Equal(1))
+			Expect(artifactCopier.DownloadBackupFromDeploymentCallCount()).To(BeZero())
+		})
+
+		It("fails with a timeout error", func() {
+			Expect(backupErr).To(ContainElement(BeAssignableToTypeOf(orchestrator.TimeoutError{})))
+			Expect(backupErr.Error()).To(ContainSubstring("bbr did not finish before its timeout"))
+		})
+	})
+
+	It("is not aborted until Abort is called", func() {
+		Expect(abort.Aborted()).To(BeFalse())
+		abort.Abort()
+		Expect(abort.Aborted()).To(BeTrue())
+	})
+})
@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"context"
 	"time"
 )
 
@@ -14,10 +15,12 @@ func NewAddFinishTimeStep(nowFunc func() time.Time) Step {
 	}
 }
 
-func (s *AddFinishTimeStep) Run(session *Session) error {
+func (s *AddFinishTimeStep) Run(ctx context.Context, session *Session) error {
 	if session.CurrentArtifact() != nil {
 		return session.CurrentArtifact().AddFinishTime(s.nowFunc())
 	}
 
 	return nil
 }
+
+func (s *AddFinishTimeStep) cleansUp() {}
@@ -19,9 +19,32 @@ type Backup interface {
 	AddChecksum(ArtifactIdentifier, BackupChecksum) error
 	CreateMetadataFileWithStartTime(time.Time) error
 	AddFinishTime(time.Time) error
+	AddLog(name string, contents []byte) error
+	AddVerifyResults([]VerifyResult) error
 	FetchChecksum(ArtifactIdentifier) (BackupChecksum, error)
 	CalculateChecksum(ArtifactIdentifier) (BackupChecksum, error)
 	DeploymentMatches(string, []Instance) (bool, error)
+	OriginalInstanceID(name, index string) (string, error)
 	SaveManifest(manifest string) error
 	Valid() (bool, error)
 }
+
+//go:generate counterfeiter -o fakes/fake_abortable_writer.go . AbortableWriter
+
+// AbortableWriter is an artifact writer which can be closed without keeping what was written
+// to it, such as an upload which would otherwise be left unfinished in a bucket.
+type AbortableWriter interface {
+	Write([]byte) (int, error)
+	Close() error
+	CloseWithError(error) error
+}
+
+// closeWithError discards what was written to the writer if it allows that, and otherwise
+// just closes it. It returns the given error.
+func closeWithError(writer io.WriteCloser, err error) error {
+	if abortable, ok := writer.(AbortableWriter); ok {
+		return abortable.CloseWithError(err)
+	}
+	writer.Close()
+	return err
+}
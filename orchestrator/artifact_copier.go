@@ -1,53 +1,121 @@
 package orchestrator
 
 import (
+	"context"
+
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/progress"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ratelimit"
+	"github.com/pkg/errors"
 )
 
 //go:generate counterfeiter -o fakes/fake_artifact_copier.go . ArtifactCopier
 type ArtifactCopier interface {
-	DownloadBackupFromDeployment(Backup, Deployment) error
-	UploadBackupToDeployment(Backup, Deployment) error
+	DownloadBackupFromDeployment(context.Context, Backup, Deployment) error
+	DownloadMissingBackupFromDeployment(context.Context, Backup, Deployment) error
+	UploadBackupToDeployment(context.Context, Backup, Deployment) error
 }
 
 type artifactCopier struct {
 	Logger
 	executor executor.Executor
+	throttle ratelimit.Throttle
+	progress *progress.Reporter
 }
 
-func NewArtifactCopier(executor executor.Executor, logger Logger) ArtifactCopier {
+func NewArtifactCopier(executor executor.Executor, throttle ratelimit.Throttle, progress *progress.Reporter, logger Logger) ArtifactCopier {
 	return artifactCopier{
 		Logger:   logger,
 		executor: executor,
+		throttle: throttle,
+		progress: progress,
 	}
 }
 
-func (c artifactCopier) DownloadBackupFromDeployment(localBackup Backup, deployment Deployment) error {
+func (c artifactCopier) DownloadBackupFromDeployment(ctx context.Context, localBackup Backup, deployment Deployment) error {
 	instances := deployment.BackupableInstances()
 
 	var executables []executor.Executable
 	for _, instance := range instances {
 		for _, remoteBackupArtifact := range instance.ArtifactsToBackup() {
-			executables = append(executables, NewBackupDownloadExecutable(localBackup, remoteBackupArtifact, c.Logger))
+			executables = append(executables, NewBackupDownloadExecutable(localBackup, remoteBackupArtifact, deployment.Name(), c.throttle, c.progress, c.Logger))
 		}
 	}
 
-	errs := c.executor.Run([][]executor.Executable{executables})
+	errs := c.executor.Run(ctx, [][]executor.Executable{executables})
 
 	return ConvertErrors(errs)
 }
 
-func (c artifactCopier) UploadBackupToDeployment(localBackup Backup, deployment Deployment) error {
+// DownloadMissingBackupFromDeployment drains only the artifacts which have no checksum in the
+// local backup yet, from instances which still have their remote artifact directory.
+func (c artifactCopier) DownloadMissingBackupFromDeployment(ctx context.Context, localBackup Backup, deployment Deployment) error {
+	var executables []executor.Executable
+	var instancesToCleanup []Instance
+	var errs []error
+
+	for _, instance := range deployment.BackupableInstances() {
+		var missingArtifacts []BackupArtifact
+		for _, remoteBackupArtifact := range instance.ArtifactsToBackup() {
+			checksum, err := localBackup.FetchChecksum(remoteBackupArtifact)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if checksum != nil {
+				c.Logger.Info("bbr", "Skipping job %s on %s/%s -- already drained", remoteBackupArtifact.Name(), instance.Name(), instance.ID())
+				continue
+			}
+			missingArtifacts = append(missingArtifacts, remoteBackupArtifact)
+		}
+
+		if len(missingArtifacts) == 0 {
+			continue
+		}
+
+		exists, err := instance.ArtifactDirExists()
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "Error checking %s on instance %s/%s", ArtifactDirectory, instance.Name(), instance.ID()))
+			continue
+		}
+		if !exists {
+			errs = append(errs, errors.Errorf("Cannot resume backup: %s no longer exists on instance %s/%s", ArtifactDirectory, instance.Name(), instance.ID()))
+			continue
+		}
+
+		instancesToCleanup = append(instancesToCleanup, instance)
+		for _, remoteBackupArtifact := range missingArtifacts {
+			executables = append(executables, NewBackupDownloadExecutable(localBackup, remoteBackupArtifact, deployment.Name(), c.throttle, c.progress, c.Logger))
+		}
+	}
+
+	if len(errs) != 0 {
+		return ConvertErrors(errs)
+	}
+
+	errs = c.executor.Run(ctx, [][]executor.Executable{executables})
+	if len(errs) != 0 {
+		return ConvertErrors(errs)
+	}
+
+	// only clean up once everything has been drained, so a failed resume can be resumed again
+	for _, instance := range instancesToCleanup {
+		instance.MarkArtifactDirCreated()
+	}
+	return nil
+}
+
+func (c artifactCopier) UploadBackupToDeployment(ctx context.Context, localBackup Backup, deployment Deployment) error {
 	instances := deployment.RestorableInstances()
 
 	var executables []executor.Executable
 	for _, instance := range instances {
 		for _, remoteBackupArtifact := range instance.ArtifactsToRestore() {
-			executables = append(executables, NewBackupUploadExecutable(localBackup, remoteBackupArtifact, instance, c.Logger))
+			executables = append(executables, NewBackupUploadExecutable(localBackup, remoteBackupArtifact, instance, c.throttle, c.progress, c.Logger))
 		}
 	}
 
-	errs := c.executor.Run([][]executor.Executable{executables})
+	errs := c.executor.Run(ctx, [][]executor.Executable{executables})
 
 	return ConvertErrors(errs)
 }
@@ -1,11 +1,14 @@
 package orchestrator_test
 
 import (
+	"context"
 	"fmt"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	executorFakes "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor/fakes"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/progress"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ratelimit"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -24,23 +27,27 @@ var _ = Describe("ArtifactCopier", func() {
 
 		remoteBackup1 *fakes.FakeBackupArtifact
 		remoteBackup2 *fakes.FakeBackupArtifact
+
+		reporter *progress.Reporter
 	)
 
 	BeforeEach(func() {
 		logger = new(fakes.FakeLogger)
+		reporter = progress.NewReporter(progress.DefaultInterval, nil)
 		fakeExecutor = new(executorFakes.FakeExecutor)
 
 		instance1 = new(fakes.FakeInstance)
 		instance2 = new(fakes.FakeInstance)
 
 		deployment = new(fakes.FakeDeployment)
+		deployment.NameReturns("my-deployment")
 
 		localBackup = new(fakes.FakeBackup)
 
 		remoteBackup1 = new(fakes.FakeBackupArtifact)
 		remoteBackup2 = new(fakes.FakeBackupArtifact)
 
-		artifactCopier = orchestrator.NewArtifactCopier(fakeExecutor, logger)
+		artifactCopier = orchestrator.NewArtifactCopier(fakeExecutor, ratelimit.Throttle{}, reporter, logger)
 	})
 
 	Context("DownloadBackupFromDeployment", func() {
@@ -51,7 +58,7 @@ var _ = Describe("ArtifactCopier", func() {
 		})
 
 		JustBeforeEach(func() {
-			err = artifactCopier.DownloadBackupFromDeployment(localBackup, deployment)
+			err = artifactCopier.DownloadBackupFromDeployment(context.Background(), localBackup, deployment)
 		})
 
 		It("downloads the backup from deployment", func() {
@@ -70,9 +77,10 @@ var _ = Describe("ArtifactCopier", func() {
 
 			By("running the executor with the executables", func() {
 				Expect(fakeExecutor.RunCallCount()).To(Equal(1))
-				Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{{
-					orchestrator.NewBackupDownloadExecutable(localBackup, remoteBackup1, logger),
-					orchestrator.NewBackupDownloadExecutable(localBackup, remoteBackup2, logger),
+				_, batches := fakeExecutor.RunArgsForCall(0)
+				Expect(batches).To(Equal([][]executor.Executable{{
+					orchestrator.NewBackupDownloadExecutable(localBackup, remoteBackup1, "my-deployment", ratelimit.Throttle{}, reporter, logger),
+					orchestrator.NewBackupDownloadExecutable(localBackup, remoteBackup2, "my-deployment", ratelimit.Throttle{}, reporter, logger),
 				}}))
 			})
 		})
@@ -88,6 +96,65 @@ var _ = Describe("ArtifactCopier", func() {
 		})
 	})
 
+	Context("DownloadMissingBackupFromDeployment", func() {
+		BeforeEach(func() {
+			instance1.ArtifactsToBackupReturns([]orchestrator.BackupArtifact{remoteBackup1})
+			instance1.ArtifactDirExistsReturns(true, nil)
+			instance2.ArtifactsToBackupReturns([]orchestrator.BackupArtifact{remoteBackup2})
+			instance2.ArtifactDirExistsReturns(true, nil)
+			deployment.BackupableInstancesReturns([]orchestrator.Instance{instance1, instance2})
+
+			localBackup.FetchChecksumStub = func(artifact orchestrator.ArtifactIdentifier) (orchestrator.BackupChecksum, error) {
+				if artifact == remoteBackup1 {
+					return orchestrator.BackupChecksum{"file": "sha"}, nil
+				}
+				return nil, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			err = artifactCopier.DownloadMissingBackupFromDeployment(context.Background(), localBackup, deployment)
+		})
+
+		It("only downloads the artifacts without a checksum", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeExecutor.RunCallCount()).To(Equal(1))
+			_, batches := fakeExecutor.RunArgsForCall(0)
+			Expect(batches).To(Equal([][]executor.Executable{{
+				orchestrator.NewBackupDownloadExecutable(localBackup, remoteBackup2, "my-deployment", ratelimit.Throttle{}, reporter, logger),
+			}}))
+		})
+
+		It("marks the remote artifact directory for cleanup once drained", func() {
+			Expect(instance1.MarkArtifactDirCreatedCallCount()).To(Equal(0))
+			Expect(instance2.MarkArtifactDirCreatedCallCount()).To(Equal(1))
+		})
+
+		Context("when the remote artifact directory no longer exists", func() {
+			BeforeEach(func() {
+				instance2.NameReturns("redis")
+				instance2.IDReturns("0")
+				instance2.ArtifactDirExistsReturns(false, nil)
+			})
+
+			It("fails without downloading anything", func() {
+				Expect(err).To(MatchError(ContainSubstring("Cannot resume backup: /var/vcap/store/bbr-backup no longer exists on instance redis/0")))
+				Expect(fakeExecutor.RunCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("When the executor fails to run", func() {
+			BeforeEach(func() {
+				fakeExecutor.RunReturns([]error{fmt.Errorf("run error")})
+			})
+
+			It("fails and leaves the remote artifact directory in place", func() {
+				Expect(err).To(MatchError(ContainSubstring("run error")))
+				Expect(instance2.MarkArtifactDirCreatedCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Context("UploadBackupToDeployment", func() {
 		BeforeEach(func() {
 			instance1.ArtifactsToRestoreReturns([]orchestrator.BackupArtifact{remoteBackup1})
@@ -96,7 +163,7 @@ var _ = Describe("ArtifactCopier", func() {
 		})
 
 		JustBeforeEach(func() {
-			err = artifactCopier.UploadBackupToDeployment(localBackup, deployment)
+			err = artifactCopier.UploadBackupToDeployment(context.Background(), localBackup, deployment)
 		})
 
 		It("uploads the backup to the deployment", func() {
@@ -115,9 +182,10 @@ var _ = Describe("ArtifactCopier", func() {
 
 			By("running the executor with the executables", func() {
 				Expect(fakeExecutor.RunCallCount()).To(Equal(1))
-				Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{{
-					orchestrator.NewBackupUploadExecutable(localBackup, remoteBackup1, instance1, logger),
-					orchestrator.NewBackupUploadExecutable(localBackup, remoteBackup2, instance2, logger),
+				_, batches := fakeExecutor.RunArgsForCall(0)
+				Expect(batches).To(Equal([][]executor.Executable{{
+					orchestrator.NewBackupUploadExecutable(localBackup, remoteBackup1, instance1, ratelimit.Throttle{}, reporter, logger),
+					orchestrator.NewBackupUploadExecutable(localBackup, remoteBackup2, instance2, ratelimit.Throttle{}, reporter, logger),
 				}}))
 			})
 		})
@@ -1,17 +1,25 @@
 package orchestrator
 
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
+
 type BackupChecker struct {
 	*Workflow
 }
 
-func NewBackupChecker(logger Logger, deploymentManager DeploymentManager, lockOrderer LockOrderer) *BackupChecker {
+func NewBackupChecker(logger Logger, deploymentManager DeploymentManager, lockOrderer LockOrderer, checkExecutor executor.Executor) *BackupChecker {
 	checkDeployment := NewFindDeploymentStep(deploymentManager, logger)
 	backupable := NewBackupableStep(lockOrderer, logger)
+	preBackupCheck := NewPreBackupCheckStep(checkExecutor)
 	cleanup := NewCleanupStep()
 	workflow := NewWorkflow()
 
 	workflow.StartWith(checkDeployment).OnSuccess(backupable)
-	workflow.Add(backupable).OnSuccessOrFailure(cleanup)
+	workflow.Add(backupable).OnSuccess(preBackupCheck).OnFailure(cleanup)
+	workflow.Add(preBackupCheck).OnSuccessOrFailure(cleanup)
 	workflow.Add(cleanup)
 
 	return &BackupChecker{
@@ -19,10 +27,10 @@ func NewBackupChecker(logger Logger, deploymentManager DeploymentManager, lockOr
 	}
 }
 
-func (b BackupChecker) Check(deploymentName string) Error {
+func (b BackupChecker) Check(ctx context.Context, deploymentName string) Error {
 	session := NewSession(deploymentName)
 
-	err := b.Workflow.Run(session)
+	err := b.Workflow.Run(ctx, session)
 
 	return err
 }
@@ -1,11 +1,13 @@
 package orchestrator_test
 
 import (
+	"context"
 	"fmt"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
 )
@@ -25,11 +27,11 @@ var _ = Describe("Check", func() {
 		deployment = new(fakes.FakeDeployment)
 		deploymentManager = new(fakes.FakeDeploymentManager)
 		logger = new(fakes.FakeLogger)
-		b = orchestrator.NewBackupChecker(logger, deploymentManager, lockOrderer)
+		b = orchestrator.NewBackupChecker(logger, deploymentManager, lockOrderer, executor.NewParallelExecutor())
 	})
 
 	JustBeforeEach(func() {
-		actualCanBeBackedUpError = b.Check(deploymentName)
+		actualCanBeBackedUpError = b.Check(context.Background(), deploymentName)
 	})
 
 	Context("when the deployment can be backed up", func() {
@@ -53,6 +55,10 @@ var _ = Describe("Check", func() {
 			Expect(deployment.IsBackupableCallCount()).To(Equal(1))
 		})
 
+		It("runs the pre-backup-check scripts of the jobs", func() {
+			Expect(deployment.PreBackupCheckCallCount()).To(Equal(1))
+		})
+
 		It("shouldn't do a backup", func() {
 			Expect(deployment.BackupCallCount()).To(Equal(0))
 		})
@@ -94,6 +100,25 @@ var _ = Describe("Check", func() {
 		})
 	})
 
+	Context("when a job's pre-backup-check script fails", func() {
+		BeforeEach(func() {
+			deploymentManager.FindReturns(deployment, nil)
+			deployment.IsBackupableReturns(true)
+			deployment.HasUniqueCustomArtifactNamesReturns(true)
+			deployment.PreBackupCheckReturns(fmt.Errorf("replication is lagging - exit code 1"))
+		})
+
+		It("fails the check, showing the script's error", func() {
+			Expect(actualCanBeBackedUpError).To(ConsistOf(
+				MatchError(fmt.Sprintf("Deployment '%s' cannot be backed up: replication is lagging - exit code 1", deploymentName)),
+			))
+		})
+
+		It("ensures that deployment is cleaned up", func() {
+			Expect(deployment.CleanupCallCount()).To(Equal(1))
+		})
+	})
+
 	Context("fails if deployment is invalid", func() {
 		BeforeEach(func() {
 			deploymentManager.FindReturns(deployment, nil)
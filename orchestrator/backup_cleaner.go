@@ -1,35 +1,52 @@
 package orchestrator
 
-import "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
 
 func NewBackupCleaner(logger Logger, deploymentManager DeploymentManager, lockOrderer LockOrderer,
-	executor executor.Executor) *BackupCleaner {
+	executor executor.Executor, journals *Journals) *BackupCleaner {
 
 	workflow := NewWorkflow()
 	findDeploymentStep := NewFindDeploymentStep(deploymentManager, logger)
+	recoverJournalStep := NewRecoverJournalStep(logger)
 	postBackUnlockStep := NewPostBackupUnlockStep(false, lockOrderer, executor)
 	cleanupPreviousStep := NewCleanupPreviousStep()
 
-	workflow.StartWith(findDeploymentStep).OnSuccess(postBackUnlockStep)
+	workflow.StartWith(findDeploymentStep).OnSuccess(recoverJournalStep)
+	workflow.Add(recoverJournalStep).OnSuccess(postBackUnlockStep)
 	workflow.Add(postBackUnlockStep).OnSuccessOrFailure(cleanupPreviousStep)
 	workflow.Add(cleanupPreviousStep)
 
 	return &BackupCleaner{
 		Logger:   logger,
 		Workflow: workflow,
+		journals: journals,
 	}
 }
 
 type BackupCleaner struct {
 	Logger
 	*Workflow
+	journals *Journals
 }
 
 func (c BackupCleaner) Cleanup(deploymentName string) Error {
+	journal, err := c.journals.Read(deploymentName)
+	if err != nil {
+		return NewError(err)
+	}
+
 	session := NewSession(deploymentName)
-	currentError := c.Workflow.Run(session)
+	session.SetJournal(journal)
+	currentError := c.Workflow.Run(context.Background(), session)
 
 	if len(currentError) == 0 {
+		if err := journal.Clear(); err != nil {
+			return NewError(NewCleanupError(err.Error()))
+		}
 		c.Logger.Info("bbr", "'%s' cleaned up\n", deploymentName)
 	}
 	return currentError
@@ -1,7 +1,11 @@
 package orchestrator_test
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
@@ -20,6 +24,7 @@ var _ = Describe("Cleanup", func() {
 		cleanupError      error
 		logger            *fakes.FakeLogger
 		lockOrderer       *fakes.FakeLockOrderer
+		journals          *orchestrator.Journals
 	)
 
 	BeforeEach(func() {
@@ -27,10 +32,11 @@ var _ = Describe("Cleanup", func() {
 		deploymentManager = new(fakes.FakeDeploymentManager)
 		logger = new(fakes.FakeLogger)
 		lockOrderer = new(fakes.FakeLockOrderer)
-		backupCleaner = orchestrator.NewBackupCleaner(logger, deploymentManager, lockOrderer, executor.NewSerialExecutor())
+		journals = nil
 	})
 
 	JustBeforeEach(func() {
+		backupCleaner = orchestrator.NewBackupCleaner(logger, deploymentManager, lockOrderer, executor.NewSerialExecutor(), journals)
 		cleanupError = backupCleaner.Cleanup(deploymentName)
 	})
 
@@ -51,7 +57,7 @@ var _ = Describe("Cleanup", func() {
 
 		It("ensures that deployment is unlocked using the provided lockOrderer", func() {
 			Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
-			actualAfterSuccessfulBackup, actualLockOrderer, _ := deployment.PostBackupUnlockArgsForCall(0)
+			_, actualAfterSuccessfulBackup, actualLockOrderer, _ := deployment.PostBackupUnlockArgsForCall(0)
 			Expect(actualAfterSuccessfulBackup).To(BeFalse())
 			Expect(actualLockOrderer).To(Equal(lockOrderer))
 		})
@@ -61,7 +67,7 @@ var _ = Describe("Cleanup", func() {
 		var currentSequenceNumber, unlockCallIndex, cleanupCallIndex int
 		BeforeEach(func() {
 			deploymentManager.FindReturns(deployment, nil)
-			deployment.PostBackupUnlockStub = func(afterSuccessfulBackup bool, orderer orchestrator.LockOrderer, runner executor.Executor) error {
+			deployment.PostBackupUnlockStub = func(_ context.Context, afterSuccessfulBackup bool, orderer orchestrator.LockOrderer, runner executor.Executor) error {
 				unlockCallIndex = currentSequenceNumber
 				currentSequenceNumber = currentSequenceNumber + 1
 				return nil
@@ -149,4 +155,107 @@ var _ = Describe("Cleanup", func() {
 			Expect(cleanupError.Error()).To(ContainSubstring(instanceCleanupError.Error()))
 		})
 	})
+
+	Context("when the interrupted backup left a journal behind", func() {
+		var (
+			journalDir             string
+			instance, notJournaled *fakes.FakeInstance
+			lockedJob, unlockedJob *fakes.FakeJob
+		)
+
+		BeforeEach(func() {
+			var err error
+			journalDir, err = ioutil.TempDir("", "journal")
+			Expect(err).NotTo(HaveOccurred())
+			journals = orchestrator.NewJournals(journalDir)
+
+			lockedJob = new(fakes.FakeJob)
+			lockedJob.NameReturns("locked")
+			lockedJob.InstanceIdentifierReturns("redis/0")
+			unlockedJob = new(fakes.FakeJob)
+			unlockedJob.NameReturns("unlocked")
+			unlockedJob.InstanceIdentifierReturns("redis/0")
+			goneJob := new(fakes.FakeJob)
+			goneJob.NameReturns("locked")
+			goneJob.InstanceIdentifierReturns("redis/gone")
+
+			crashedInstance := new(fakes.FakeInstance)
+			crashedInstance.NameReturns("redis")
+			crashedInstance.IDReturns("0")
+			crashedInstance.SSHUserReturns("bbr-crashed")
+
+			journal, err := journals.Start(deploymentName, "backup")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(journal.AddSSHUsers([]orchestrator.Instance{crashedInstance})).To(Succeed())
+			Expect(journal.AddLockedJobs([]orchestrator.Job{lockedJob, goneJob})).To(Succeed())
+			Expect(journal.AddArtifactDirectories([]orchestrator.Instance{crashedInstance})).To(Succeed())
+
+			instance = new(fakes.FakeInstance)
+			instance.NameReturns("redis")
+			instance.IDReturns("0")
+			instance.SSHUserReturns("bbr-cleanup")
+			instance.JobsReturns([]orchestrator.Job{lockedJob, unlockedJob})
+			notJournaled = new(fakes.FakeInstance)
+			notJournaled.NameReturns("redis")
+			notJournaled.IDReturns("1")
+			notJournaled.SSHUserReturns("bbr-cleanup")
+
+			deployment.NameReturns(deploymentName)
+			deployment.InstancesReturns([]orchestrator.Instance{instance, notJournaled})
+			deploymentManager.FindReturns(deployment, nil)
+			lockOrderer.OrderStub = func(jobs []orchestrator.Job) ([][]orchestrator.Job, error) {
+				return [][]orchestrator.Job{jobs}, nil
+			}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(journalDir)).To(Succeed())
+		})
+
+		It("only unlocks the jobs which the journal lists", func() {
+			Expect(lockedJob.PostBackupUnlockCallCount()).To(Equal(1))
+			Expect(unlockedJob.PostBackupUnlockCallCount()).To(BeZero())
+		})
+
+		It("only removes the artifact directories which the journal lists", func() {
+			Expect(deployment.CleanupPreviousCallCount()).To(BeZero())
+			Expect(instance.MarkArtifactDirCreatedCallCount()).To(Equal(1))
+			Expect(notJournaled.MarkArtifactDirCreatedCallCount()).To(BeZero())
+			Expect(instance.CleanupCallCount()).To(Equal(1))
+			Expect(notJournaled.CleanupCallCount()).To(Equal(1))
+		})
+
+		It("removes the SSH users which the journal lists", func() {
+			Expect(instance.RemoveSSHUserCallCount()).To(Equal(1))
+			Expect(instance.RemoveSSHUserArgsForCall(0)).To(Equal("bbr-crashed"))
+			Expect(notJournaled.RemoveSSHUserCallCount()).To(BeZero())
+		})
+
+		It("reports what the journal lists, including what is no longer part of the deployment", func() {
+			_, message, args := logger.InfoArgsForCall(1)
+			Expect(fmt.Sprintf(message, args...)).To(ContainSubstring("job locked on redis/gone is locked"))
+
+			Expect(logger.WarnCallCount()).To(Equal(1))
+			_, message, args = logger.WarnArgsForCall(0)
+			Expect(fmt.Sprintf(message, args...)).To(Equal("Cannot unlock job locked, as redis/gone is no longer part of the deployment"))
+		})
+
+		It("removes the journal", func() {
+			Expect(cleanupError).To(BeNil())
+			Expect(filepath.Join(journalDir, deploymentName+".bbr-journal")).NotTo(BeAnExistingFile())
+		})
+
+		Context("and the unlock fails", func() {
+			BeforeEach(func() {
+				lockedJob.PostBackupUnlockReturns(fmt.Errorf("unlock error"))
+			})
+
+			It("keeps the journal, so that the cleanup can be retried", func() {
+				Expect(cleanupError).To(MatchError(ContainSubstring("unlock error")))
+				journal, err := journals.Read(deploymentName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(journal.Entries().LockedJobs).To(ContainElement(orchestrator.JournalJob{Instance: "redis/0", Job: "locked"}))
+			})
+		})
+	})
 })
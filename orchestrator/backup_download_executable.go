@@ -1,26 +1,37 @@
 package orchestrator
 
 import (
+	"context"
 	"fmt"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/event"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/progress"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ratelimit"
 	"github.com/pkg/errors"
 )
 
 type BackupDownloadExecutable struct {
 	localBackup    Backup
 	remoteArtifact BackupArtifact
+	deploymentName string
+	throttle       ratelimit.Throttle
+	progress       *progress.Reporter
 	Logger
 }
 
-func NewBackupDownloadExecutable(localBackup Backup, remoteArtifact BackupArtifact, logger Logger) BackupDownloadExecutable {
+func NewBackupDownloadExecutable(localBackup Backup, remoteArtifact BackupArtifact, deploymentName string, throttle ratelimit.Throttle, progress *progress.Reporter, logger Logger) BackupDownloadExecutable {
 	return BackupDownloadExecutable{
 		localBackup:    localBackup,
 		remoteArtifact: remoteArtifact,
+		deploymentName: deploymentName,
+		throttle:       throttle,
+		progress:       progress,
 		Logger:         logger,
 	}
 }
 
-func (e BackupDownloadExecutable) Execute() error {
-	err := e.downloadBackupArtifact(e.localBackup, e.remoteArtifact)
+func (e BackupDownloadExecutable) Execute(ctx context.Context) error {
+	size, err := e.downloadBackupArtifact(e.localBackup, e.remoteArtifact)
 	if err != nil {
 		return err
 	}
@@ -41,33 +52,44 @@ func (e BackupDownloadExecutable) Execute() error {
 	}
 
 	e.Logger.Info("bbr", "Finished validity checks -- for job %s on %s/%s...", e.remoteArtifact.Name(), e.remoteArtifact.InstanceName(), e.remoteArtifact.InstanceID())
+
+	event.Record(event.Event{
+		Type:       event.ArtifactDrained,
+		Deployment: e.deploymentName,
+		Instance:   e.remoteArtifact.InstanceName() + "/" + e.remoteArtifact.InstanceID(),
+		Artifact:   e.remoteArtifact.Name(),
+		Size:       size,
+		Checksums:  checksum,
+	})
 	return nil
 }
 
-func (e BackupDownloadExecutable) downloadBackupArtifact(localBackup Backup, remoteBackupArtifact BackupArtifact) error {
+func (e BackupDownloadExecutable) downloadBackupArtifact(localBackup Backup, remoteBackupArtifact BackupArtifact) (string, error) {
 	localBackupArtifactWriter, err := localBackup.CreateArtifact(remoteBackupArtifact)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	size, err := remoteBackupArtifact.Size()
 	if err != nil {
-		return err
+		return "", closeWithError(localBackupArtifactWriter, err)
 	}
 
 	e.Logger.Info("bbr", "Copying backup -- %s uncompressed -- for job %s on %s/%s...", size, remoteBackupArtifact.Name(), remoteBackupArtifact.InstanceName(), remoteBackupArtifact.InstanceID())
-	err = remoteBackupArtifact.StreamFromRemote(localBackupArtifactWriter)
+	transfer := e.progress.Start(fmt.Sprintf("for job %s on %s/%s", remoteBackupArtifact.Name(), remoteBackupArtifact.InstanceName(), remoteBackupArtifact.InstanceID()), size, e.Logger)
+	err = remoteBackupArtifact.StreamFromRemote(e.throttle.Writer(transfer.Writer(localBackupArtifactWriter)))
+	transfer.Finish()
 	if err != nil {
-		return err
+		return "", closeWithError(localBackupArtifactWriter, err)
 	}
 
 	err = localBackupArtifactWriter.Close()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	e.Logger.Info("bbr", "Finished copying backup -- for job %s on %s/%s...", remoteBackupArtifact.Name(), remoteBackupArtifact.InstanceName(), remoteBackupArtifact.InstanceID())
-	return nil
+	return size, nil
 }
 
 func (e BackupDownloadExecutable) compareChecksums(localBackup Backup, remoteBackupArtifact BackupArtifact) (BackupChecksum, error) {
@@ -1,10 +1,16 @@
 package orchestrator_test
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/event"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/progress"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ratelimit"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -28,8 +34,8 @@ var _ = Describe("BackupDownloadExecutable", func() {
 	})
 
 	JustBeforeEach(func() {
-		executable = orchestrator.NewBackupDownloadExecutable(localBackup, remoteArtifact, logger)
-		actualError = executable.Execute()
+		executable = orchestrator.NewBackupDownloadExecutable(localBackup, remoteArtifact, "my-deployment", ratelimit.Throttle{}, progress.NewReporter(progress.DefaultInterval, nil), logger)
+		actualError = executable.Execute(context.Background())
 	})
 
 	It("downloads the artifact", func() {
@@ -48,7 +54,9 @@ var _ = Describe("BackupDownloadExecutable", func() {
 
 		By("streaming from the remote artifact", func() {
 			Expect(remoteArtifact.StreamFromRemoteCallCount()).To(Equal(1))
-			Expect(remoteArtifact.StreamFromRemoteArgsForCall(0)).To(Equal(localBackupArtifactWriter))
+			remoteWriter := remoteArtifact.StreamFromRemoteArgsForCall(0)
+			remoteWriter.Write([]byte("backup-data"))
+			Expect(localBackupArtifactWriter.WriteArgsForCall(0)).To(Equal([]byte("backup-data")))
 		})
 
 		By("closing the local backup artifact writer", func() {
@@ -68,6 +76,37 @@ var _ = Describe("BackupDownloadExecutable", func() {
 		})
 	})
 
+	Context("when events are being recorded", func() {
+		var events *bytes.Buffer
+
+		BeforeEach(func() {
+			events = new(bytes.Buffer)
+			event.SetOutput(events)
+
+			remoteArtifact.NameReturns("redis")
+			remoteArtifact.InstanceNameReturns("redis-server")
+			remoteArtifact.InstanceIDReturns("0")
+			remoteArtifact.SizeReturns("4.0K", nil)
+			remoteArtifact.ChecksumReturns(orchestrator.BackupChecksum{"dump.rdb": "abc"}, nil)
+			localBackup.CalculateChecksumReturns(orchestrator.BackupChecksum{"dump.rdb": "abc"}, nil)
+		})
+
+		AfterEach(func() {
+			event.SetOutput(nil)
+		})
+
+		It("records the size and checksums of the drained artifact", func() {
+			Expect(events.String()).To(SatisfyAll(
+				ContainSubstring(`"type":"artifact_drained"`),
+				ContainSubstring(`"deployment":"my-deployment"`),
+				ContainSubstring(`"instance":"redis-server/0"`),
+				ContainSubstring(`"artifact":"redis"`),
+				ContainSubstring(`"size":"4.0K"`),
+				ContainSubstring(`"checksums":{"dump.rdb":"abc"}`),
+			))
+		})
+	})
+
 	Context("When the local artifact cannot be created", func() {
 		BeforeEach(func() {
 			localBackup.CreateArtifactReturns(nil, fmt.Errorf("create artifact error"))
@@ -96,6 +135,27 @@ var _ = Describe("BackupDownloadExecutable", func() {
 		It("should fail", func() {
 			Expect(actualError).To(MatchError("stream error"))
 		})
+
+		It("closes the local artifact", func() {
+			Expect(localBackupArtifactWriter.CloseCallCount()).To(Equal(1))
+		})
+
+		Context("and the local artifact can be abandoned", func() {
+			var abortableWriter *fakes.FakeAbortableWriter
+
+			BeforeEach(func() {
+				abortableWriter = new(fakes.FakeAbortableWriter)
+				abortableWriter.CloseWithErrorStub = func(err error) error { return err }
+				localBackup.CreateArtifactReturns(abortableWriter, nil)
+			})
+
+			It("abandons it rather than closing it", func() {
+				Expect(actualError).To(MatchError("stream error"))
+				Expect(abortableWriter.CloseWithErrorCallCount()).To(Equal(1))
+				Expect(abortableWriter.CloseWithErrorArgsForCall(0)).To(MatchError("stream error"))
+				Expect(abortableWriter.CloseCallCount()).To(BeZero())
+			})
+		})
 	})
 
 	Context("When the local backup writer cannot be closed", func() {
@@ -1,13 +1,18 @@
 package orchestrator
 
+import "context"
+
 type BackupExecutable struct {
 	Job
+	deploymentName string
 }
 
-func NewBackupExecutable(j Job) BackupExecutable {
-	return BackupExecutable{j}
+func NewBackupExecutable(j Job, deploymentName string) BackupExecutable {
+	return BackupExecutable{Job: j, deploymentName: deploymentName}
 }
 
-func (e BackupExecutable) Execute() error {
-	return e.Job.Backup()
+func (e BackupExecutable) Execute(ctx context.Context) error {
+	err := e.Job.Backup(ctx)
+	recordJobFinished(e.Job, e.deploymentName, "backup", err)
+	return err
 }
@@ -1,6 +1,7 @@
 package orchestrator_test
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
@@ -23,10 +24,10 @@ var _ = Describe("BackupExecutables", func() {
 
 	Context("NewBackupExecutable", func() {
 		BeforeEach(func() {
-			executable = orchestrator.NewBackupExecutable(fakeJob)
+			executable = orchestrator.NewBackupExecutable(fakeJob, "my-deployment")
 		})
 		JustBeforeEach(func() {
-			err = executable.Execute()
+			err = executable.Execute(context.Background())
 		})
 
 		It("executes backup", func() {
@@ -1,16 +1,24 @@
 package orchestrator
 
-import "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
 
 type BackupStep struct {
 	executor executor.Executor
 }
 
-func (s *BackupStep) Run(session *Session) error {
-	err := session.CurrentDeployment().Backup(s.executor)
-	if err != nil {
+func (s *BackupStep) Run(ctx context.Context, session *Session) error {
+	if err := session.Journal().AddArtifactDirectories(session.CurrentDeployment().BackupableInstances()); err != nil {
 		return NewBackupError(err.Error())
 	}
+
+	err := session.CurrentDeployment().Backup(ctx, s.executor)
+	if err != nil {
+		return withTimeout(NewBackupError(err.Error()), err)
+	}
 	return nil
 }
 
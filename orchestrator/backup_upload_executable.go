@@ -1,8 +1,12 @@
 package orchestrator
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/progress"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ratelimit"
+
 	"github.com/pkg/errors"
 )
 
@@ -10,19 +14,23 @@ type BackupUploadExecutable struct {
 	localBackup    Backup
 	remoteArtifact BackupArtifact
 	instance       Instance
+	throttle       ratelimit.Throttle
+	progress       *progress.Reporter
 	Logger
 }
 
-func NewBackupUploadExecutable(localBackup Backup, remoteArtifact BackupArtifact, instance Instance, logger Logger) BackupUploadExecutable {
+func NewBackupUploadExecutable(localBackup Backup, remoteArtifact BackupArtifact, instance Instance, throttle ratelimit.Throttle, progress *progress.Reporter, logger Logger) BackupUploadExecutable {
 	return BackupUploadExecutable{
 		localBackup:    localBackup,
 		remoteArtifact: remoteArtifact,
 		instance:       instance,
+		throttle:       throttle,
+		progress:       progress,
 		Logger:         logger,
 	}
 }
 
-func (e BackupUploadExecutable) Execute() error {
+func (e BackupUploadExecutable) Execute(ctx context.Context) error {
 	localBackupArtifactReader, err := e.localBackup.ReadArtifact(e.remoteArtifact)
 	if err != nil {
 		return err
@@ -33,8 +41,14 @@ func (e BackupUploadExecutable) Execute() error {
 		return err
 	}
 
-	e.Logger.Info("bbr", "Copying backup -- %s uncompressed -- for job %s on %s/%s...", size, e.remoteArtifact.Name(), e.instance.Name(), e.instance.Index())
-	err = e.remoteArtifact.StreamToRemote(localBackupArtifactReader)
+	if size == "" {
+		e.Logger.Info("bbr", "Copying backup -- for job %s on %s/%s...", e.remoteArtifact.Name(), e.instance.Name(), e.instance.Index())
+	} else {
+		e.Logger.Info("bbr", "Copying backup -- %s uncompressed -- for job %s on %s/%s...", size, e.remoteArtifact.Name(), e.instance.Name(), e.instance.Index())
+	}
+	transfer := e.progress.Start(fmt.Sprintf("for job %s on %s/%s", e.remoteArtifact.Name(), e.instance.Name(), e.instance.Index()), size, e.Logger)
+	err = e.remoteArtifact.StreamToRemote(e.throttle.Reader(transfer.Reader(localBackupArtifactReader)))
+	transfer.Finish()
 	if err != nil {
 		return err
 	}
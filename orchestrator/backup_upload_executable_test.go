@@ -1,6 +1,8 @@
 package orchestrator_test
 
 import (
+	"context"
+
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/pkg/errors"
 
@@ -11,6 +13,8 @@ import (
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/progress"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ratelimit"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -24,12 +28,14 @@ var _ = Describe("BackupUploadExecutable", func() {
 		logger                    *fakes.FakeLogger
 		actualError               error
 		localBackupArtifactReader io.ReadCloser
+		throttle                  ratelimit.Throttle
 	)
 	BeforeEach(func() {
 		backup = new(fakes.FakeBackup)
 		remoteArtifact = new(fakes.FakeBackupArtifact)
 		instance = new(fakes.FakeInstance)
 		logger = new(fakes.FakeLogger)
+		throttle = ratelimit.Throttle{}
 
 		localBackupArtifactReader = ioutil.NopCloser(bytes.NewBufferString("this-is-some-backup-data"))
 		backup.ReadArtifactReturns(localBackupArtifactReader, nil)
@@ -38,8 +44,8 @@ var _ = Describe("BackupUploadExecutable", func() {
 	})
 
 	JustBeforeEach(func() {
-		executable = orchestrator.NewBackupUploadExecutable(backup, remoteArtifact, instance, logger)
-		actualError = executable.Execute()
+		executable = orchestrator.NewBackupUploadExecutable(backup, remoteArtifact, instance, throttle, progress.NewReporter(progress.DefaultInterval, nil), logger)
+		actualError = executable.Execute(context.Background())
 
 	})
 
@@ -62,7 +68,9 @@ var _ = Describe("BackupUploadExecutable", func() {
 
 			By("streaming local artifact to remote", func() {
 				Expect(remoteArtifact.StreamToRemoteCallCount()).To(Equal(1))
-				Expect(remoteArtifact.StreamToRemoteArgsForCall(0)).To(Equal(localBackupArtifactReader))
+				data, err := ioutil.ReadAll(remoteArtifact.StreamToRemoteArgsForCall(0))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(data)).To(Equal("this-is-some-backup-data"))
 			})
 
 			By("marking the director created", func() {
@@ -88,6 +96,35 @@ var _ = Describe("BackupUploadExecutable", func() {
 		})
 	})
 
+	Context("When the backup does not know the size of the artifact", func() {
+		BeforeEach(func() {
+			backup.GetArtifactSizeReturns("", nil)
+		})
+
+		It("logs the upload without a size", func() {
+			Expect(actualError).NotTo(HaveOccurred())
+			_, logMsg, _ := logger.InfoArgsForCall(0)
+			Expect(logMsg).To(Equal("Copying backup -- for job %s on %s/%s..."))
+		})
+	})
+
+	Context("When transfers are throttled", func() {
+		BeforeEach(func() {
+			throttle = ratelimit.NewThrottle(0, 1024*1024)
+			remoteArtifact.StreamToRemoteStub = func(reader io.Reader) error {
+				data, err := ioutil.ReadAll(reader)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(data)).To(Equal("this-is-some-backup-data"))
+				return nil
+			}
+		})
+
+		It("streams the artifact through a rate limited reader", func() {
+			Expect(actualError).NotTo(HaveOccurred())
+			Expect(remoteArtifact.StreamToRemoteCallCount()).To(Equal(1))
+		})
+	})
+
 	Context("When the artifact size fails to be calculated", func() {
 		BeforeEach(func() {
 			backup.GetArtifactSizeReturns("1G", errors.New("I failed"))
@@ -1,45 +1,72 @@
 package orchestrator
 
 import (
+	"context"
 	"time"
 
 	exe "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 )
 
 func NewBackuper(backupManager BackupManager, logger Logger, deploymentManager DeploymentManager,
-	lockOrderer LockOrderer, executor exe.Executor, nowFunc func() time.Time, artifactCopier ArtifactCopier, timestamp string) *Backuper {
+	lockOrderer LockOrderer, lockExecutor, backupExecutor exe.Executor, nowFunc func() time.Time, artifactCopier ArtifactCopier, timestamp string,
+	scriptEnvironment *ScriptEnvironment, scriptLogs *ScriptLogs, journals *Journals, abort *Abort, lockTimeout time.Duration, keptArtifactsHint string) *Backuper {
 
 	findDeploymentStep := NewFindDeploymentStep(deploymentManager, logger)
 	backupable := NewBackupableStep(lockOrderer, logger)
-	createArtifact := NewCreateArtifactStep(logger, backupManager, deploymentManager, nowFunc, timestamp)
-	lock := NewLockStep(lockOrderer, executor)
+	preBackupCheck := NewPreBackupCheckStep(lockExecutor)
+	createArtifact := NewCreateArtifactStep(logger, backupManager, deploymentManager, nowFunc, timestamp, scriptEnvironment)
+	lock := NewLockStep(lockOrderer, lockExecutor)
 
-	backup := NewBackupStep(executor)
-	unlockAfterSuccessfulBackup := NewPostBackupUnlockStep(true, lockOrderer, executor)
-	unlockAfterFailedBackup := NewPostBackupUnlockStep(false, lockOrderer, executor)
+	backup := NewBackupStep(backupExecutor)
+	verify := NewPostBackupVerifyStep(logger, backupExecutor)
+	unlockAfterSuccessfulBackup := NewPostBackupUnlockStep(true, lockOrderer, lockExecutor)
+	unlockAfterFailedBackup := NewPostBackupUnlockStep(false, lockOrderer, lockExecutor)
 	drain := NewDrainStep(logger, artifactCopier)
+	keepArtifacts := NewKeepArtifactsStep(logger, keptArtifactsHint)
 	cleanup := NewCleanupStep()
+	saveScriptLogs := NewSaveScriptLogsStep(logger, scriptLogs)
 	addFinishTimeStep := NewAddFinishTimeStep(nowFunc)
 
-	workflow := NewWorkflow()
+	workflow := NewAbortableWorkflow(abort, lockTimeout)
 	workflow.StartWith(findDeploymentStep).OnSuccess(backupable)
-	workflow.Add(backupable).OnSuccess(createArtifact).OnFailure(cleanup)
+	workflow.Add(backupable).OnSuccess(preBackupCheck).OnFailure(cleanup)
+	workflow.Add(preBackupCheck).OnSuccess(createArtifact).OnFailure(cleanup)
 	workflow.Add(createArtifact).OnSuccess(lock).OnFailure(cleanup)
 	workflow.Add(lock).OnSuccess(backup).OnFailure(unlockAfterFailedBackup)
-	workflow.Add(backup).OnSuccess(unlockAfterSuccessfulBackup).OnFailure(unlockAfterFailedBackup)
+	workflow.Add(backup).OnSuccess(verify).OnFailure(unlockAfterFailedBackup)
+	workflow.Add(verify).OnSuccess(unlockAfterSuccessfulBackup).OnFailure(unlockAfterFailedBackup)
 	workflow.Add(unlockAfterSuccessfulBackup).OnSuccessOrFailure(drain)
 	workflow.Add(unlockAfterFailedBackup).OnSuccessOrFailure(cleanup)
-	workflow.Add(drain).OnSuccessOrFailure(cleanup)
-	workflow.Add(cleanup).OnSuccessOrFailure(addFinishTimeStep)
+	workflow.Add(drain).OnSuccess(cleanup).OnFailure(keepArtifacts)
+	workflow.Add(keepArtifacts).OnSuccessOrFailure(cleanup)
+	workflow.Add(cleanup).OnSuccessOrFailure(saveScriptLogs)
+	workflow.Add(saveScriptLogs).OnSuccessOrFailure(addFinishTimeStep)
 	workflow.Add(addFinishTimeStep)
 
+	reopenArtifact := NewReopenArtifactStep(logger, backupManager)
+	resumeDrain := NewResumeDrainStep(logger, artifactCopier)
+
+	// the finish time is only added once every artifact has been drained, as a backup without
+	// one is taken to be incomplete
+	resumeWorkflow := NewAbortableWorkflow(abort, lockTimeout)
+	resumeWorkflow.StartWith(findDeploymentStep).OnSuccess(reopenArtifact)
+	resumeWorkflow.Add(reopenArtifact).OnSuccess(resumeDrain).OnFailure(cleanup)
+	resumeWorkflow.Add(resumeDrain).OnSuccess(addFinishTimeStep).OnFailure(keepArtifacts)
+	resumeWorkflow.Add(addFinishTimeStep).OnSuccessOrFailure(cleanup)
+	resumeWorkflow.Add(keepArtifacts).OnSuccessOrFailure(cleanup)
+	resumeWorkflow.Add(cleanup)
+
 	return &Backuper{
-		workflow: workflow,
+		workflow:       workflow,
+		resumeWorkflow: resumeWorkflow,
+		journals:       journals,
 	}
 }
 
 type Backuper struct {
-	workflow *Workflow
+	workflow       *Workflow
+	resumeWorkflow *Workflow
+	journals       *Journals
 }
 
 type AuthInfo struct {
@@ -48,11 +75,30 @@ type AuthInfo struct {
 }
 
 //Backup checks if a deployment has backupable instances and backs them up.
-func (b Backuper) Backup(deploymentName, artifactPath string) Error {
+func (b Backuper) Backup(ctx context.Context, deploymentName, artifactPath string) Error {
+	journal, err := b.journals.Start(deploymentName, "backup")
+	if err != nil {
+		return NewError(err)
+	}
+
 	session := NewSession(deploymentName)
 	session.SetCurrentArtifactPath(artifactPath)
+	session.SetJournal(journal)
 
-	err := b.workflow.Run(session)
+	return b.workflow.Run(ctx, session)
+}
+
+//Resume drains the artifacts missing from an existing backup, without running any scripts.
+//It carries on the journal of the interrupted backup, if there is one.
+func (b Backuper) Resume(ctx context.Context, deploymentName, backupPath string) Error {
+	journal, err := b.journals.Continue(deploymentName, "backup")
+	if err != nil {
+		return NewError(err)
+	}
+
+	session := NewSession(deploymentName)
+	session.SetCurrentArtifactPath(backupPath)
+	session.SetJournal(journal)
 
-	return err
+	return b.resumeWorkflow.Run(ctx, session)
 }
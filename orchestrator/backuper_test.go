@@ -1,15 +1,21 @@
 package orchestrator_test
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"time"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	goerr "github.com/pkg/errors"
 )
 
 var _ = Describe("Backup", func() {
@@ -26,6 +32,8 @@ var _ = Describe("Backup", func() {
 		startTime, finishTime time.Time
 		artifactCopier        *fakes.FakeArtifactCopier
 		timeStamp             string
+		scriptEnvironment     *orchestrator.ScriptEnvironment
+		scriptLogs            *orchestrator.ScriptLogs
 	)
 
 	BeforeEach(func() {
@@ -47,11 +55,13 @@ var _ = Describe("Backup", func() {
 		}
 
 		artifactCopier = new(fakes.FakeArtifactCopier)
-		b = orchestrator.NewBackuper(fakeBackupManager, logger, deploymentManager, lockOrderer, executor.NewParallelExecutor(), nowFunc, artifactCopier, timeStamp)
+		scriptEnvironment = orchestrator.NewScriptEnvironment("1.2.3", nil)
+		scriptLogs = orchestrator.NewScriptLogs()
+		b = orchestrator.NewBackuper(fakeBackupManager, logger, deploymentManager, lockOrderer, executor.NewParallelExecutor(), executor.NewParallelExecutor(), nowFunc, artifactCopier, timeStamp, scriptEnvironment, scriptLogs, nil, nil, 0, "Run `bbr deployment backup --resume` to drain the rest of it.")
 	})
 
 	JustBeforeEach(func() {
-		actualBackupError = b.Backup(deploymentName, "")
+		actualBackupError = b.Backup(context.Background(), deploymentName, "")
 	})
 
 	Context("backs up a deployment", func() {
@@ -68,6 +78,14 @@ var _ = Describe("Backup", func() {
 			Expect(actualBackupError).NotTo(HaveOccurred())
 		})
 
+		It("tells job scripts which backup is being taken", func() {
+			Expect(scriptEnvironment.Variables("redis", "0")).To(Equal(map[string]string{
+				"BBR_VERSION":          "1.2.3",
+				"BBR_BACKUP_ID":        deploymentName + "_" + timeStamp,
+				"BBR_BACKUP_TIMESTAMP": timeStamp,
+			}))
+		})
+
 		It("finds the deployment", func() {
 			Expect(deploymentManager.FindCallCount()).To(Equal(1))
 			Expect(deploymentManager.FindArgsForCall(0)).To(Equal(deploymentName))
@@ -84,6 +102,10 @@ var _ = Describe("Backup", func() {
 			Expect(deployment.IsBackupableCallCount()).To(Equal(1))
 		})
 
+		It("runs pre-backup-check scripts on the deployment before locking it", func() {
+			Expect(deployment.PreBackupCheckCallCount()).To(Equal(1))
+		})
+
 		It("runs pre-backup-lock scripts on the deployment", func() {
 			Expect(deployment.PreBackupLockCallCount()).To(Equal(1))
 		})
@@ -92,9 +114,13 @@ var _ = Describe("Backup", func() {
 			Expect(deployment.BackupCallCount()).To(Equal(1))
 		})
 
+		It("runs post-backup-verify scripts on the deployment", func() {
+			Expect(deployment.PostBackupVerifyCallCount()).To(Equal(1))
+		})
+
 		It("runs post-backup-unlock scripts on the deployment", func() {
 			Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
-			afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
+			_, afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
 			Expect(afterSuccessfulBackup).To(BeTrue())
 		})
 
@@ -116,7 +142,7 @@ var _ = Describe("Backup", func() {
 		It("drains the backup to the artifact", func() {
 			Expect(artifactCopier.DownloadBackupFromDeploymentCallCount()).To(Equal(1))
 
-			downloadedBackup, downloadedFromDeployment := artifactCopier.DownloadBackupFromDeploymentArgsForCall(0)
+			_, downloadedBackup, downloadedFromDeployment := artifactCopier.DownloadBackupFromDeploymentArgsForCall(0)
 			Expect(downloadedBackup).To(Equal(fakeBackup))
 			Expect(downloadedFromDeployment).To(Equal(deployment))
 		})
@@ -125,6 +151,33 @@ var _ = Describe("Backup", func() {
 			Expect(fakeBackup.CreateMetadataFileWithStartTimeArgsForCall(0)).To(Equal(startTime))
 			Expect(fakeBackup.AddFinishTimeArgsForCall(0)).To(Equal(finishTime))
 		})
+
+		Context("when scripts have written output", func() {
+			BeforeEach(func() {
+				deployment.BackupStub = func(context.Context, executor.Executor) error {
+					scriptLogs.Add("redis/0", "redis-server", "backup", []byte("dumped"))
+					return nil
+				}
+			})
+
+			It("saves the output into the backup", func() {
+				Expect(fakeBackup.AddLogCallCount()).To(Equal(1))
+				name, contents := fakeBackup.AddLogArgsForCall(0)
+				Expect(name).To(Equal("redis-0-redis-server-backup"))
+				Expect(contents).To(Equal([]byte("dumped")))
+			})
+
+			Context("and the output cannot be saved", func() {
+				BeforeEach(func() {
+					fakeBackup.AddLogReturns(goerr.New("disk full"))
+				})
+
+				It("warns, but does not fail", func() {
+					Expect(actualBackupError).NotTo(HaveOccurred())
+					Expect(logger.WarnCallCount()).To(Equal(1))
+				})
+			})
+		})
 	})
 
 	Describe("failures", func() {
@@ -216,6 +269,31 @@ var _ = Describe("Backup", func() {
 			})
 		})
 
+		Context("fails if a pre-backup-check script fails", func() {
+			BeforeEach(func() {
+				fakeBackupManager.CreateReturns(fakeBackup, nil)
+				deploymentManager.FindReturns(deployment, nil)
+				deployment.IsBackupableReturns(true)
+				deployment.HasUniqueCustomArtifactNamesReturns(true)
+				deployment.PreBackupCheckReturns(fmt.Errorf("replication is lagging - exit code 1"))
+			})
+
+			It("fails the backup process", func() {
+				Expect(actualBackupError).To(ConsistOf(
+					MatchError("Deployment '" + deploymentName + "' cannot be backed up: replication is lagging - exit code 1"),
+				))
+			})
+
+			It("does not create an artifact or lock the deployment", func() {
+				Expect(fakeBackupManager.CreateCallCount()).To(BeZero())
+				Expect(deployment.PreBackupLockCallCount()).To(BeZero())
+			})
+
+			It("ensures that deployment is cleaned up", func() {
+				Expect(deployment.CleanupCallCount()).To(Equal(1))
+			})
+		})
+
 		Context("fails if pre-backup-lock fails", func() {
 			var lockError = orchestrator.NewLockError("smoooooooth jazz")
 
@@ -235,13 +313,84 @@ var _ = Describe("Backup", func() {
 
 			It("also runs post-backup-unlock", func() {
 				Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
-				afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
+				_, afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
 				Expect(afterSuccessfulBackup).To(BeFalse())
 			})
 
 			Context("cleanup fails as well", assertCleanupError)
 		})
 
+		Context("fails if a pre-backup-lock script times out", func() {
+			BeforeEach(func() {
+				fakeBackupManager.CreateReturns(fakeBackup, nil)
+				deploymentManager.FindReturns(deployment, nil)
+				deployment.IsBackupableReturns(true)
+				deployment.HasUniqueCustomArtifactNamesReturns(true)
+				deployment.CleanupReturns(nil)
+
+				deployment.PreBackupLockReturns(orchestrator.NewError(
+					goerr.Wrap(ssh.ScriptTimeoutError{Label: "pre-backup lock redis on redis/0", After: time.Minute}, "Error attempting to run pre-backup-lock"),
+				))
+			})
+
+			It("fails with a lock error marked as a timeout", func() {
+				Expect(actualBackupError).To(ConsistOf(BeAssignableToTypeOf(orchestrator.TimeoutError{})))
+				Expect(actualBackupError).To(MatchError(ContainSubstring("pre-backup lock redis on redis/0 timed out after 1m0s")))
+				Expect(orchestrator.BuildExitCode(actualBackupError.(orchestrator.Error))).To(Equal(1<<6 | 1<<2))
+			})
+
+			It("still runs post-backup-unlock", func() {
+				Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when jobs verify their backups", func() {
+			var results []orchestrator.VerifyResult
+
+			BeforeEach(func() {
+				fakeBackupManager.CreateReturns(fakeBackup, nil)
+				deploymentManager.FindReturns(deployment, nil)
+				deployment.IsBackupableReturns(true)
+				deployment.HasUniqueCustomArtifactNamesReturns(true)
+				results = []orchestrator.VerifyResult{{InstanceIdentifier: "redis/0", JobName: "redis-server"}}
+				deployment.PostBackupVerifyReturns(results, nil)
+			})
+
+			It("records the results in the backup", func() {
+				Expect(actualBackupError).NotTo(HaveOccurred())
+				Expect(fakeBackup.AddVerifyResultsCallCount()).To(Equal(1))
+				Expect(fakeBackup.AddVerifyResultsArgsForCall(0)).To(Equal(results))
+			})
+
+			Context("and a verify script fails", func() {
+				BeforeEach(func() {
+					results = []orchestrator.VerifyResult{{InstanceIdentifier: "redis/0", JobName: "redis-server", Error: fmt.Errorf("dump is truncated")}}
+					deployment.PostBackupVerifyReturns(results, fmt.Errorf("dump is truncated"))
+				})
+
+				It("fails the backup with a verify error", func() {
+					Expect(actualBackupError).To(ConsistOf(And(
+						MatchError("dump is truncated"),
+						BeAssignableToTypeOf(orchestrator.PostBackupVerifyError{}),
+					)))
+				})
+
+				It("still records the results", func() {
+					Expect(fakeBackup.AddVerifyResultsArgsForCall(0)).To(Equal(results))
+				})
+
+				It("unlocks the deployment, telling it the backup failed", func() {
+					Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
+					_, afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
+					Expect(afterSuccessfulBackup).To(BeFalse())
+				})
+
+				It("does not drain the backup", func() {
+					Expect(artifactCopier.DownloadBackupFromDeploymentCallCount()).To(BeZero())
+				})
+			})
+		})
+
 		Context("fails if post-backup-unlock fails", func() {
 			var unlockError orchestrator.UnlockError
 
@@ -334,6 +483,27 @@ var _ = Describe("Backup", func() {
 				Expect(deployment.CleanupCallCount()).To(Equal(1))
 			})
 
+			Context("when the instances have artifact directories", func() {
+				var instance *fakes.FakeInstance
+
+				BeforeEach(func() {
+					instance = new(fakes.FakeInstance)
+					instance.ArtifactDirCreatedReturns(true)
+					deployment.InstancesReturns([]orchestrator.Instance{instance})
+				})
+
+				It("keeps the artifact directories so that the backup can be resumed", func() {
+					Expect(instance.KeepArtifactDirCallCount()).To(Equal(1))
+					Expect(deployment.CleanupCallCount()).To(Equal(1))
+				})
+
+				It("tells the operator how to resume the backup", func() {
+					Expect(logger.WarnCallCount()).To(Equal(1))
+					_, msg, args := logger.WarnArgsForCall(0)
+					Expect(fmt.Sprintf(msg, args...)).To(ContainSubstring("Run `bbr deployment backup --resume` to drain the rest of it."))
+				})
+			})
+
 			Context("cleanup fails as well", assertCleanupError)
 		})
 
@@ -430,7 +600,7 @@ var _ = Describe("Backup", func() {
 
 			It("runs post-backup-unlock scripts on the deployment", func() {
 				Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
-				afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
+				_, afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
 				Expect(afterSuccessfulBackup).To(BeFalse())
 			})
 
@@ -471,6 +641,106 @@ var _ = Describe("Backup", func() {
 	})
 })
 
+var _ = Describe("Resume", func() {
+	var (
+		b                 *orchestrator.Backuper
+		deployment        *fakes.FakeDeployment
+		deploymentManager *fakes.FakeDeploymentManager
+		fakeBackup        *fakes.FakeBackup
+		fakeBackupManager *fakes.FakeBackupManager
+		logger            *fakes.FakeLogger
+		artifactCopier    *fakes.FakeArtifactCopier
+		finishTime        time.Time
+		resumeError       error
+		deploymentName    = "foobarbaz"
+		backupPath        = "foobarbaz_20151021T010203Z"
+	)
+
+	BeforeEach(func() {
+		deployment = new(fakes.FakeDeployment)
+		deploymentManager = new(fakes.FakeDeploymentManager)
+		fakeBackupManager = new(fakes.FakeBackupManager)
+		fakeBackup = new(fakes.FakeBackup)
+		logger = new(fakes.FakeLogger)
+		artifactCopier = new(fakes.FakeArtifactCopier)
+		finishTime = time.Now()
+
+		deploymentManager.FindReturns(deployment, nil)
+		fakeBackupManager.OpenReturns(fakeBackup, nil)
+		fakeBackup.DeploymentMatchesReturns(true, nil)
+
+		b = orchestrator.NewBackuper(fakeBackupManager, logger, deploymentManager, new(fakes.FakeLockOrderer), executor.NewParallelExecutor(), executor.NewParallelExecutor(), func() time.Time { return finishTime }, artifactCopier, "", nil, nil, nil, nil, 0, "")
+	})
+
+	JustBeforeEach(func() {
+		resumeError = b.Resume(context.Background(), deploymentName, backupPath)
+	})
+
+	It("drains the missing artifacts into the existing backup", func() {
+		Expect(resumeError).NotTo(HaveOccurred())
+
+		actualPath, _ := fakeBackupManager.OpenArgsForCall(0)
+		Expect(actualPath).To(Equal(backupPath))
+		Expect(fakeBackupManager.CreateCallCount()).To(Equal(0))
+
+		Expect(artifactCopier.DownloadMissingBackupFromDeploymentCallCount()).To(Equal(1))
+		_, actualBackup, actualDeployment := artifactCopier.DownloadMissingBackupFromDeploymentArgsForCall(0)
+		Expect(actualBackup).To(Equal(fakeBackup))
+		Expect(actualDeployment).To(Equal(deployment))
+		Expect(artifactCopier.DownloadBackupFromDeploymentCallCount()).To(Equal(0))
+	})
+
+	It("does not run any scripts", func() {
+		Expect(deployment.PreBackupLockCallCount()).To(Equal(0))
+		Expect(deployment.BackupCallCount()).To(Equal(0))
+		Expect(deployment.PostBackupUnlockCallCount()).To(Equal(0))
+	})
+
+	It("cleans up and records the finish time", func() {
+		Expect(deployment.CleanupCallCount()).To(Equal(1))
+		Expect(fakeBackup.AddFinishTimeCallCount()).To(Equal(1))
+		Expect(fakeBackup.AddFinishTimeArgsForCall(0)).To(Equal(finishTime))
+	})
+
+	Context("when the backup cannot be opened", func() {
+		BeforeEach(func() {
+			fakeBackupManager.OpenReturns(nil, fmt.Errorf("no such backup"))
+		})
+
+		It("fails without draining", func() {
+			Expect(resumeError).To(MatchError(ContainSubstring("no such backup")))
+			Expect(artifactCopier.DownloadMissingBackupFromDeploymentCallCount()).To(Equal(0))
+			Expect(deployment.CleanupCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the backup belongs to a different deployment", func() {
+		BeforeEach(func() {
+			fakeBackup.DeploymentMatchesReturns(false, nil)
+		})
+
+		It("fails without draining", func() {
+			Expect(resumeError).To(MatchError(ContainSubstring("does not match deployment foobarbaz")))
+			Expect(artifactCopier.DownloadMissingBackupFromDeploymentCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when draining fails", func() {
+		BeforeEach(func() {
+			artifactCopier.DownloadMissingBackupFromDeploymentReturns(fmt.Errorf("ssh dropped"))
+		})
+
+		It("returns the error and still cleans up", func() {
+			Expect(resumeError).To(MatchError(ContainSubstring("ssh dropped")))
+			Expect(deployment.CleanupCallCount()).To(Equal(1))
+		})
+
+		It("does not record a finish time, as the backup is still incomplete", func() {
+			Expect(fakeBackup.AddFinishTimeCallCount()).To(Equal(0))
+		})
+	})
+})
+
 func expectErrorMatch(actual error, expected ...error) {
 	if actualErrors, isErrorList := actual.(orchestrator.Error); isErrorList {
 		for _, err := range actualErrors {
@@ -481,3 +751,110 @@ func expectErrorMatch(actual error, expected ...error) {
 		Expect(actual).To(MatchError(expected))
 	}
 }
+
+var _ = Describe("Backup journal", func() {
+	var (
+		journalDir          string
+		journals            *orchestrator.Journals
+		deployment          *fakes.FakeDeployment
+		deploymentManager   *fakes.FakeDeploymentManager
+		instance            *fakes.FakeInstance
+		job                 *fakes.FakeJob
+		jobNotLocked        *fakes.FakeJob
+		journalDuringBackup orchestrator.JournalEntries
+		backupErr           orchestrator.Error
+	)
+
+	BeforeEach(func() {
+		var err error
+		journalDir, err = ioutil.TempDir("", "journal")
+		Expect(err).NotTo(HaveOccurred())
+		journals = orchestrator.NewJournals(journalDir)
+
+		job = new(fakes.FakeJob)
+		job.NameReturns("redis-server")
+		job.InstanceIdentifierReturns("redis/0")
+		jobNotLocked = new(fakes.FakeJob)
+		jobNotLocked.NameReturns("redis-writer")
+		jobNotLocked.InstanceIdentifierReturns("redis/0")
+		instance = new(fakes.FakeInstance)
+		instance.NameReturns("redis")
+		instance.IDReturns("0")
+		instance.SSHUserReturns("bbr-123")
+		instance.JobsReturns([]orchestrator.Job{job, jobNotLocked})
+		instance.ArtifactDirCreatedReturns(true)
+
+		deployment = new(fakes.FakeDeployment)
+		deployment.IsBackupableReturns(true)
+		deployment.HasUniqueCustomArtifactNamesReturns(true)
+		deployment.InstancesReturns([]orchestrator.Instance{instance})
+		deployment.BackupableInstancesReturns([]orchestrator.Instance{instance})
+		deployment.BackupStub = func(context.Context, executor.Executor) error {
+			journal, err := journals.Read("redis")
+			Expect(err).NotTo(HaveOccurred())
+			journalDuringBackup = journal.Entries()
+			return nil
+		}
+		deployment.LockedJobsStub = func() []orchestrator.Job {
+			if deployment.PostBackupUnlockCallCount() > 0 {
+				return nil
+			}
+			return []orchestrator.Job{job}
+		}
+		deploymentManager = new(fakes.FakeDeploymentManager)
+		deploymentManager.FindReturns(deployment, nil)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(journalDir)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		backupManager := new(fakes.FakeBackupManager)
+		backupManager.CreateReturns(new(fakes.FakeBackup), nil)
+		b := orchestrator.NewBackuper(backupManager, new(fakes.FakeLogger), deploymentManager, new(fakes.FakeLockOrderer),
+			executor.NewParallelExecutor(), executor.NewParallelExecutor(), time.Now, new(fakes.FakeArtifactCopier), "", nil, nil, journals, nil, 0, "")
+		backupErr = b.Backup(context.Background(), "redis", journalDir)
+	})
+
+	It("records the SSH users, the jobs which were locked and artifact directories while backing up", func() {
+		Expect(journalDuringBackup).To(Equal(orchestrator.JournalEntries{
+			Operation:           "backup",
+			SSHUsers:            []orchestrator.JournalSSHUser{{Instance: "redis/0", Username: "bbr-123"}},
+			LockedJobs:          []orchestrator.JournalJob{{Instance: "redis/0", Job: "redis-server"}},
+			ArtifactDirectories: []string{"redis/0"},
+		}))
+	})
+
+	It("removes the journal once everything has been undone", func() {
+		Expect(backupErr).NotTo(HaveOccurred())
+		Expect(filepath.Join(journalDir, "redis.bbr-journal")).NotTo(BeAnExistingFile())
+	})
+
+	Context("when the cleanup fails", func() {
+		BeforeEach(func() {
+			deployment.CleanupReturns(fmt.Errorf("cleanup error"))
+		})
+
+		It("keeps what was not undone in the journal", func() {
+			journal, err := journals.Read("redis")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(journal.Entries().LockedJobs).To(BeEmpty())
+			Expect(journal.Entries().SSHUsers).To(HaveLen(1))
+			Expect(journal.Entries().ArtifactDirectories).To(HaveLen(1))
+		})
+	})
+
+	Context("when a previous backup left outstanding entries in the journal", func() {
+		BeforeEach(func() {
+			journal, err := journals.Start("redis", "backup")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(journal.AddLockedJobs([]orchestrator.Job{job})).To(Succeed())
+		})
+
+		It("fails before finding the deployment, advising a cleanup", func() {
+			Expect(backupErr.ContainsArtifactDirError()).To(BeTrue())
+			Expect(deploymentManager.FindCallCount()).To(BeZero())
+		})
+	})
+})
@@ -1,11 +1,41 @@
 package orchestrator
 
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
 type CleanupPreviousStep struct{}
 
 func NewCleanupPreviousStep() Step {
 	return &CleanupPreviousStep{}
 }
 
-func (s *CleanupPreviousStep) Run(session *Session) error {
-	return session.CurrentDeployment().CleanupPrevious()
+// Run removes the artifact directory from every instance, unless there is a journal of the
+// interrupted backup or restore. Then only the artifact directories it lists have been marked
+// as created, and the SSH users it lists are removed alongside the ones set up for the cleanup.
+func (s *CleanupPreviousStep) Run(ctx context.Context, session *Session) error {
+	deployment := session.CurrentDeployment()
+	if session.Journal() == nil {
+		return deployment.CleanupPrevious()
+	}
+
+	var errs []error
+	if err := deployment.Cleanup(); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, user := range session.Journal().Entries().SSHUsers {
+		instance := findInstance(deployment.Instances(), user.Instance)
+		if instance == nil || instance.SSHUser() == user.Username {
+			continue
+		}
+
+		if err := instance.RemoveSSHUser(user.Username); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to remove SSH user %s on %s", user.Username, user.Instance))
+		}
+	}
+
+	return ConvertErrors(errs)
 }
@@ -1,6 +1,10 @@
 package orchestrator
 
-import "fmt"
+import (
+	"context"
+
+	"fmt"
+)
 
 type CleanupStep struct{}
 
@@ -8,11 +12,30 @@ func NewCleanupStep() Step {
 	return &CleanupStep{}
 }
 
-func (s *CleanupStep) Run(session *Session) error {
+func (s *CleanupStep) Run(ctx context.Context, session *Session) error {
+	deployment := session.CurrentDeployment()
 
-	if err := session.CurrentDeployment().Cleanup(); err != nil {
+	if err := deployment.Cleanup(); err != nil {
 		return NewCleanupError(
 			fmt.Sprintf("Deployment '%s' failed while cleaning up with error: %v", session.DeploymentName(), err))
 	}
+
+	var cleanedUpArtifactDirs []Instance
+	for _, instance := range deployment.Instances() {
+		if instance.ArtifactDirCreated() {
+			cleanedUpArtifactDirs = append(cleanedUpArtifactDirs, instance)
+		}
+	}
+
+	err := session.Journal().RemoveArtifactDirectories(cleanedUpArtifactDirs)
+	if err == nil {
+		err = session.Journal().RemoveSSHUsers(deployment.Instances())
+	}
+	if err != nil {
+		return NewCleanupError(
+			fmt.Sprintf("Deployment '%s' was cleaned up, but its journal could not be updated: %v", session.DeploymentName(), err))
+	}
 	return nil
 }
+
+func (s *CleanupStep) cleansUp() {}
@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -11,9 +12,10 @@ type CreateArtifactStep struct {
 	deploymentManager DeploymentManager
 	nowFunc           func() time.Time
 	timeStamp         string
+	scriptEnvironment *ScriptEnvironment
 }
 
-func (s *CreateArtifactStep) Run(session *Session) error {
+func (s *CreateArtifactStep) Run(ctx context.Context, session *Session) error {
 	s.logger.Info("bbr", "Starting backup of %s...\n", session.DeploymentName())
 
 	directoryName := fmt.Sprintf("%s_%s", session.DeploymentName(), s.timeStamp)
@@ -23,6 +25,7 @@ func (s *CreateArtifactStep) Run(session *Session) error {
 	}
 	artifact.CreateMetadataFileWithStartTime(s.nowFunc())
 	session.SetCurrentArtifact(artifact)
+	s.scriptEnvironment.SetBackup(directoryName, s.timeStamp)
 
 	err = s.deploymentManager.SaveManifest(session.DeploymentName(), artifact)
 	if err != nil {
@@ -31,6 +34,6 @@ func (s *CreateArtifactStep) Run(session *Session) error {
 	return nil
 }
 
-func NewCreateArtifactStep(logger Logger, backupManager BackupManager, deploymentManager DeploymentManager, nowFunc func() time.Time, timeStamp string) Step {
-	return &CreateArtifactStep{logger: logger, backupManager: backupManager, deploymentManager: deploymentManager, nowFunc: nowFunc, timeStamp: timeStamp}
+func NewCreateArtifactStep(logger Logger, backupManager BackupManager, deploymentManager DeploymentManager, nowFunc func() time.Time, timeStamp string, scriptEnvironment *ScriptEnvironment) Step {
+	return &CreateArtifactStep{logger: logger, backupManager: backupManager, deploymentManager: deploymentManager, nowFunc: nowFunc, timeStamp: timeStamp, scriptEnvironment: scriptEnvironment}
 }
@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"context"
 	"fmt"
 
 	"strings"
@@ -13,23 +14,27 @@ const ArtifactDirectory = "/var/vcap/store/bbr-backup"
 
 //go:generate counterfeiter -o fakes/fake_deployment.go . Deployment
 type Deployment interface {
+	Name() string
 	IsBackupable() bool
 	BackupableInstances() []Instance
 	HasUniqueCustomArtifactNames() bool
 	CheckArtifactDir() error
 	IsRestorable() bool
 	RestorableInstances() []Instance
-	PreBackupLock(LockOrderer, executor.Executor) error
-	Backup(executor.Executor) error
-	PostBackupUnlock(bool, LockOrderer, executor.Executor) error
-	Restore() error
+	PreBackupCheck(context.Context, executor.Executor) error
+	PreBackupLock(context.Context, LockOrderer, executor.Executor) error
+	Backup(context.Context, executor.Executor) error
+	PostBackupVerify(context.Context, executor.Executor) ([]VerifyResult, error)
+	PostBackupUnlock(context.Context, bool, LockOrderer, executor.Executor) error
+	Restore(context.Context) error
 	Cleanup() error
 	CleanupPrevious() error
 	Instances() []Instance
 	CustomArtifactNamesMatch() error
-	PreRestoreLock(LockOrderer, executor.Executor) error
-	PostRestoreUnlock(LockOrderer, executor.Executor) error
+	PreRestoreLock(context.Context, LockOrderer, executor.Executor) error
+	PostRestoreUnlock(context.Context, LockOrderer, executor.Executor) error
 	ValidateLockingDependencies(orderer LockOrderer) error
+	LockedJobs() []Job
 }
 
 //go:generate counterfeiter -o fakes/fake_lock_orderer.go . LockOrderer
@@ -39,11 +44,17 @@ type LockOrderer interface {
 
 type deployment struct {
 	Logger
+	name      string
 	instances instances
+	locks     *jobLocks
 }
 
-func NewDeployment(logger Logger, instancesArray []Instance) Deployment {
-	return &deployment{Logger: logger, instances: instances(instancesArray)}
+func NewDeployment(name string, logger Logger, instancesArray []Instance) Deployment {
+	return &deployment{Logger: logger, name: name, instances: instances(instancesArray), locks: &jobLocks{}}
+}
+
+func (bd *deployment) Name() string {
+	return bd.name
 }
 
 func (bd *deployment) IsBackupable() bool {
@@ -93,7 +104,19 @@ func (bd *deployment) ValidateLockingDependencies(lockOrderer LockOrderer) error
 	return err
 }
 
-func (bd *deployment) PreBackupLock(lockOrderer LockOrderer, executor executor.Executor) error {
+// PreBackupCheck runs the pre-backup-check scripts of every job, all at once, as checks do
+// not change the state of the jobs.
+func (bd *deployment) PreBackupCheck(ctx context.Context, executor executor.Executor) error {
+	bd.Logger.Info("bbr", "Running pre-backup-check scripts...")
+
+	jobs := bd.instances.Jobs()
+	preBackupCheckErrors := executor.Run(ctx, newJobExecutables([][]Job{jobs}, bd.name, NewJobPreBackupCheckExecutable))
+
+	bd.Logger.Info("bbr", "Finished running pre-backup-check scripts.")
+	return ConvertErrors(preBackupCheckErrors)
+}
+
+func (bd *deployment) PreBackupLock(ctx context.Context, lockOrderer LockOrderer, executor executor.Executor) error {
 	bd.Logger.Info("bbr", "Running pre-backup-lock scripts...")
 
 	jobs := bd.instances.Jobs()
@@ -103,13 +126,13 @@ func (bd *deployment) PreBackupLock(lockOrderer LockOrderer, executor executor.E
 		return err
 	}
 
-	preBackupLockErrors := executor.Run(newJobExecutables(orderedJobs, NewJobPreBackupLockExecutable))
+	preBackupLockErr := bd.lock(ctx, orderedJobs, executor, NewJobPreBackupLockExecutable)
 
 	bd.Logger.Info("bbr", "Finished running pre-backup-lock scripts.")
-	return ConvertErrors(preBackupLockErrors)
+	return preBackupLockErr
 }
 
-func (bd *deployment) Backup(exe executor.Executor) error {
+func (bd *deployment) Backup(ctx context.Context, exe executor.Executor) error {
 	bd.Logger.Info("bbr", "Running backup scripts...")
 
 	instances := bd.instances.AllBackupable()
@@ -118,17 +141,44 @@ func (bd *deployment) Backup(exe executor.Executor) error {
 	for _, i := range instances {
 		i.MarkArtifactDirCreated()
 		for _, j := range i.Jobs() {
-			executables = append(executables, NewBackupExecutable(j))
+			executables = append(executables, NewBackupExecutable(j, bd.name))
 		}
 	}
 
-	backupErr := exe.Run([][]executor.Executable{executables})
+	backupErr := exe.Run(ctx, [][]executor.Executable{executables})
 
 	bd.Logger.Info("bbr", "Finished running backup scripts.")
 	return ConvertErrors(backupErr)
 }
 
-func (bd *deployment) PostBackupUnlock(afterSuccessfulBackup bool, lockOrderer LockOrderer, executor executor.Executor) error {
+// PostBackupVerify runs the post-backup-verify scripts of the jobs which have been backed
+// up, returning the result of each script alongside any errors.
+func (bd *deployment) PostBackupVerify(ctx context.Context, exe executor.Executor) ([]VerifyResult, error) {
+	var jobs []Job
+	for _, job := range bd.instances.AllBackupable().Jobs() {
+		if job.HasPostBackupVerify() {
+			jobs = append(jobs, job)
+		}
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	bd.Logger.Info("bbr", "Running post-backup-verify scripts...")
+
+	results := make([]VerifyResult, len(jobs))
+	var executables []executor.Executable
+	for i, job := range jobs {
+		executables = append(executables, NewJobPostBackupVerifyExecutable(job, bd.name, &results[i]))
+	}
+
+	verifyErrors := exe.Run(ctx, [][]executor.Executable{executables})
+
+	bd.Logger.Info("bbr", "Finished running post-backup-verify scripts.")
+	return results, ConvertErrors(verifyErrors)
+}
+
+func (bd *deployment) PostBackupUnlock(ctx context.Context, afterSuccessfulBackup bool, lockOrderer LockOrderer, executor executor.Executor) error {
 	bd.Logger.Info("bbr", "Running post-backup-unlock scripts...")
 
 	jobs := bd.instances.Jobs()
@@ -137,20 +187,19 @@ func (bd *deployment) PostBackupUnlock(afterSuccessfulBackup bool, lockOrderer L
 	if err != nil {
 		return err
 	}
-	reversedJobs := Reverse(orderedJobs)
 
 	executableJobConstructor := NewJobPostFailedBackupUnlockExecutable
 	if afterSuccessfulBackup {
 		executableJobConstructor = NewJobPostSuccessfulBackupUnlockExecutable
 
 	}
-	postBackupUnlockErrors := executor.Run(newJobExecutables(reversedJobs, executableJobConstructor))
+	postBackupUnlockErr := bd.unlock(ctx, orderedJobs, executor, executableJobConstructor)
 
 	bd.Logger.Info("bbr", "Finished running post-backup-unlock scripts.")
-	return ConvertErrors(postBackupUnlockErrors)
+	return postBackupUnlockErr
 }
 
-func (bd *deployment) PreRestoreLock(lockOrderer LockOrderer, executor executor.Executor) error {
+func (bd *deployment) PreRestoreLock(ctx context.Context, lockOrderer LockOrderer, executor executor.Executor) error {
 	bd.Logger.Info("bbr", "Running pre-restore-lock scripts...")
 
 	jobs := bd.instances.Jobs()
@@ -160,20 +209,20 @@ func (bd *deployment) PreRestoreLock(lockOrderer LockOrderer, executor executor.
 		return err
 	}
 
-	preRestoreLockErrors := executor.Run(newJobExecutables(orderedJobs, NewJobPreRestoreLockExecutable))
+	preRestoreLockErr := bd.lock(ctx, orderedJobs, executor, NewJobPreRestoreLockExecutable)
 
 	bd.Logger.Info("bbr", "Finished running pre-restore-lock scripts.")
-	return ConvertErrors(preRestoreLockErrors)
+	return preRestoreLockErr
 }
 
-func (bd *deployment) Restore() error {
+func (bd *deployment) Restore(ctx context.Context) error {
 	bd.Logger.Info("bbr", "Running restore scripts...")
-	err := bd.instances.AllRestoreable().Restore()
+	err := bd.instances.AllRestoreable().Restore(ctx, bd.name)
 	bd.Logger.Info("bbr", "Finished running restore scripts.")
 	return err
 }
 
-func (bd *deployment) PostRestoreUnlock(lockOrderer LockOrderer, executor executor.Executor) error {
+func (bd *deployment) PostRestoreUnlock(ctx context.Context, lockOrderer LockOrderer, executor executor.Executor) error {
 	bd.Logger.Info("bbr", "Running post-restore-unlock scripts...")
 
 	jobs := bd.instances.Jobs()
@@ -182,20 +231,64 @@ func (bd *deployment) PostRestoreUnlock(lockOrderer LockOrderer, executor execut
 	if err != nil {
 		return err
 	}
-	reversedJobs := Reverse(orderedJobs)
 
-	postRestoreUnlockErrors := executor.Run(newJobExecutables(reversedJobs, NewJobPostRestoreUnlockExecutable))
+	postRestoreUnlockErr := bd.unlock(ctx, orderedJobs, executor, NewJobPostRestoreUnlockExecutable)
 
 	bd.Logger.Info("bbr", "Finished running post-restore-unlock scripts.")
-	return ConvertErrors(postRestoreUnlockErrors)
+	return postRestoreUnlockErr
+}
+
+// lock runs one ordered batch of jobs at a time, and stops once any job of a batch fails to
+// lock, so that no job is locked before the jobs it depends on
+func (bd *deployment) lock(ctx context.Context, orderedJobs [][]Job, exe executor.Executor, newJobExecutable func(Job, string) executor.Executable) error {
+	bd.locks.startLocking(orderedJobs)
+
+	for i, jobs := range orderedJobs {
+		lockErrors := exe.Run(ctx, [][]executor.Executable{
+			newTrackedLockExecutables(jobs, bd.name, newJobExecutable, bd.locks.recordLock),
+		})
+		if len(lockErrors) > 0 {
+			if i < len(orderedJobs)-1 {
+				bd.Logger.Warn("bbr", "Not locking the remaining jobs, as a job failed to lock")
+			}
+			return ConvertErrors(lockErrors)
+		}
+	}
+	return nil
+}
+
+// unlock runs, in the reverse of the lock order, only for the jobs which might be locked
+func (bd *deployment) unlock(ctx context.Context, orderedJobs [][]Job, exe executor.Executor, newJobExecutable func(Job, string) executor.Executable) error {
+	var executables [][]executor.Executable
+	for _, jobs := range Reverse(bd.locks.onlyLocked(orderedJobs)) {
+		executables = append(executables, newTrackedLockExecutables(jobs, bd.name, newJobExecutable, bd.locks.recordUnlock))
+	}
+
+	unlockErrors := exe.Run(ctx, executables)
+
+	if bd.locks.locking {
+		bd.Logger.Info("bbr", "Lock state of the jobs of %s:\n%s", bd.name, bd.locks.describe())
+	}
+	return ConvertErrors(unlockErrors)
+}
+
+// LockedJobs returns the jobs which are still locked, or might be
+func (bd *deployment) LockedJobs() []Job {
+	var lockedJobs []Job
+	for _, job := range bd.instances.Jobs() {
+		if bd.locks.isLocked(job) {
+			lockedJobs = append(lockedJobs, job)
+		}
+	}
+	return lockedJobs
 }
 
-func newJobExecutables(jobsList [][]Job, newJobExecutable func(Job) executor.Executable) [][]executor.Executable {
+func newJobExecutables(jobsList [][]Job, deploymentName string, newJobExecutable func(Job, string) executor.Executable) [][]executor.Executable {
 	var executablesList [][]executor.Executable
 	for _, jobs := range jobsList {
 		var executables []executor.Executable
 		for _, job := range jobs {
-			executables = append(executables, newJobExecutable(job))
+			executables = append(executables, newJobExecutable(job, deploymentName))
 		}
 		executablesList = append(executablesList, executables)
 	}
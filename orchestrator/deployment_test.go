@@ -1,6 +1,7 @@
 package orchestrator_test
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
@@ -39,13 +40,54 @@ var _ = Describe("Deployment", func() {
 		job2a = new(fakes.FakeJob)
 		job3a = new(fakes.FakeJob)
 
+		for name, job := range map[string]*fakes.FakeJob{"job1a": job1a, "job1b": job1b, "job2a": job2a, "job3a": job3a} {
+			job.NameReturns(name)
+			job.InstanceIdentifierReturns("instance" + name[3:4] + "/0")
+		}
+
 		instance1.JobsReturns([]orchestrator.Job{job1a, job1b})
 		instance2.JobsReturns([]orchestrator.Job{job2a})
 		instance3.JobsReturns([]orchestrator.Job{job3a})
 	})
 
 	JustBeforeEach(func() {
-		deployment = orchestrator.NewDeployment(logger, instances)
+		deployment = orchestrator.NewDeployment("my-deployment", logger, instances)
+	})
+
+	Context("PreBackupCheck", func() {
+		var (
+			checkError   error
+			fakeExecutor *executorFakes.FakeExecutor
+		)
+
+		BeforeEach(func() {
+			fakeExecutor = new(executorFakes.FakeExecutor)
+			instances = []orchestrator.Instance{instance1, instance2}
+		})
+
+		JustBeforeEach(func() {
+			checkError = deployment.PreBackupCheck(context.Background(), fakeExecutor)
+		})
+
+		It("checks every job at once", func() {
+			Expect(checkError).NotTo(HaveOccurred())
+			_, batches := fakeExecutor.RunArgsForCall(0)
+			Expect(batches).To(Equal([][]executor.Executable{{
+				orchestrator.NewJobPreBackupCheckExecutable(job1a, "my-deployment"),
+				orchestrator.NewJobPreBackupCheckExecutable(job1b, "my-deployment"),
+				orchestrator.NewJobPreBackupCheckExecutable(job2a, "my-deployment"),
+			}}))
+		})
+
+		Context("if a pre-backup-check fails", func() {
+			BeforeEach(func() {
+				fakeExecutor.RunReturns([]error{fmt.Errorf("job1b is not healthy")})
+			})
+
+			It("fails", func() {
+				Expect(checkError).To(MatchError(ContainSubstring("job1b is not healthy")))
+			})
+		})
 	})
 
 	Context("PreBackupLock", func() {
@@ -53,43 +95,61 @@ var _ = Describe("Deployment", func() {
 			lockError    error
 			lockOrderer  *fakes.FakeLockOrderer
 			fakeExecutor *executorFakes.FakeExecutor
+			lockedOrder  []string
 		)
 
 		BeforeEach(func() {
 			lockOrderer = new(fakes.FakeLockOrderer)
 			fakeExecutor = new(executorFakes.FakeExecutor)
+			fakeExecutor.RunStub = executor.NewSerialExecutor().Run
 			instances = []orchestrator.Instance{instance1, instance2, instance3}
 			lockOrderer.OrderReturns([][]orchestrator.Job{{job2a}, {job3a, job1a}, {job1b}}, nil)
+
+			lockedOrder = nil
+			for _, job := range []*fakes.FakeJob{job1a, job1b, job2a, job3a} {
+				job := job
+				job.PreBackupLockStub = func(context.Context) error {
+					lockedOrder = append(lockedOrder, job.Name())
+					return nil
+				}
+			}
 		})
 
 		JustBeforeEach(func() {
-			lockError = deployment.PreBackupLock(lockOrderer, fakeExecutor)
+			lockError = deployment.PreBackupLock(context.Background(), lockOrderer, fakeExecutor)
 		})
 
-		It("delegates the execution to the executor", func() {
+		It("locks one ordered batch of jobs at a time", func() {
 			Expect(lockError).NotTo(HaveOccurred())
 			Expect(lockOrderer.OrderArgsForCall(0)).To(ConsistOf(job1a, job1b, job2a, job3a))
-			Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{
-				{orchestrator.NewJobPreBackupLockExecutable(job2a)},
-				{orchestrator.NewJobPreBackupLockExecutable(job3a), orchestrator.NewJobPreBackupLockExecutable(job1a)},
-				{orchestrator.NewJobPreBackupLockExecutable(job1b)},
-			}))
+			Expect(fakeExecutor.RunCallCount()).To(Equal(3))
+			Expect(lockedOrder).To(Equal([]string{"job2a", "job3a", "job1a", "job1b"}))
+			Expect(deployment.LockedJobs()).To(ConsistOf(job1a, job1b, job2a, job3a))
 		})
 
 		Context("if the pre-backup-lock fails", func() {
 			BeforeEach(func() {
-				fakeExecutor.RunReturns([]error{
-					fmt.Errorf("job1b failed"),
-					fmt.Errorf("job2a failed"),
-				})
+				job3a.PreBackupLockReturns(fmt.Errorf("job3a failed"))
+				job1a.PreBackupLockStub = nil
+				job1a.PreBackupLockReturns(fmt.Errorf("job1a failed"))
 			})
 
 			It("fails", func() {
 				Expect(lockError).To(MatchError(SatisfyAll(
-					ContainSubstring("job1b failed"),
-					ContainSubstring("job2a failed"),
+					ContainSubstring("job3a failed"),
+					ContainSubstring("job1a failed"),
 				)))
 			})
+
+			It("does not lock the batches which follow", func() {
+				Expect(fakeExecutor.RunCallCount()).To(Equal(2))
+				Expect(job1b.PreBackupLockCallCount()).To(BeZero())
+				Expect(logger.WarnCallCount()).To(Equal(1))
+			})
+
+			It("does not count the jobs it did not try to lock as locked", func() {
+				Expect(deployment.LockedJobs()).To(ConsistOf(job1a, job2a, job3a))
+			})
 		})
 
 		Context("if the lockOrderer returns an error", func() {
@@ -108,7 +168,7 @@ var _ = Describe("Deployment", func() {
 		var fakeExecutor *executorFakes.FakeExecutor
 
 		JustBeforeEach(func() {
-			err = deployment.Backup(fakeExecutor)
+			err = deployment.Backup(context.Background(), fakeExecutor)
 		})
 
 		BeforeEach(func() {
@@ -126,8 +186,10 @@ var _ = Describe("Deployment", func() {
 		It("calls Backup() on all backupable instances", func() {
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{
-				{orchestrator.NewBackupExecutable(job1a), orchestrator.NewBackupExecutable(job3a)},
+			_, batches := fakeExecutor.RunArgsForCall(0)
+
+			Expect(batches).To(Equal([][]executor.Executable{
+				{orchestrator.NewBackupExecutable(job1a, "my-deployment"), orchestrator.NewBackupExecutable(job3a, "my-deployment")},
 			}))
 		})
 
@@ -141,61 +203,163 @@ var _ = Describe("Deployment", func() {
 			It("fails and stops the backup", func() {
 				Expect(err).To(MatchError(ContainSubstring("backup instance1 failed")))
 
-				Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{
-					{orchestrator.NewBackupExecutable(job1a), orchestrator.NewBackupExecutable(job3a)},
+				_, batches := fakeExecutor.RunArgsForCall(0)
+
+				Expect(batches).To(Equal([][]executor.Executable{
+					{orchestrator.NewBackupExecutable(job1a, "my-deployment"), orchestrator.NewBackupExecutable(job3a, "my-deployment")},
 				}))
 			})
 		})
 	})
 
-	Context("PostBackupUnlock", func() {
+	Context("PostBackupVerify", func() {
 		var (
-			lockError    error
-			lockOrderer  *fakes.FakeLockOrderer
+			results      []orchestrator.VerifyResult
+			verifyError  error
 			fakeExecutor *executorFakes.FakeExecutor
 		)
 
+		BeforeEach(func() {
+			fakeExecutor = new(executorFakes.FakeExecutor)
+			fakeExecutor.RunStub = func(_ context.Context, executables [][]executor.Executable) []error {
+				var errs []error
+				for _, executable := range executables[0] {
+					if err := executable.Execute(context.Background()); err != nil {
+						errs = append(errs, err)
+					}
+				}
+				return errs
+			}
+			instances = []orchestrator.Instance{instance1, instance2, instance3}
+			instance1.IsBackupableReturns(true)
+			instance2.IsBackupableReturns(true)
+			job1a.HasPostBackupVerifyReturns(true)
+			job1a.NameReturns("job1a")
+			job1a.InstanceIdentifierReturns("instance1/0")
+			job2a.HasPostBackupVerifyReturns(true)
+			job2a.NameReturns("job2a")
+			job2a.InstanceIdentifierReturns("instance2/0")
+			job2a.PostBackupVerifyReturns(fmt.Errorf("dump is truncated"))
+			job3a.HasPostBackupVerifyReturns(true)
+		})
+
+		JustBeforeEach(func() {
+			results, verifyError = deployment.PostBackupVerify(context.Background(), fakeExecutor)
+		})
+
+		It("runs the verify scripts of backed up jobs which have one", func() {
+			Expect(job1a.PostBackupVerifyCallCount()).To(Equal(1))
+			Expect(job1b.PostBackupVerifyCallCount()).To(BeZero())
+			Expect(job2a.PostBackupVerifyCallCount()).To(Equal(1))
+			Expect(job3a.PostBackupVerifyCallCount()).To(BeZero())
+		})
+
+		It("returns the result of each script, and fails if any did", func() {
+			Expect(results).To(Equal([]orchestrator.VerifyResult{
+				{InstanceIdentifier: "instance1/0", JobName: "job1a"},
+				{InstanceIdentifier: "instance2/0", JobName: "job2a", Error: fmt.Errorf("dump is truncated")},
+			}))
+			Expect(verifyError).To(MatchError(ContainSubstring("dump is truncated")))
+		})
+	})
+
+	Context("PostBackupUnlock", func() {
+		var (
+			lockError     error
+			lockOrderer   *fakes.FakeLockOrderer
+			fakeExecutor  *executorFakes.FakeExecutor
+			unlockedOrder []string
+		)
+
 		BeforeEach(func() {
 			lockOrderer = new(fakes.FakeLockOrderer)
 			fakeExecutor = new(executorFakes.FakeExecutor)
+			fakeExecutor.RunStub = executor.NewSerialExecutor().Run
 			instances = []orchestrator.Instance{instance1, instance2, instance3}
 			lockOrderer.OrderReturns([][]orchestrator.Job{{job2a}, {job3a, job1a}, {job1b}}, nil)
+
+			unlockedOrder = nil
+			for _, job := range []*fakes.FakeJob{job1a, job1b, job2a, job3a} {
+				job := job
+				job.PostBackupUnlockStub = func(context.Context, bool) error {
+					unlockedOrder = append(unlockedOrder, job.Name())
+					return nil
+				}
+			}
 		})
 
-		It("calls the executor with post successful backup executables", func() {
-			lockError = deployment.PostBackupUnlock(true, lockOrderer, fakeExecutor)
+		It("unlocks every job in the reverse of the lock order, after a successful backup", func() {
+			lockError = deployment.PostBackupUnlock(context.Background(), true, lockOrderer, fakeExecutor)
 
 			Expect(lockError).NotTo(HaveOccurred())
 			Expect(lockOrderer.OrderArgsForCall(0)).To(ConsistOf(job1a, job1b, job2a, job3a))
-			Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{
-				{orchestrator.NewJobPostSuccessfulBackupUnlockExecutable(job2a)},
-				{orchestrator.NewJobPostSuccessfulBackupUnlockExecutable(job3a), orchestrator.NewJobPostSuccessfulBackupUnlockExecutable(job1a)},
-				{orchestrator.NewJobPostSuccessfulBackupUnlockExecutable(job1b)},
-			}))
+			Expect(unlockedOrder).To(Equal([]string{"job1b", "job3a", "job1a", "job2a"}))
+			_, afterSuccessfulBackup := job1b.PostBackupUnlockArgsForCall(0)
+			Expect(afterSuccessfulBackup).To(BeTrue())
+			Expect(deployment.LockedJobs()).To(BeEmpty())
 		})
 
 		Context("when called after a failed backup", func() {
-			It("calls the executor with post failed backup executables", func() {
-				lockError = deployment.PostBackupUnlock(false, lockOrderer, fakeExecutor)
+			It("unlocks every job after a failed backup", func() {
+				lockError = deployment.PostBackupUnlock(context.Background(), false, lockOrderer, fakeExecutor)
 
 				Expect(lockError).NotTo(HaveOccurred())
-				Expect(lockOrderer.OrderArgsForCall(0)).To(ConsistOf(job1a, job1b, job2a, job3a))
-				Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{
-					{orchestrator.NewJobPostFailedBackupUnlockExecutable(job2a)},
-					{orchestrator.NewJobPostFailedBackupUnlockExecutable(job3a), orchestrator.NewJobPostFailedBackupUnlockExecutable(job1a)},
-					{orchestrator.NewJobPostFailedBackupUnlockExecutable(job1b)},
-				}))
+				Expect(unlockedOrder).To(Equal([]string{"job1b", "job3a", "job1a", "job2a"}))
+				_, afterSuccessfulBackup := job1b.PostBackupUnlockArgsForCall(0)
+				Expect(afterSuccessfulBackup).To(BeFalse())
+			})
+		})
+
+		Context("when only some of the jobs were locked", func() {
+			BeforeEach(func() {
+				job3a.PreBackupLockReturns(fmt.Errorf("job3a failed"))
+			})
+
+			JustBeforeEach(func() {
+				Expect(deployment.PreBackupLock(context.Background(), lockOrderer, fakeExecutor)).To(HaveOccurred())
+				lockError = deployment.PostBackupUnlock(context.Background(), false, lockOrderer, fakeExecutor)
+			})
+
+			It("only unlocks the jobs it tried to lock, in reverse order", func() {
+				Expect(lockError).NotTo(HaveOccurred())
+				Expect(unlockedOrder).To(Equal([]string{"job3a", "job1a", "job2a"}))
+				Expect(deployment.LockedJobs()).To(BeEmpty())
+			})
+
+			It("reports which jobs were locked and unlocked", func() {
+				var report string
+				for i := 0; i < logger.InfoCallCount(); i++ {
+					_, msg, args := logger.InfoArgsForCall(i)
+					report += fmt.Sprintf(msg, args...) + "\n"
+				}
+				Expect(report).To(ContainSubstring("Lock state of the jobs of my-deployment:\n" +
+					"  instance2/0/job2a: locked, unlocked\n" +
+					"  instance3/0/job3a: failed to lock, unlocked\n" +
+					"  instance1/0/job1a: locked, unlocked\n" +
+					"  instance1/0/job1b: not locked, not unlocked\n"))
+			})
+
+			Context("and a job fails to unlock", func() {
+				BeforeEach(func() {
+					job2a.PostBackupUnlockStub = nil
+					job2a.PostBackupUnlockReturns(fmt.Errorf("job2a failed"))
+				})
+
+				It("still counts it as locked", func() {
+					Expect(lockError).To(MatchError(ContainSubstring("job2a failed")))
+					Expect(deployment.LockedJobs()).To(ConsistOf(job2a))
+				})
 			})
 		})
 
 		Context("if the post-backup-unlock fails", func() {
 			It("fails", func() {
-				fakeExecutor.RunReturns([]error{
-					fmt.Errorf("job1b failed"),
-					fmt.Errorf("job2a failed"),
-				})
+				job1b.PostBackupUnlockStub = nil
+				job1b.PostBackupUnlockReturns(fmt.Errorf("job1b failed"))
+				job2a.PostBackupUnlockStub = nil
+				job2a.PostBackupUnlockReturns(fmt.Errorf("job2a failed"))
 
-				lockError = deployment.PostBackupUnlock(true, lockOrderer, fakeExecutor)
+				lockError = deployment.PostBackupUnlock(context.Background(), true, lockOrderer, fakeExecutor)
 
 				Expect(lockError).To(MatchError(SatisfyAll(
 					ContainSubstring("job1b failed"),
@@ -208,7 +372,7 @@ var _ = Describe("Deployment", func() {
 			It("fails", func() {
 				lockOrderer.OrderReturns(nil, fmt.Errorf("test lock orderer error"))
 
-				lockError = deployment.PostBackupUnlock(true, lockOrderer, fakeExecutor)
+				lockError = deployment.PostBackupUnlock(context.Background(), true, lockOrderer, fakeExecutor)
 
 				Expect(lockError).To(MatchError(ContainSubstring("test lock orderer error")))
 			})
@@ -416,7 +580,7 @@ var _ = Describe("Deployment", func() {
 		var err error
 
 		JustBeforeEach(func() {
-			err = deployment.Restore()
+			err = deployment.Restore(context.Background())
 		})
 
 		BeforeEach(func() {
@@ -459,37 +623,31 @@ var _ = Describe("Deployment", func() {
 		BeforeEach(func() {
 			lockOrderer = new(fakes.FakeLockOrderer)
 			fakeExecutor = new(executorFakes.FakeExecutor)
+			fakeExecutor.RunStub = executor.NewSerialExecutor().Run
 			instances = []orchestrator.Instance{instance1, instance2, instance3}
 			lockOrderer.OrderReturns([][]orchestrator.Job{{job2a}, {job3a, job1a}, {job1b}}, nil)
 		})
 
 		JustBeforeEach(func() {
-			lockError = deployment.PreRestoreLock(lockOrderer, fakeExecutor)
+			lockError = deployment.PreRestoreLock(context.Background(), lockOrderer, fakeExecutor)
 		})
 
-		It("delegates the execution to the executor", func() {
+		It("locks one ordered batch of jobs at a time", func() {
 			Expect(lockError).NotTo(HaveOccurred())
 			Expect(lockOrderer.OrderArgsForCall(0)).To(ConsistOf(job1a, job1b, job2a, job3a))
-			Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{
-				{orchestrator.NewJobPreRestoreLockExecutable(job2a)},
-				{orchestrator.NewJobPreRestoreLockExecutable(job3a), orchestrator.NewJobPreRestoreLockExecutable(job1a)},
-				{orchestrator.NewJobPreRestoreLockExecutable(job1b)},
-			}))
+			Expect(fakeExecutor.RunCallCount()).To(Equal(3))
+			Expect(job1b.PreRestoreLockCallCount()).To(Equal(1))
 		})
 
 		Context("if the pre-restore-lock fails", func() {
 			BeforeEach(func() {
-				fakeExecutor.RunReturns([]error{
-					fmt.Errorf("job1b failed"),
-					fmt.Errorf("job2a failed"),
-				})
+				job3a.PreRestoreLockReturns(fmt.Errorf("job3a failed"))
 			})
 
-			It("fails", func() {
-				Expect(lockError).To(MatchError(SatisfyAll(
-					ContainSubstring("job1b failed"),
-					ContainSubstring("job2a failed"),
-				)))
+			It("fails, and does not lock the batches which follow", func() {
+				Expect(lockError).To(MatchError(ContainSubstring("job3a failed")))
+				Expect(job1b.PreRestoreLockCallCount()).To(BeZero())
+				Expect(deployment.LockedJobs()).To(ConsistOf(job1a, job2a, job3a))
 			})
 		})
 
@@ -514,30 +672,27 @@ var _ = Describe("Deployment", func() {
 		BeforeEach(func() {
 			lockOrderer = new(fakes.FakeLockOrderer)
 			fakeExecutor = new(executorFakes.FakeExecutor)
+			fakeExecutor.RunStub = executor.NewSerialExecutor().Run
 			instances = []orchestrator.Instance{instance1, instance2, instance3}
 			lockOrderer.OrderReturns([][]orchestrator.Job{{job2a}, {job3a, job1a}, {job1b}}, nil)
 		})
 
 		JustBeforeEach(func() {
-			lockError = deployment.PostRestoreUnlock(lockOrderer, fakeExecutor)
+			lockError = deployment.PostRestoreUnlock(context.Background(), lockOrderer, fakeExecutor)
 		})
 
-		It("delegates the execution to the executor", func() {
+		It("unlocks every job", func() {
 			Expect(lockError).NotTo(HaveOccurred())
 			Expect(lockOrderer.OrderArgsForCall(0)).To(ConsistOf(job1a, job1b, job2a, job3a))
-			Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{
-				{orchestrator.NewJobPostRestoreUnlockExecutable(job2a)},
-				{orchestrator.NewJobPostRestoreUnlockExecutable(job3a), orchestrator.NewJobPostRestoreUnlockExecutable(job1a)},
-				{orchestrator.NewJobPostRestoreUnlockExecutable(job1b)},
-			}))
+			_, batches := fakeExecutor.RunArgsForCall(0)
+			Expect(batches).To(HaveLen(3))
+			Expect(job2a.PostRestoreUnlockCallCount()).To(Equal(1))
 		})
 
 		Context("if the post-restore-unlock fails", func() {
 			BeforeEach(func() {
-				fakeExecutor.RunReturns([]error{
-					fmt.Errorf("job1b failed"),
-					fmt.Errorf("job2a failed"),
-				})
+				job1b.PostRestoreUnlockReturns(fmt.Errorf("job1b failed"))
+				job2a.PostRestoreUnlockReturns(fmt.Errorf("job2a failed"))
 			})
 
 			It("fails", func() {
@@ -1,12 +1,14 @@
 package orchestrator
 
 import (
+	"context"
 	"time"
 )
 
 type DrainStep struct {
 	logger         Logger
 	artifactCopier ArtifactCopier
+	onlyMissing    bool
 }
 
 func NewDrainStep(logger Logger, artifactCopier ArtifactCopier) Step {
@@ -16,7 +18,18 @@ func NewDrainStep(logger Logger, artifactCopier ArtifactCopier) Step {
 	}
 }
 
-func (s *DrainStep) Run(session *Session) error {
+func NewResumeDrainStep(logger Logger, artifactCopier ArtifactCopier) Step {
+	return &DrainStep{
+		logger:         logger,
+		artifactCopier: artifactCopier,
+		onlyMissing:    true,
+	}
+}
+
+func (s *DrainStep) Run(ctx context.Context, session *Session) error {
 	defer s.logger.Info("bbr", "Backup created of %s on %v\n", session.DeploymentName(), time.Now())
-	return s.artifactCopier.DownloadBackupFromDeployment(session.CurrentArtifact(), session.CurrentDeployment())
+	if s.onlyMissing {
+		return s.artifactCopier.DownloadMissingBackupFromDeployment(ctx, session.CurrentArtifact(), session.CurrentDeployment())
+	}
+	return s.artifactCopier.DownloadBackupFromDeployment(ctx, session.CurrentArtifact(), session.CurrentDeployment())
 }
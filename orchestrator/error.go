@@ -17,6 +17,13 @@ type BackupError customError
 type UnlockError customError
 type CleanupError customError
 type ArtifactDirError customError
+type VerificationError customError
+type PostBackupVerifyError customError
+type AbortError customError
+
+// TimeoutError marks the error of a step in which a script ran for longer than its timeout.
+// It wraps the error of the step, so a lock which timed out is still a lock error.
+type TimeoutError customError
 
 func NewLockError(errorMessage string) LockError {
 	return LockError{errors.New(errorMessage)}
@@ -38,6 +45,54 @@ func NewArtifactDirError(errorMessage string) ArtifactDirError {
 	return ArtifactDirError{errors.New(errorMessage)}
 }
 
+func NewVerificationError(errorMessage string) VerificationError {
+	return VerificationError{errors.New(errorMessage)}
+}
+
+func NewPostBackupVerifyError(errorMessage string) PostBackupVerifyError {
+	return PostBackupVerifyError{errors.New(errorMessage)}
+}
+
+func NewAbortError(errorMessage string) AbortError {
+	return AbortError{errors.New(errorMessage)}
+}
+
+func NewTimeoutError(err error) TimeoutError {
+	return TimeoutError{err}
+}
+
+// withTimeout marks stepErr as a timeout if any of the scripts behind cause timed out
+func withTimeout(stepErr, cause error) error {
+	if causedByTimeout(cause) {
+		return NewTimeoutError(stepErr)
+	}
+	return stepErr
+}
+
+func causedByTimeout(err error) bool {
+	if errs, ok := err.(Error); ok {
+		for _, e := range errs {
+			if causedByTimeout(e) {
+				return true
+			}
+		}
+		return false
+	}
+
+	timeout, ok := errors.Cause(err).(interface {
+		Timeout() bool
+	})
+	return ok && timeout.Timeout()
+}
+
+// withoutTimeout returns the error a timeout was marked on
+func withoutTimeout(err error) error {
+	if timeout, ok := err.(TimeoutError); ok {
+		return timeout.error
+	}
+	return err
+}
+
 func ConvertErrors(errs []error) error {
 	flattenedErrors := flattenErrors(errs)
 
@@ -101,7 +156,7 @@ func (err Error) getPostFix() string {
 
 func (err Error) ContainsUnlockOrCleanupOrArtifactDirExists() bool {
 	for _, e := range err {
-		switch e.(type) {
+		switch withoutTimeout(e).(type) {
 		case UnlockError:
 			return true
 		case CleanupError:
@@ -118,7 +173,7 @@ func (err Error) ContainsUnlockOrCleanupOrArtifactDirExists() bool {
 
 func (err Error) ContainsArtifactDirError() bool {
 	for _, e := range err {
-		_, ok := e.(ArtifactDirError)
+		_, ok := withoutTimeout(e).(ArtifactDirError)
 		return ok
 	}
 	return false
@@ -126,7 +181,7 @@ func (err Error) ContainsArtifactDirError() bool {
 
 func (err Error) IsCleanup() bool {
 	if len(err) == 1 {
-		_, ok := err[0].(CleanupError)
+		_, ok := withoutTimeout(err[0]).(CleanupError)
 		return ok
 	}
 
@@ -137,7 +192,7 @@ func (err Error) IsPostBackup() bool {
 	foundPostBackupError := false
 
 	for _, e := range err {
-		switch e.(type) {
+		switch withoutTimeout(e).(type) {
 		case UnlockError:
 			foundPostBackupError = true
 		case CleanupError:
@@ -162,13 +217,22 @@ func BuildExitCode(errs Error) int {
 	exitCode := 0
 
 	for _, err := range errs {
+		if timeout, ok := err.(TimeoutError); ok {
+			exitCode = exitCode | 1<<6
+			err = timeout.error
+		}
+
 		switch err.(type) {
+		case PostBackupVerifyError:
+			exitCode = exitCode | 1<<1
 		case LockError:
 			exitCode = exitCode | 1<<2
 		case UnlockError:
 			exitCode = exitCode | 1<<3
 		case CleanupError:
 			exitCode = exitCode | 1<<4
+		case VerificationError:
+			exitCode = exitCode | 1<<5
 		default:
 			exitCode = exitCode | 1
 		}
@@ -176,3 +240,41 @@ func BuildExitCode(errs Error) int {
 
 	return exitCode
 }
+
+// ErrorTypes classifies errors the same way BuildExitCode does, for machine-readable output
+func ErrorTypes(errs Error) []string {
+	var types []string
+	seen := map[string]bool{}
+
+	for _, err := range errs {
+		if _, ok := err.(TimeoutError); ok && !seen["timeout"] {
+			seen["timeout"] = true
+			types = append(types, "timeout")
+		}
+
+		var errorType string
+		switch withoutTimeout(err).(type) {
+		case LockError:
+			errorType = "lock"
+		case UnlockError:
+			errorType = "unlock"
+		case CleanupError:
+			errorType = "cleanup"
+		case VerificationError:
+			errorType = "verification"
+		case PostBackupVerifyError:
+			errorType = "post-backup-verify"
+		case AbortError:
+			errorType = "abort"
+		default:
+			errorType = "general"
+		}
+
+		if !seen[errorType] {
+			seen[errorType] = true
+			types = append(types, errorType)
+		}
+	}
+
+	return types
+}
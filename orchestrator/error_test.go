@@ -75,6 +75,13 @@ var _ = Describe("Error", func() {
 		})
 	})
 
+	Describe("IsPostBackup with timeouts", func() {
+		It("returns true when an unlock timed out", func() {
+			err := orchestrator.NewError(orchestrator.NewTimeoutError(postBackupUnlockError))
+			Expect(err.IsPostBackup()).To(BeTrue())
+		})
+	})
+
 	Describe("IsFatal", func() {
 		It("returns true when there is one error - a generic error", func() {
 			errors := orchestrator.Error{genericError}
@@ -112,6 +119,11 @@ var _ = Describe("Error", func() {
 				{"lockError", []error{lockError}, 4},
 				{"unlockError", []error{postBackupUnlockError}, 8},
 				{"cleanupError", []error{cleanupError}, 16},
+				{"verificationError", []error{orchestrator.NewVerificationError("checksum mismatch")}, 32},
+				{"postBackupVerifyError", []error{orchestrator.NewPostBackupVerifyError("dump is truncated")}, 2},
+				{"postBackupVerifyAndVerificationErrors", []error{orchestrator.NewPostBackupVerifyError("dump is truncated"), orchestrator.NewVerificationError("checksum mismatch")}, 34},
+				{"lockTimeoutError", []error{orchestrator.NewTimeoutError(lockError)}, 68},
+				{"backupTimeoutError", []error{orchestrator.NewTimeoutError(backupError)}, 65},
 			}
 
 			for i := range errorCases {
@@ -158,6 +170,18 @@ var _ = Describe("Error", func() {
 		})
 	})
 
+	Describe("ErrorTypes", func() {
+		It("reports timeouts as well as the type of error which timed out", func() {
+			types := orchestrator.ErrorTypes([]error{orchestrator.NewTimeoutError(lockError), postBackupUnlockError})
+			Expect(types).To(Equal([]string{"timeout", "lock", "unlock"}))
+		})
+
+		It("tells failed post-backup-verify scripts apart from corrupt backups", func() {
+			types := orchestrator.ErrorTypes([]error{orchestrator.NewPostBackupVerifyError("dump is truncated"), orchestrator.NewVerificationError("checksum mismatch")})
+			Expect(types).To(Equal([]string{"post-backup-verify", "verification"}))
+		})
+	})
+
 	Describe("ConvertErrors", func() {
 		var errorOne = errors.New("error one")
 		var errorTwo = errors.New("error two")
@@ -2,17 +2,19 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 )
 
 type FakeArtifactCopier struct {
-	DownloadBackupFromDeploymentStub        func(orchestrator.Backup, orchestrator.Deployment) error
+	DownloadBackupFromDeploymentStub        func(context.Context, orchestrator.Backup, orchestrator.Deployment) error
 	downloadBackupFromDeploymentMutex       sync.RWMutex
 	downloadBackupFromDeploymentArgsForCall []struct {
-		arg1 orchestrator.Backup
-		arg2 orchestrator.Deployment
+		arg1 context.Context
+		arg2 orchestrator.Backup
+		arg3 orchestrator.Deployment
 	}
 	downloadBackupFromDeploymentReturns struct {
 		result1 error
@@ -20,11 +22,25 @@ type FakeArtifactCopier struct {
 	downloadBackupFromDeploymentReturnsOnCall map[int]struct {
 		result1 error
 	}
-	UploadBackupToDeploymentStub        func(orchestrator.Backup, orchestrator.Deployment) error
+	DownloadMissingBackupFromDeploymentStub        func(context.Context, orchestrator.Backup, orchestrator.Deployment) error
+	downloadMissingBackupFromDeploymentMutex       sync.RWMutex
+	downloadMissingBackupFromDeploymentArgsForCall []struct {
+		arg1 context.Context
+		arg2 orchestrator.Backup
+		arg3 orchestrator.Deployment
+	}
+	downloadMissingBackupFromDeploymentReturns struct {
+		result1 error
+	}
+	downloadMissingBackupFromDeploymentReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UploadBackupToDeploymentStub        func(context.Context, orchestrator.Backup, orchestrator.Deployment) error
 	uploadBackupToDeploymentMutex       sync.RWMutex
 	uploadBackupToDeploymentArgsForCall []struct {
-		arg1 orchestrator.Backup
-		arg2 orchestrator.Deployment
+		arg1 context.Context
+		arg2 orchestrator.Backup
+		arg3 orchestrator.Deployment
 	}
 	uploadBackupToDeploymentReturns struct {
 		result1 error
@@ -36,17 +52,18 @@ type FakeArtifactCopier struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeArtifactCopier) DownloadBackupFromDeployment(arg1 orchestrator.Backup, arg2 orchestrator.Deployment) error {
+func (fake *FakeArtifactCopier) DownloadBackupFromDeployment(arg1 context.Context, arg2 orchestrator.Backup, arg3 orchestrator.Deployment) error {
 	fake.downloadBackupFromDeploymentMutex.Lock()
 	ret, specificReturn := fake.downloadBackupFromDeploymentReturnsOnCall[len(fake.downloadBackupFromDeploymentArgsForCall)]
 	fake.downloadBackupFromDeploymentArgsForCall = append(fake.downloadBackupFromDeploymentArgsForCall, struct {
-		arg1 orchestrator.Backup
-		arg2 orchestrator.Deployment
-	}{arg1, arg2})
-	fake.recordInvocation("DownloadBackupFromDeployment", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 orchestrator.Backup
+		arg3 orchestrator.Deployment
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("DownloadBackupFromDeployment", []interface{}{arg1, arg2, arg3})
 	fake.downloadBackupFromDeploymentMutex.Unlock()
 	if fake.DownloadBackupFromDeploymentStub != nil {
-		return fake.DownloadBackupFromDeploymentStub(arg1, arg2)
+		return fake.DownloadBackupFromDeploymentStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -60,10 +77,10 @@ func (fake *FakeArtifactCopier) DownloadBackupFromDeploymentCallCount() int {
 	return len(fake.downloadBackupFromDeploymentArgsForCall)
 }
 
-func (fake *FakeArtifactCopier) DownloadBackupFromDeploymentArgsForCall(i int) (orchestrator.Backup, orchestrator.Deployment) {
+func (fake *FakeArtifactCopier) DownloadBackupFromDeploymentArgsForCall(i int) (context.Context, orchestrator.Backup, orchestrator.Deployment) {
 	fake.downloadBackupFromDeploymentMutex.RLock()
 	defer fake.downloadBackupFromDeploymentMutex.RUnlock()
-	return fake.downloadBackupFromDeploymentArgsForCall[i].arg1, fake.downloadBackupFromDeploymentArgsForCall[i].arg2
+	return fake.downloadBackupFromDeploymentArgsForCall[i].arg1, fake.downloadBackupFromDeploymentArgsForCall[i].arg2, fake.downloadBackupFromDeploymentArgsForCall[i].arg3
 }
 
 func (fake *FakeArtifactCopier) DownloadBackupFromDeploymentReturns(result1 error) {
@@ -85,17 +102,68 @@ func (fake *FakeArtifactCopier) DownloadBackupFromDeploymentReturnsOnCall(i int,
 	}{result1}
 }
 
-func (fake *FakeArtifactCopier) UploadBackupToDeployment(arg1 orchestrator.Backup, arg2 orchestrator.Deployment) error {
+func (fake *FakeArtifactCopier) DownloadMissingBackupFromDeployment(arg1 context.Context, arg2 orchestrator.Backup, arg3 orchestrator.Deployment) error {
+	fake.downloadMissingBackupFromDeploymentMutex.Lock()
+	ret, specificReturn := fake.downloadMissingBackupFromDeploymentReturnsOnCall[len(fake.downloadMissingBackupFromDeploymentArgsForCall)]
+	fake.downloadMissingBackupFromDeploymentArgsForCall = append(fake.downloadMissingBackupFromDeploymentArgsForCall, struct {
+		arg1 context.Context
+		arg2 orchestrator.Backup
+		arg3 orchestrator.Deployment
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("DownloadMissingBackupFromDeployment", []interface{}{arg1, arg2, arg3})
+	fake.downloadMissingBackupFromDeploymentMutex.Unlock()
+	if fake.DownloadMissingBackupFromDeploymentStub != nil {
+		return fake.DownloadMissingBackupFromDeploymentStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.downloadMissingBackupFromDeploymentReturns.result1
+}
+
+func (fake *FakeArtifactCopier) DownloadMissingBackupFromDeploymentCallCount() int {
+	fake.downloadMissingBackupFromDeploymentMutex.RLock()
+	defer fake.downloadMissingBackupFromDeploymentMutex.RUnlock()
+	return len(fake.downloadMissingBackupFromDeploymentArgsForCall)
+}
+
+func (fake *FakeArtifactCopier) DownloadMissingBackupFromDeploymentArgsForCall(i int) (context.Context, orchestrator.Backup, orchestrator.Deployment) {
+	fake.downloadMissingBackupFromDeploymentMutex.RLock()
+	defer fake.downloadMissingBackupFromDeploymentMutex.RUnlock()
+	return fake.downloadMissingBackupFromDeploymentArgsForCall[i].arg1, fake.downloadMissingBackupFromDeploymentArgsForCall[i].arg2, fake.downloadMissingBackupFromDeploymentArgsForCall[i].arg3
+}
+
+func (fake *FakeArtifactCopier) DownloadMissingBackupFromDeploymentReturns(result1 error) {
+	fake.DownloadMissingBackupFromDeploymentStub = nil
+	fake.downloadMissingBackupFromDeploymentReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeArtifactCopier) DownloadMissingBackupFromDeploymentReturnsOnCall(i int, result1 error) {
+	fake.DownloadMissingBackupFromDeploymentStub = nil
+	if fake.downloadMissingBackupFromDeploymentReturnsOnCall == nil {
+		fake.downloadMissingBackupFromDeploymentReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.downloadMissingBackupFromDeploymentReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeArtifactCopier) UploadBackupToDeployment(arg1 context.Context, arg2 orchestrator.Backup, arg3 orchestrator.Deployment) error {
 	fake.uploadBackupToDeploymentMutex.Lock()
 	ret, specificReturn := fake.uploadBackupToDeploymentReturnsOnCall[len(fake.uploadBackupToDeploymentArgsForCall)]
 	fake.uploadBackupToDeploymentArgsForCall = append(fake.uploadBackupToDeploymentArgsForCall, struct {
-		arg1 orchestrator.Backup
-		arg2 orchestrator.Deployment
-	}{arg1, arg2})
-	fake.recordInvocation("UploadBackupToDeployment", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 orchestrator.Backup
+		arg3 orchestrator.Deployment
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("UploadBackupToDeployment", []interface{}{arg1, arg2, arg3})
 	fake.uploadBackupToDeploymentMutex.Unlock()
 	if fake.UploadBackupToDeploymentStub != nil {
-		return fake.UploadBackupToDeploymentStub(arg1, arg2)
+		return fake.UploadBackupToDeploymentStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -109,10 +177,10 @@ func (fake *FakeArtifactCopier) UploadBackupToDeploymentCallCount() int {
 	return len(fake.uploadBackupToDeploymentArgsForCall)
 }
 
-func (fake *FakeArtifactCopier) UploadBackupToDeploymentArgsForCall(i int) (orchestrator.Backup, orchestrator.Deployment) {
+func (fake *FakeArtifactCopier) UploadBackupToDeploymentArgsForCall(i int) (context.Context, orchestrator.Backup, orchestrator.Deployment) {
 	fake.uploadBackupToDeploymentMutex.RLock()
 	defer fake.uploadBackupToDeploymentMutex.RUnlock()
-	return fake.uploadBackupToDeploymentArgsForCall[i].arg1, fake.uploadBackupToDeploymentArgsForCall[i].arg2
+	return fake.uploadBackupToDeploymentArgsForCall[i].arg1, fake.uploadBackupToDeploymentArgsForCall[i].arg2, fake.uploadBackupToDeploymentArgsForCall[i].arg3
 }
 
 func (fake *FakeArtifactCopier) UploadBackupToDeploymentReturns(result1 error) {
@@ -139,6 +207,8 @@ func (fake *FakeArtifactCopier) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.downloadBackupFromDeploymentMutex.RLock()
 	defer fake.downloadBackupFromDeploymentMutex.RUnlock()
+	fake.downloadMissingBackupFromDeploymentMutex.RLock()
+	defer fake.downloadMissingBackupFromDeploymentMutex.RUnlock()
 	fake.uploadBackupToDeploymentMutex.RLock()
 	defer fake.uploadBackupToDeploymentMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
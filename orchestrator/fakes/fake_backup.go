@@ -83,6 +83,29 @@ type FakeBackup struct {
 	addFinishTimeReturnsOnCall map[int]struct {
 		result1 error
 	}
+	AddLogStub        func(name string, contents []byte) error
+	addLogMutex       sync.RWMutex
+	addLogArgsForCall []struct {
+		name     string
+		contents []byte
+	}
+	addLogReturns struct {
+		result1 error
+	}
+	addLogReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AddVerifyResultsStub        func([]orchestrator.VerifyResult) error
+	addVerifyResultsMutex       sync.RWMutex
+	addVerifyResultsArgsForCall []struct {
+		arg1 []orchestrator.VerifyResult
+	}
+	addVerifyResultsReturns struct {
+		result1 error
+	}
+	addVerifyResultsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	FetchChecksumStub        func(orchestrator.ArtifactIdentifier) (orchestrator.BackupChecksum, error)
 	fetchChecksumMutex       sync.RWMutex
 	fetchChecksumArgsForCall []struct {
@@ -123,6 +146,20 @@ type FakeBackup struct {
 		result1 bool
 		result2 error
 	}
+	OriginalInstanceIDStub        func(name, index string) (string, error)
+	originalInstanceIDMutex       sync.RWMutex
+	originalInstanceIDArgsForCall []struct {
+		name  string
+		index string
+	}
+	originalInstanceIDReturns struct {
+		result1 string
+		result2 error
+	}
+	originalInstanceIDReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	SaveManifestStub        func(manifest string) error
 	saveManifestMutex       sync.RWMutex
 	saveManifestArgsForCall []struct {
@@ -447,6 +484,113 @@ func (fake *FakeBackup) AddFinishTimeReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeBackup) AddLog(name string, contents []byte) error {
+	var contentsCopy []byte
+	if contents != nil {
+		contentsCopy = make([]byte, len(contents))
+		copy(contentsCopy, contents)
+	}
+	fake.addLogMutex.Lock()
+	ret, specificReturn := fake.addLogReturnsOnCall[len(fake.addLogArgsForCall)]
+	fake.addLogArgsForCall = append(fake.addLogArgsForCall, struct {
+		name     string
+		contents []byte
+	}{name, contentsCopy})
+	fake.recordInvocation("AddLog", []interface{}{name, contentsCopy})
+	fake.addLogMutex.Unlock()
+	if fake.AddLogStub != nil {
+		return fake.AddLogStub(name, contents)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.addLogReturns.result1
+}
+
+func (fake *FakeBackup) AddLogCallCount() int {
+	fake.addLogMutex.RLock()
+	defer fake.addLogMutex.RUnlock()
+	return len(fake.addLogArgsForCall)
+}
+
+func (fake *FakeBackup) AddLogArgsForCall(i int) (string, []byte) {
+	fake.addLogMutex.RLock()
+	defer fake.addLogMutex.RUnlock()
+	return fake.addLogArgsForCall[i].name, fake.addLogArgsForCall[i].contents
+}
+
+func (fake *FakeBackup) AddLogReturns(result1 error) {
+	fake.AddLogStub = nil
+	fake.addLogReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackup) AddLogReturnsOnCall(i int, result1 error) {
+	fake.AddLogStub = nil
+	if fake.addLogReturnsOnCall == nil {
+		fake.addLogReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addLogReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackup) AddVerifyResults(arg1 []orchestrator.VerifyResult) error {
+	var arg1Copy []orchestrator.VerifyResult
+	if arg1 != nil {
+		arg1Copy = make([]orchestrator.VerifyResult, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.addVerifyResultsMutex.Lock()
+	ret, specificReturn := fake.addVerifyResultsReturnsOnCall[len(fake.addVerifyResultsArgsForCall)]
+	fake.addVerifyResultsArgsForCall = append(fake.addVerifyResultsArgsForCall, struct {
+		arg1 []orchestrator.VerifyResult
+	}{arg1Copy})
+	fake.recordInvocation("AddVerifyResults", []interface{}{arg1Copy})
+	fake.addVerifyResultsMutex.Unlock()
+	if fake.AddVerifyResultsStub != nil {
+		return fake.AddVerifyResultsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.addVerifyResultsReturns.result1
+}
+
+func (fake *FakeBackup) AddVerifyResultsCallCount() int {
+	fake.addVerifyResultsMutex.RLock()
+	defer fake.addVerifyResultsMutex.RUnlock()
+	return len(fake.addVerifyResultsArgsForCall)
+}
+
+func (fake *FakeBackup) AddVerifyResultsArgsForCall(i int) []orchestrator.VerifyResult {
+	fake.addVerifyResultsMutex.RLock()
+	defer fake.addVerifyResultsMutex.RUnlock()
+	return fake.addVerifyResultsArgsForCall[i].arg1
+}
+
+func (fake *FakeBackup) AddVerifyResultsReturns(result1 error) {
+	fake.AddVerifyResultsStub = nil
+	fake.addVerifyResultsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackup) AddVerifyResultsReturnsOnCall(i int, result1 error) {
+	fake.AddVerifyResultsStub = nil
+	if fake.addVerifyResultsReturnsOnCall == nil {
+		fake.addVerifyResultsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addVerifyResultsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBackup) FetchChecksum(arg1 orchestrator.ArtifactIdentifier) (orchestrator.BackupChecksum, error) {
 	fake.fetchChecksumMutex.Lock()
 	ret, specificReturn := fake.fetchChecksumReturnsOnCall[len(fake.fetchChecksumArgsForCall)]
@@ -606,6 +750,58 @@ func (fake *FakeBackup) DeploymentMatchesReturnsOnCall(i int, result1 bool, resu
 	}{result1, result2}
 }
 
+func (fake *FakeBackup) OriginalInstanceID(name string, index string) (string, error) {
+	fake.originalInstanceIDMutex.Lock()
+	ret, specificReturn := fake.originalInstanceIDReturnsOnCall[len(fake.originalInstanceIDArgsForCall)]
+	fake.originalInstanceIDArgsForCall = append(fake.originalInstanceIDArgsForCall, struct {
+		name  string
+		index string
+	}{name, index})
+	fake.recordInvocation("OriginalInstanceID", []interface{}{name, index})
+	fake.originalInstanceIDMutex.Unlock()
+	if fake.OriginalInstanceIDStub != nil {
+		return fake.OriginalInstanceIDStub(name, index)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.originalInstanceIDReturns.result1, fake.originalInstanceIDReturns.result2
+}
+
+func (fake *FakeBackup) OriginalInstanceIDCallCount() int {
+	fake.originalInstanceIDMutex.RLock()
+	defer fake.originalInstanceIDMutex.RUnlock()
+	return len(fake.originalInstanceIDArgsForCall)
+}
+
+func (fake *FakeBackup) OriginalInstanceIDArgsForCall(i int) (string, string) {
+	fake.originalInstanceIDMutex.RLock()
+	defer fake.originalInstanceIDMutex.RUnlock()
+	return fake.originalInstanceIDArgsForCall[i].name, fake.originalInstanceIDArgsForCall[i].index
+}
+
+func (fake *FakeBackup) OriginalInstanceIDReturns(result1 string, result2 error) {
+	fake.OriginalInstanceIDStub = nil
+	fake.originalInstanceIDReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBackup) OriginalInstanceIDReturnsOnCall(i int, result1 string, result2 error) {
+	fake.OriginalInstanceIDStub = nil
+	if fake.originalInstanceIDReturnsOnCall == nil {
+		fake.originalInstanceIDReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.originalInstanceIDReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBackup) SaveManifest(manifest string) error {
 	fake.saveManifestMutex.Lock()
 	ret, specificReturn := fake.saveManifestReturnsOnCall[len(fake.saveManifestArgsForCall)]
@@ -712,12 +908,18 @@ func (fake *FakeBackup) Invocations() map[string][][]interface{} {
 	defer fake.createMetadataFileWithStartTimeMutex.RUnlock()
 	fake.addFinishTimeMutex.RLock()
 	defer fake.addFinishTimeMutex.RUnlock()
+	fake.addLogMutex.RLock()
+	defer fake.addLogMutex.RUnlock()
+	fake.addVerifyResultsMutex.RLock()
+	defer fake.addVerifyResultsMutex.RUnlock()
 	fake.fetchChecksumMutex.RLock()
 	defer fake.fetchChecksumMutex.RUnlock()
 	fake.calculateChecksumMutex.RLock()
 	defer fake.calculateChecksumMutex.RUnlock()
 	fake.deploymentMatchesMutex.RLock()
 	defer fake.deploymentMatchesMutex.RUnlock()
+	fake.originalInstanceIDMutex.RLock()
+	defer fake.originalInstanceIDMutex.RUnlock()
 	fake.saveManifestMutex.RLock()
 	defer fake.saveManifestMutex.RUnlock()
 	fake.validMutex.RLock()
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
@@ -9,6 +10,15 @@ import (
 )
 
 type FakeDeployment struct {
+	NameStub        func() string
+	nameMutex       sync.RWMutex
+	nameArgsForCall []struct{}
+	nameReturns     struct {
+		result1 string
+	}
+	nameReturnsOnCall map[int]struct {
+		result1 string
+	}
 	IsBackupableStub        func() bool
 	isBackupableMutex       sync.RWMutex
 	isBackupableArgsForCall []struct{}
@@ -63,11 +73,24 @@ type FakeDeployment struct {
 	restorableInstancesReturnsOnCall map[int]struct {
 		result1 []orchestrator.Instance
 	}
-	PreBackupLockStub        func(orchestrator.LockOrderer, executor.Executor) error
+	PreBackupCheckStub        func(context.Context, executor.Executor) error
+	preBackupCheckMutex       sync.RWMutex
+	preBackupCheckArgsForCall []struct {
+		arg1 context.Context
+		arg2 executor.Executor
+	}
+	preBackupCheckReturns struct {
+		result1 error
+	}
+	preBackupCheckReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PreBackupLockStub        func(context.Context, orchestrator.LockOrderer, executor.Executor) error
 	preBackupLockMutex       sync.RWMutex
 	preBackupLockArgsForCall []struct {
-		arg1 orchestrator.LockOrderer
-		arg2 executor.Executor
+		arg1 context.Context
+		arg2 orchestrator.LockOrderer
+		arg3 executor.Executor
 	}
 	preBackupLockReturns struct {
 		result1 error
@@ -75,10 +98,11 @@ type FakeDeployment struct {
 	preBackupLockReturnsOnCall map[int]struct {
 		result1 error
 	}
-	BackupStub        func(executor.Executor) error
+	BackupStub        func(context.Context, executor.Executor) error
 	backupMutex       sync.RWMutex
 	backupArgsForCall []struct {
-		arg1 executor.Executor
+		arg1 context.Context
+		arg2 executor.Executor
 	}
 	backupReturns struct {
 		result1 error
@@ -86,12 +110,27 @@ type FakeDeployment struct {
 	backupReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PostBackupUnlockStub        func(bool, orchestrator.LockOrderer, executor.Executor) error
+	PostBackupVerifyStub        func(context.Context, executor.Executor) ([]orchestrator.VerifyResult, error)
+	postBackupVerifyMutex       sync.RWMutex
+	postBackupVerifyArgsForCall []struct {
+		arg1 context.Context
+		arg2 executor.Executor
+	}
+	postBackupVerifyReturns struct {
+		result1 []orchestrator.VerifyResult
+		result2 error
+	}
+	postBackupVerifyReturnsOnCall map[int]struct {
+		result1 []orchestrator.VerifyResult
+		result2 error
+	}
+	PostBackupUnlockStub        func(context.Context, bool, orchestrator.LockOrderer, executor.Executor) error
 	postBackupUnlockMutex       sync.RWMutex
 	postBackupUnlockArgsForCall []struct {
-		arg1 bool
-		arg2 orchestrator.LockOrderer
-		arg3 executor.Executor
+		arg1 context.Context
+		arg2 bool
+		arg3 orchestrator.LockOrderer
+		arg4 executor.Executor
 	}
 	postBackupUnlockReturns struct {
 		result1 error
@@ -99,10 +138,12 @@ type FakeDeployment struct {
 	postBackupUnlockReturnsOnCall map[int]struct {
 		result1 error
 	}
-	RestoreStub        func() error
+	RestoreStub        func(context.Context) error
 	restoreMutex       sync.RWMutex
-	restoreArgsForCall []struct{}
-	restoreReturns     struct {
+	restoreArgsForCall []struct {
+		arg1 context.Context
+	}
+	restoreReturns struct {
 		result1 error
 	}
 	restoreReturnsOnCall map[int]struct {
@@ -144,11 +185,12 @@ type FakeDeployment struct {
 	customArtifactNamesMatchReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PreRestoreLockStub        func(orchestrator.LockOrderer, executor.Executor) error
+	PreRestoreLockStub        func(context.Context, orchestrator.LockOrderer, executor.Executor) error
 	preRestoreLockMutex       sync.RWMutex
 	preRestoreLockArgsForCall []struct {
-		arg1 orchestrator.LockOrderer
-		arg2 executor.Executor
+		arg1 context.Context
+		arg2 orchestrator.LockOrderer
+		arg3 executor.Executor
 	}
 	preRestoreLockReturns struct {
 		result1 error
@@ -156,11 +198,12 @@ type FakeDeployment struct {
 	preRestoreLockReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PostRestoreUnlockStub        func(orchestrator.LockOrderer, executor.Executor) error
+	PostRestoreUnlockStub        func(context.Context, orchestrator.LockOrderer, executor.Executor) error
 	postRestoreUnlockMutex       sync.RWMutex
 	postRestoreUnlockArgsForCall []struct {
-		arg1 orchestrator.LockOrderer
-		arg2 executor.Executor
+		arg1 context.Context
+		arg2 orchestrator.LockOrderer
+		arg3 executor.Executor
 	}
 	postRestoreUnlockReturns struct {
 		result1 error
@@ -179,10 +222,59 @@ type FakeDeployment struct {
 	validateLockingDependenciesReturnsOnCall map[int]struct {
 		result1 error
 	}
+	LockedJobsStub        func() []orchestrator.Job
+	lockedJobsMutex       sync.RWMutex
+	lockedJobsArgsForCall []struct{}
+	lockedJobsReturns     struct {
+		result1 []orchestrator.Job
+	}
+	lockedJobsReturnsOnCall map[int]struct {
+		result1 []orchestrator.Job
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeDeployment) Name() string {
+	fake.nameMutex.Lock()
+	ret, specificReturn := fake.nameReturnsOnCall[len(fake.nameArgsForCall)]
+	fake.nameArgsForCall = append(fake.nameArgsForCall, struct{}{})
+	fake.recordInvocation("Name", []interface{}{})
+	fake.nameMutex.Unlock()
+	if fake.NameStub != nil {
+		return fake.NameStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.nameReturns.result1
+}
+
+func (fake *FakeDeployment) NameCallCount() int {
+	fake.nameMutex.RLock()
+	defer fake.nameMutex.RUnlock()
+	return len(fake.nameArgsForCall)
+}
+
+func (fake *FakeDeployment) NameReturns(result1 string) {
+	fake.NameStub = nil
+	fake.nameReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeDeployment) NameReturnsOnCall(i int, result1 string) {
+	fake.NameStub = nil
+	if fake.nameReturnsOnCall == nil {
+		fake.nameReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.nameReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
 func (fake *FakeDeployment) IsBackupable() bool {
 	fake.isBackupableMutex.Lock()
 	ret, specificReturn := fake.isBackupableReturnsOnCall[len(fake.isBackupableArgsForCall)]
@@ -423,17 +515,67 @@ func (fake *FakeDeployment) RestorableInstancesReturnsOnCall(i int, result1 []or
 	}{result1}
 }
 
-func (fake *FakeDeployment) PreBackupLock(arg1 orchestrator.LockOrderer, arg2 executor.Executor) error {
+func (fake *FakeDeployment) PreBackupCheck(arg1 context.Context, arg2 executor.Executor) error {
+	fake.preBackupCheckMutex.Lock()
+	ret, specificReturn := fake.preBackupCheckReturnsOnCall[len(fake.preBackupCheckArgsForCall)]
+	fake.preBackupCheckArgsForCall = append(fake.preBackupCheckArgsForCall, struct {
+		arg1 context.Context
+		arg2 executor.Executor
+	}{arg1, arg2})
+	fake.recordInvocation("PreBackupCheck", []interface{}{arg1, arg2})
+	fake.preBackupCheckMutex.Unlock()
+	if fake.PreBackupCheckStub != nil {
+		return fake.PreBackupCheckStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.preBackupCheckReturns.result1
+}
+
+func (fake *FakeDeployment) PreBackupCheckCallCount() int {
+	fake.preBackupCheckMutex.RLock()
+	defer fake.preBackupCheckMutex.RUnlock()
+	return len(fake.preBackupCheckArgsForCall)
+}
+
+func (fake *FakeDeployment) PreBackupCheckArgsForCall(i int) (context.Context, executor.Executor) {
+	fake.preBackupCheckMutex.RLock()
+	defer fake.preBackupCheckMutex.RUnlock()
+	return fake.preBackupCheckArgsForCall[i].arg1, fake.preBackupCheckArgsForCall[i].arg2
+}
+
+func (fake *FakeDeployment) PreBackupCheckReturns(result1 error) {
+	fake.PreBackupCheckStub = nil
+	fake.preBackupCheckReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDeployment) PreBackupCheckReturnsOnCall(i int, result1 error) {
+	fake.PreBackupCheckStub = nil
+	if fake.preBackupCheckReturnsOnCall == nil {
+		fake.preBackupCheckReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.preBackupCheckReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDeployment) PreBackupLock(arg1 context.Context, arg2 orchestrator.LockOrderer, arg3 executor.Executor) error {
 	fake.preBackupLockMutex.Lock()
 	ret, specificReturn := fake.preBackupLockReturnsOnCall[len(fake.preBackupLockArgsForCall)]
 	fake.preBackupLockArgsForCall = append(fake.preBackupLockArgsForCall, struct {
-		arg1 orchestrator.LockOrderer
-		arg2 executor.Executor
-	}{arg1, arg2})
-	fake.recordInvocation("PreBackupLock", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 orchestrator.LockOrderer
+		arg3 executor.Executor
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("PreBackupLock", []interface{}{arg1, arg2, arg3})
 	fake.preBackupLockMutex.Unlock()
 	if fake.PreBackupLockStub != nil {
-		return fake.PreBackupLockStub(arg1, arg2)
+		return fake.PreBackupLockStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -447,10 +589,10 @@ func (fake *FakeDeployment) PreBackupLockCallCount() int {
 	return len(fake.preBackupLockArgsForCall)
 }
 
-func (fake *FakeDeployment) PreBackupLockArgsForCall(i int) (orchestrator.LockOrderer, executor.Executor) {
+func (fake *FakeDeployment) PreBackupLockArgsForCall(i int) (context.Context, orchestrator.LockOrderer, executor.Executor) {
 	fake.preBackupLockMutex.RLock()
 	defer fake.preBackupLockMutex.RUnlock()
-	return fake.preBackupLockArgsForCall[i].arg1, fake.preBackupLockArgsForCall[i].arg2
+	return fake.preBackupLockArgsForCall[i].arg1, fake.preBackupLockArgsForCall[i].arg2, fake.preBackupLockArgsForCall[i].arg3
 }
 
 func (fake *FakeDeployment) PreBackupLockReturns(result1 error) {
@@ -472,16 +614,17 @@ func (fake *FakeDeployment) PreBackupLockReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeDeployment) Backup(arg1 executor.Executor) error {
+func (fake *FakeDeployment) Backup(arg1 context.Context, arg2 executor.Executor) error {
 	fake.backupMutex.Lock()
 	ret, specificReturn := fake.backupReturnsOnCall[len(fake.backupArgsForCall)]
 	fake.backupArgsForCall = append(fake.backupArgsForCall, struct {
-		arg1 executor.Executor
-	}{arg1})
-	fake.recordInvocation("Backup", []interface{}{arg1})
+		arg1 context.Context
+		arg2 executor.Executor
+	}{arg1, arg2})
+	fake.recordInvocation("Backup", []interface{}{arg1, arg2})
 	fake.backupMutex.Unlock()
 	if fake.BackupStub != nil {
-		return fake.BackupStub(arg1)
+		return fake.BackupStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -495,10 +638,10 @@ func (fake *FakeDeployment) BackupCallCount() int {
 	return len(fake.backupArgsForCall)
 }
 
-func (fake *FakeDeployment) BackupArgsForCall(i int) executor.Executor {
+func (fake *FakeDeployment) BackupArgsForCall(i int) (context.Context, executor.Executor) {
 	fake.backupMutex.RLock()
 	defer fake.backupMutex.RUnlock()
-	return fake.backupArgsForCall[i].arg1
+	return fake.backupArgsForCall[i].arg1, fake.backupArgsForCall[i].arg2
 }
 
 func (fake *FakeDeployment) BackupReturns(result1 error) {
@@ -520,18 +663,71 @@ func (fake *FakeDeployment) BackupReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeDeployment) PostBackupUnlock(arg1 bool, arg2 orchestrator.LockOrderer, arg3 executor.Executor) error {
+func (fake *FakeDeployment) PostBackupVerify(arg1 context.Context, arg2 executor.Executor) ([]orchestrator.VerifyResult, error) {
+	fake.postBackupVerifyMutex.Lock()
+	ret, specificReturn := fake.postBackupVerifyReturnsOnCall[len(fake.postBackupVerifyArgsForCall)]
+	fake.postBackupVerifyArgsForCall = append(fake.postBackupVerifyArgsForCall, struct {
+		arg1 context.Context
+		arg2 executor.Executor
+	}{arg1, arg2})
+	fake.recordInvocation("PostBackupVerify", []interface{}{arg1, arg2})
+	fake.postBackupVerifyMutex.Unlock()
+	if fake.PostBackupVerifyStub != nil {
+		return fake.PostBackupVerifyStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.postBackupVerifyReturns.result1, fake.postBackupVerifyReturns.result2
+}
+
+func (fake *FakeDeployment) PostBackupVerifyCallCount() int {
+	fake.postBackupVerifyMutex.RLock()
+	defer fake.postBackupVerifyMutex.RUnlock()
+	return len(fake.postBackupVerifyArgsForCall)
+}
+
+func (fake *FakeDeployment) PostBackupVerifyArgsForCall(i int) (context.Context, executor.Executor) {
+	fake.postBackupVerifyMutex.RLock()
+	defer fake.postBackupVerifyMutex.RUnlock()
+	return fake.postBackupVerifyArgsForCall[i].arg1, fake.postBackupVerifyArgsForCall[i].arg2
+}
+
+func (fake *FakeDeployment) PostBackupVerifyReturns(result1 []orchestrator.VerifyResult, result2 error) {
+	fake.PostBackupVerifyStub = nil
+	fake.postBackupVerifyReturns = struct {
+		result1 []orchestrator.VerifyResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployment) PostBackupVerifyReturnsOnCall(i int, result1 []orchestrator.VerifyResult, result2 error) {
+	fake.PostBackupVerifyStub = nil
+	if fake.postBackupVerifyReturnsOnCall == nil {
+		fake.postBackupVerifyReturnsOnCall = make(map[int]struct {
+			result1 []orchestrator.VerifyResult
+			result2 error
+		})
+	}
+	fake.postBackupVerifyReturnsOnCall[i] = struct {
+		result1 []orchestrator.VerifyResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployment) PostBackupUnlock(arg1 context.Context, arg2 bool, arg3 orchestrator.LockOrderer, arg4 executor.Executor) error {
 	fake.postBackupUnlockMutex.Lock()
 	ret, specificReturn := fake.postBackupUnlockReturnsOnCall[len(fake.postBackupUnlockArgsForCall)]
 	fake.postBackupUnlockArgsForCall = append(fake.postBackupUnlockArgsForCall, struct {
-		arg1 bool
-		arg2 orchestrator.LockOrderer
-		arg3 executor.Executor
-	}{arg1, arg2, arg3})
-	fake.recordInvocation("PostBackupUnlock", []interface{}{arg1, arg2, arg3})
+		arg1 context.Context
+		arg2 bool
+		arg3 orchestrator.LockOrderer
+		arg4 executor.Executor
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("PostBackupUnlock", []interface{}{arg1, arg2, arg3, arg4})
 	fake.postBackupUnlockMutex.Unlock()
 	if fake.PostBackupUnlockStub != nil {
-		return fake.PostBackupUnlockStub(arg1, arg2, arg3)
+		return fake.PostBackupUnlockStub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -545,10 +741,10 @@ func (fake *FakeDeployment) PostBackupUnlockCallCount() int {
 	return len(fake.postBackupUnlockArgsForCall)
 }
 
-func (fake *FakeDeployment) PostBackupUnlockArgsForCall(i int) (bool, orchestrator.LockOrderer, executor.Executor) {
+func (fake *FakeDeployment) PostBackupUnlockArgsForCall(i int) (context.Context, bool, orchestrator.LockOrderer, executor.Executor) {
 	fake.postBackupUnlockMutex.RLock()
 	defer fake.postBackupUnlockMutex.RUnlock()
-	return fake.postBackupUnlockArgsForCall[i].arg1, fake.postBackupUnlockArgsForCall[i].arg2, fake.postBackupUnlockArgsForCall[i].arg3
+	return fake.postBackupUnlockArgsForCall[i].arg1, fake.postBackupUnlockArgsForCall[i].arg2, fake.postBackupUnlockArgsForCall[i].arg3, fake.postBackupUnlockArgsForCall[i].arg4
 }
 
 func (fake *FakeDeployment) PostBackupUnlockReturns(result1 error) {
@@ -570,14 +766,16 @@ func (fake *FakeDeployment) PostBackupUnlockReturnsOnCall(i int, result1 error)
 	}{result1}
 }
 
-func (fake *FakeDeployment) Restore() error {
+func (fake *FakeDeployment) Restore(arg1 context.Context) error {
 	fake.restoreMutex.Lock()
 	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
-	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct{}{})
-	fake.recordInvocation("Restore", []interface{}{})
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Restore", []interface{}{arg1})
 	fake.restoreMutex.Unlock()
 	if fake.RestoreStub != nil {
-		return fake.RestoreStub()
+		return fake.RestoreStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -591,6 +789,12 @@ func (fake *FakeDeployment) RestoreCallCount() int {
 	return len(fake.restoreArgsForCall)
 }
 
+func (fake *FakeDeployment) RestoreArgsForCall(i int) context.Context {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return fake.restoreArgsForCall[i].arg1
+}
+
 func (fake *FakeDeployment) RestoreReturns(result1 error) {
 	fake.RestoreStub = nil
 	fake.restoreReturns = struct {
@@ -770,17 +974,18 @@ func (fake *FakeDeployment) CustomArtifactNamesMatchReturnsOnCall(i int, result1
 	}{result1}
 }
 
-func (fake *FakeDeployment) PreRestoreLock(arg1 orchestrator.LockOrderer, arg2 executor.Executor) error {
+func (fake *FakeDeployment) PreRestoreLock(arg1 context.Context, arg2 orchestrator.LockOrderer, arg3 executor.Executor) error {
 	fake.preRestoreLockMutex.Lock()
 	ret, specificReturn := fake.preRestoreLockReturnsOnCall[len(fake.preRestoreLockArgsForCall)]
 	fake.preRestoreLockArgsForCall = append(fake.preRestoreLockArgsForCall, struct {
-		arg1 orchestrator.LockOrderer
-		arg2 executor.Executor
-	}{arg1, arg2})
-	fake.recordInvocation("PreRestoreLock", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 orchestrator.LockOrderer
+		arg3 executor.Executor
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("PreRestoreLock", []interface{}{arg1, arg2, arg3})
 	fake.preRestoreLockMutex.Unlock()
 	if fake.PreRestoreLockStub != nil {
-		return fake.PreRestoreLockStub(arg1, arg2)
+		return fake.PreRestoreLockStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -794,10 +999,10 @@ func (fake *FakeDeployment) PreRestoreLockCallCount() int {
 	return len(fake.preRestoreLockArgsForCall)
 }
 
-func (fake *FakeDeployment) PreRestoreLockArgsForCall(i int) (orchestrator.LockOrderer, executor.Executor) {
+func (fake *FakeDeployment) PreRestoreLockArgsForCall(i int) (context.Context, orchestrator.LockOrderer, executor.Executor) {
 	fake.preRestoreLockMutex.RLock()
 	defer fake.preRestoreLockMutex.RUnlock()
-	return fake.preRestoreLockArgsForCall[i].arg1, fake.preRestoreLockArgsForCall[i].arg2
+	return fake.preRestoreLockArgsForCall[i].arg1, fake.preRestoreLockArgsForCall[i].arg2, fake.preRestoreLockArgsForCall[i].arg3
 }
 
 func (fake *FakeDeployment) PreRestoreLockReturns(result1 error) {
@@ -819,17 +1024,18 @@ func (fake *FakeDeployment) PreRestoreLockReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeDeployment) PostRestoreUnlock(arg1 orchestrator.LockOrderer, arg2 executor.Executor) error {
+func (fake *FakeDeployment) PostRestoreUnlock(arg1 context.Context, arg2 orchestrator.LockOrderer, arg3 executor.Executor) error {
 	fake.postRestoreUnlockMutex.Lock()
 	ret, specificReturn := fake.postRestoreUnlockReturnsOnCall[len(fake.postRestoreUnlockArgsForCall)]
 	fake.postRestoreUnlockArgsForCall = append(fake.postRestoreUnlockArgsForCall, struct {
-		arg1 orchestrator.LockOrderer
-		arg2 executor.Executor
-	}{arg1, arg2})
-	fake.recordInvocation("PostRestoreUnlock", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 orchestrator.LockOrderer
+		arg3 executor.Executor
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("PostRestoreUnlock", []interface{}{arg1, arg2, arg3})
 	fake.postRestoreUnlockMutex.Unlock()
 	if fake.PostRestoreUnlockStub != nil {
-		return fake.PostRestoreUnlockStub(arg1, arg2)
+		return fake.PostRestoreUnlockStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -843,10 +1049,10 @@ func (fake *FakeDeployment) PostRestoreUnlockCallCount() int {
 	return len(fake.postRestoreUnlockArgsForCall)
 }
 
-func (fake *FakeDeployment) PostRestoreUnlockArgsForCall(i int) (orchestrator.LockOrderer, executor.Executor) {
+func (fake *FakeDeployment) PostRestoreUnlockArgsForCall(i int) (context.Context, orchestrator.LockOrderer, executor.Executor) {
 	fake.postRestoreUnlockMutex.RLock()
 	defer fake.postRestoreUnlockMutex.RUnlock()
-	return fake.postRestoreUnlockArgsForCall[i].arg1, fake.postRestoreUnlockArgsForCall[i].arg2
+	return fake.postRestoreUnlockArgsForCall[i].arg1, fake.postRestoreUnlockArgsForCall[i].arg2, fake.postRestoreUnlockArgsForCall[i].arg3
 }
 
 func (fake *FakeDeployment) PostRestoreUnlockReturns(result1 error) {
@@ -916,9 +1122,51 @@ func (fake *FakeDeployment) ValidateLockingDependenciesReturnsOnCall(i int, resu
 	}{result1}
 }
 
+func (fake *FakeDeployment) LockedJobs() []orchestrator.Job {
+	fake.lockedJobsMutex.Lock()
+	ret, specificReturn := fake.lockedJobsReturnsOnCall[len(fake.lockedJobsArgsForCall)]
+	fake.lockedJobsArgsForCall = append(fake.lockedJobsArgsForCall, struct{}{})
+	fake.recordInvocation("LockedJobs", []interface{}{})
+	fake.lockedJobsMutex.Unlock()
+	if fake.LockedJobsStub != nil {
+		return fake.LockedJobsStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.lockedJobsReturns.result1
+}
+
+func (fake *FakeDeployment) LockedJobsCallCount() int {
+	fake.lockedJobsMutex.RLock()
+	defer fake.lockedJobsMutex.RUnlock()
+	return len(fake.lockedJobsArgsForCall)
+}
+
+func (fake *FakeDeployment) LockedJobsReturns(result1 []orchestrator.Job) {
+	fake.LockedJobsStub = nil
+	fake.lockedJobsReturns = struct {
+		result1 []orchestrator.Job
+	}{result1}
+}
+
+func (fake *FakeDeployment) LockedJobsReturnsOnCall(i int, result1 []orchestrator.Job) {
+	fake.LockedJobsStub = nil
+	if fake.lockedJobsReturnsOnCall == nil {
+		fake.lockedJobsReturnsOnCall = make(map[int]struct {
+			result1 []orchestrator.Job
+		})
+	}
+	fake.lockedJobsReturnsOnCall[i] = struct {
+		result1 []orchestrator.Job
+	}{result1}
+}
+
 func (fake *FakeDeployment) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.nameMutex.RLock()
+	defer fake.nameMutex.RUnlock()
 	fake.isBackupableMutex.RLock()
 	defer fake.isBackupableMutex.RUnlock()
 	fake.backupableInstancesMutex.RLock()
@@ -931,10 +1179,14 @@ func (fake *FakeDeployment) Invocations() map[string][][]interface{} {
 	defer fake.isRestorableMutex.RUnlock()
 	fake.restorableInstancesMutex.RLock()
 	defer fake.restorableInstancesMutex.RUnlock()
+	fake.preBackupCheckMutex.RLock()
+	defer fake.preBackupCheckMutex.RUnlock()
 	fake.preBackupLockMutex.RLock()
 	defer fake.preBackupLockMutex.RUnlock()
 	fake.backupMutex.RLock()
 	defer fake.backupMutex.RUnlock()
+	fake.postBackupVerifyMutex.RLock()
+	defer fake.postBackupVerifyMutex.RUnlock()
 	fake.postBackupUnlockMutex.RLock()
 	defer fake.postBackupUnlockMutex.RUnlock()
 	fake.restoreMutex.RLock()
@@ -953,6 +1205,8 @@ func (fake *FakeDeployment) Invocations() map[string][][]interface{} {
 	defer fake.postRestoreUnlockMutex.RUnlock()
 	fake.validateLockingDependenciesMutex.RLock()
 	defer fake.validateLockingDependenciesMutex.RUnlock()
+	fake.lockedJobsMutex.RLock()
+	defer fake.lockedJobsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
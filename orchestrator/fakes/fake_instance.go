@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
@@ -67,6 +68,9 @@ type FakeInstance struct {
 	MarkArtifactDirCreatedStub        func()
 	markArtifactDirCreatedMutex       sync.RWMutex
 	markArtifactDirCreatedArgsForCall []struct{}
+	KeepArtifactDirStub               func()
+	keepArtifactDirMutex              sync.RWMutex
+	keepArtifactDirArgsForCall        []struct{}
 	IsRestorableStub                  func() bool
 	isRestorableMutex                 sync.RWMutex
 	isRestorableArgsForCall           []struct{}
@@ -76,19 +80,23 @@ type FakeInstance struct {
 	isRestorableReturnsOnCall map[int]struct {
 		result1 bool
 	}
-	BackupStub        func() error
+	BackupStub        func(context.Context) error
 	backupMutex       sync.RWMutex
-	backupArgsForCall []struct{}
-	backupReturns     struct {
+	backupArgsForCall []struct {
+		arg1 context.Context
+	}
+	backupReturns struct {
 		result1 error
 	}
 	backupReturnsOnCall map[int]struct {
 		result1 error
 	}
-	RestoreStub        func() error
+	RestoreStub        func(context.Context) error
 	restoreMutex       sync.RWMutex
-	restoreArgsForCall []struct{}
-	restoreReturns     struct {
+	restoreArgsForCall []struct {
+		arg1 context.Context
+	}
+	restoreReturns struct {
 		result1 error
 	}
 	restoreReturnsOnCall map[int]struct {
@@ -112,6 +120,26 @@ type FakeInstance struct {
 	cleanupPreviousReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SSHUserStub        func() string
+	sSHUserMutex       sync.RWMutex
+	sSHUserArgsForCall []struct{}
+	sSHUserReturns     struct {
+		result1 string
+	}
+	sSHUserReturnsOnCall map[int]struct {
+		result1 string
+	}
+	RemoveSSHUserStub        func(username string) error
+	removeSSHUserMutex       sync.RWMutex
+	removeSSHUserArgsForCall []struct {
+		username string
+	}
+	removeSSHUserReturns struct {
+		result1 error
+	}
+	removeSSHUserReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ArtifactsToBackupStub        func() []orchestrator.BackupArtifact
 	artifactsToBackupMutex       sync.RWMutex
 	artifactsToBackupArgsForCall []struct{}
@@ -420,6 +448,22 @@ func (fake *FakeInstance) MarkArtifactDirCreatedCallCount() int {
 	return len(fake.markArtifactDirCreatedArgsForCall)
 }
 
+func (fake *FakeInstance) KeepArtifactDir() {
+	fake.keepArtifactDirMutex.Lock()
+	fake.keepArtifactDirArgsForCall = append(fake.keepArtifactDirArgsForCall, struct{}{})
+	fake.recordInvocation("KeepArtifactDir", []interface{}{})
+	fake.keepArtifactDirMutex.Unlock()
+	if fake.KeepArtifactDirStub != nil {
+		fake.KeepArtifactDirStub()
+	}
+}
+
+func (fake *FakeInstance) KeepArtifactDirCallCount() int {
+	fake.keepArtifactDirMutex.RLock()
+	defer fake.keepArtifactDirMutex.RUnlock()
+	return len(fake.keepArtifactDirArgsForCall)
+}
+
 func (fake *FakeInstance) IsRestorable() bool {
 	fake.isRestorableMutex.Lock()
 	ret, specificReturn := fake.isRestorableReturnsOnCall[len(fake.isRestorableArgsForCall)]
@@ -460,14 +504,16 @@ func (fake *FakeInstance) IsRestorableReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
-func (fake *FakeInstance) Backup() error {
+func (fake *FakeInstance) Backup(arg1 context.Context) error {
 	fake.backupMutex.Lock()
 	ret, specificReturn := fake.backupReturnsOnCall[len(fake.backupArgsForCall)]
-	fake.backupArgsForCall = append(fake.backupArgsForCall, struct{}{})
-	fake.recordInvocation("Backup", []interface{}{})
+	fake.backupArgsForCall = append(fake.backupArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Backup", []interface{}{arg1})
 	fake.backupMutex.Unlock()
 	if fake.BackupStub != nil {
-		return fake.BackupStub()
+		return fake.BackupStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -481,6 +527,12 @@ func (fake *FakeInstance) BackupCallCount() int {
 	return len(fake.backupArgsForCall)
 }
 
+func (fake *FakeInstance) BackupArgsForCall(i int) context.Context {
+	fake.backupMutex.RLock()
+	defer fake.backupMutex.RUnlock()
+	return fake.backupArgsForCall[i].arg1
+}
+
 func (fake *FakeInstance) BackupReturns(result1 error) {
 	fake.BackupStub = nil
 	fake.backupReturns = struct {
@@ -500,14 +552,16 @@ func (fake *FakeInstance) BackupReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeInstance) Restore() error {
+func (fake *FakeInstance) Restore(arg1 context.Context) error {
 	fake.restoreMutex.Lock()
 	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
-	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct{}{})
-	fake.recordInvocation("Restore", []interface{}{})
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Restore", []interface{}{arg1})
 	fake.restoreMutex.Unlock()
 	if fake.RestoreStub != nil {
-		return fake.RestoreStub()
+		return fake.RestoreStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -521,6 +575,12 @@ func (fake *FakeInstance) RestoreCallCount() int {
 	return len(fake.restoreArgsForCall)
 }
 
+func (fake *FakeInstance) RestoreArgsForCall(i int) context.Context {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return fake.restoreArgsForCall[i].arg1
+}
+
 func (fake *FakeInstance) RestoreReturns(result1 error) {
 	fake.RestoreStub = nil
 	fake.restoreReturns = struct {
@@ -620,6 +680,94 @@ func (fake *FakeInstance) CleanupPreviousReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeInstance) SSHUser() string {
+	fake.sSHUserMutex.Lock()
+	ret, specificReturn := fake.sSHUserReturnsOnCall[len(fake.sSHUserArgsForCall)]
+	fake.sSHUserArgsForCall = append(fake.sSHUserArgsForCall, struct{}{})
+	fake.recordInvocation("SSHUser", []interface{}{})
+	fake.sSHUserMutex.Unlock()
+	if fake.SSHUserStub != nil {
+		return fake.SSHUserStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.sSHUserReturns.result1
+}
+
+func (fake *FakeInstance) SSHUserCallCount() int {
+	fake.sSHUserMutex.RLock()
+	defer fake.sSHUserMutex.RUnlock()
+	return len(fake.sSHUserArgsForCall)
+}
+
+func (fake *FakeInstance) SSHUserReturns(result1 string) {
+	fake.SSHUserStub = nil
+	fake.sSHUserReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeInstance) SSHUserReturnsOnCall(i int, result1 string) {
+	fake.SSHUserStub = nil
+	if fake.sSHUserReturnsOnCall == nil {
+		fake.sSHUserReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.sSHUserReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeInstance) RemoveSSHUser(username string) error {
+	fake.removeSSHUserMutex.Lock()
+	ret, specificReturn := fake.removeSSHUserReturnsOnCall[len(fake.removeSSHUserArgsForCall)]
+	fake.removeSSHUserArgsForCall = append(fake.removeSSHUserArgsForCall, struct {
+		username string
+	}{username})
+	fake.recordInvocation("RemoveSSHUser", []interface{}{username})
+	fake.removeSSHUserMutex.Unlock()
+	if fake.RemoveSSHUserStub != nil {
+		return fake.RemoveSSHUserStub(username)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.removeSSHUserReturns.result1
+}
+
+func (fake *FakeInstance) RemoveSSHUserCallCount() int {
+	fake.removeSSHUserMutex.RLock()
+	defer fake.removeSSHUserMutex.RUnlock()
+	return len(fake.removeSSHUserArgsForCall)
+}
+
+func (fake *FakeInstance) RemoveSSHUserArgsForCall(i int) string {
+	fake.removeSSHUserMutex.RLock()
+	defer fake.removeSSHUserMutex.RUnlock()
+	return fake.removeSSHUserArgsForCall[i].username
+}
+
+func (fake *FakeInstance) RemoveSSHUserReturns(result1 error) {
+	fake.RemoveSSHUserStub = nil
+	fake.removeSSHUserReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeInstance) RemoveSSHUserReturnsOnCall(i int, result1 error) {
+	fake.RemoveSSHUserStub = nil
+	if fake.removeSSHUserReturnsOnCall == nil {
+		fake.removeSSHUserReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeSSHUserReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeInstance) ArtifactsToBackup() []orchestrator.BackupArtifact {
 	fake.artifactsToBackupMutex.Lock()
 	ret, specificReturn := fake.artifactsToBackupReturnsOnCall[len(fake.artifactsToBackupArgsForCall)]
@@ -837,6 +985,8 @@ func (fake *FakeInstance) Invocations() map[string][][]interface{} {
 	defer fake.artifactDirCreatedMutex.RUnlock()
 	fake.markArtifactDirCreatedMutex.RLock()
 	defer fake.markArtifactDirCreatedMutex.RUnlock()
+	fake.keepArtifactDirMutex.RLock()
+	defer fake.keepArtifactDirMutex.RUnlock()
 	fake.isRestorableMutex.RLock()
 	defer fake.isRestorableMutex.RUnlock()
 	fake.backupMutex.RLock()
@@ -847,6 +997,10 @@ func (fake *FakeInstance) Invocations() map[string][][]interface{} {
 	defer fake.cleanupMutex.RUnlock()
 	fake.cleanupPreviousMutex.RLock()
 	defer fake.cleanupPreviousMutex.RUnlock()
+	fake.sSHUserMutex.RLock()
+	defer fake.sSHUserMutex.RUnlock()
+	fake.removeSSHUserMutex.RLock()
+	defer fake.removeSSHUserMutex.RUnlock()
 	fake.artifactsToBackupMutex.RLock()
 	defer fake.artifactsToBackupMutex.RUnlock()
 	fake.artifactsToRestoreMutex.RLock()
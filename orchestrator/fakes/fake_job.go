@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
@@ -44,6 +45,15 @@ type FakeJob struct {
 	hasNamedRestoreArtifactReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	HasPostBackupVerifyStub        func() bool
+	hasPostBackupVerifyMutex       sync.RWMutex
+	hasPostBackupVerifyArgsForCall []struct{}
+	hasPostBackupVerifyReturns     struct {
+		result1 bool
+	}
+	hasPostBackupVerifyReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	BackupArtifactNameStub        func() string
 	backupArtifactNameMutex       sync.RWMutex
 	backupArtifactNameArgsForCall []struct{}
@@ -62,27 +72,54 @@ type FakeJob struct {
 	restoreArtifactNameReturnsOnCall map[int]struct {
 		result1 string
 	}
-	BackupStub        func() error
+	BackupStub        func(ctx context.Context) error
 	backupMutex       sync.RWMutex
-	backupArgsForCall []struct{}
-	backupReturns     struct {
+	backupArgsForCall []struct {
+		ctx context.Context
+	}
+	backupReturns struct {
 		result1 error
 	}
 	backupReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PreBackupLockStub        func() error
+	PostBackupVerifyStub        func(ctx context.Context) error
+	postBackupVerifyMutex       sync.RWMutex
+	postBackupVerifyArgsForCall []struct {
+		ctx context.Context
+	}
+	postBackupVerifyReturns struct {
+		result1 error
+	}
+	postBackupVerifyReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PreBackupCheckStub        func(ctx context.Context) error
+	preBackupCheckMutex       sync.RWMutex
+	preBackupCheckArgsForCall []struct {
+		ctx context.Context
+	}
+	preBackupCheckReturns struct {
+		result1 error
+	}
+	preBackupCheckReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PreBackupLockStub        func(ctx context.Context) error
 	preBackupLockMutex       sync.RWMutex
-	preBackupLockArgsForCall []struct{}
-	preBackupLockReturns     struct {
+	preBackupLockArgsForCall []struct {
+		ctx context.Context
+	}
+	preBackupLockReturns struct {
 		result1 error
 	}
 	preBackupLockReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PostBackupUnlockStub        func(afterSuccessfulBackup bool) error
+	PostBackupUnlockStub        func(ctx context.Context, afterSuccessfulBackup bool) error
 	postBackupUnlockMutex       sync.RWMutex
 	postBackupUnlockArgsForCall []struct {
+		ctx                   context.Context
 		afterSuccessfulBackup bool
 	}
 	postBackupUnlockReturns struct {
@@ -91,28 +128,34 @@ type FakeJob struct {
 	postBackupUnlockReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PreRestoreLockStub        func() error
+	PreRestoreLockStub        func(ctx context.Context) error
 	preRestoreLockMutex       sync.RWMutex
-	preRestoreLockArgsForCall []struct{}
-	preRestoreLockReturns     struct {
+	preRestoreLockArgsForCall []struct {
+		ctx context.Context
+	}
+	preRestoreLockReturns struct {
 		result1 error
 	}
 	preRestoreLockReturnsOnCall map[int]struct {
 		result1 error
 	}
-	RestoreStub        func() error
+	RestoreStub        func(ctx context.Context) error
 	restoreMutex       sync.RWMutex
-	restoreArgsForCall []struct{}
-	restoreReturns     struct {
+	restoreArgsForCall []struct {
+		ctx context.Context
+	}
+	restoreReturns struct {
 		result1 error
 	}
 	restoreReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PostRestoreUnlockStub        func() error
+	PostRestoreUnlockStub        func(ctx context.Context) error
 	postRestoreUnlockMutex       sync.RWMutex
-	postRestoreUnlockArgsForCall []struct{}
-	postRestoreUnlockReturns     struct {
+	postRestoreUnlockArgsForCall []struct {
+		ctx context.Context
+	}
+	postRestoreUnlockReturns struct {
 		result1 error
 	}
 	postRestoreUnlockReturnsOnCall map[int]struct {
@@ -345,6 +388,46 @@ func (fake *FakeJob) HasNamedRestoreArtifactReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeJob) HasPostBackupVerify() bool {
+	fake.hasPostBackupVerifyMutex.Lock()
+	ret, specificReturn := fake.hasPostBackupVerifyReturnsOnCall[len(fake.hasPostBackupVerifyArgsForCall)]
+	fake.hasPostBackupVerifyArgsForCall = append(fake.hasPostBackupVerifyArgsForCall, struct{}{})
+	fake.recordInvocation("HasPostBackupVerify", []interface{}{})
+	fake.hasPostBackupVerifyMutex.Unlock()
+	if fake.HasPostBackupVerifyStub != nil {
+		return fake.HasPostBackupVerifyStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.hasPostBackupVerifyReturns.result1
+}
+
+func (fake *FakeJob) HasPostBackupVerifyCallCount() int {
+	fake.hasPostBackupVerifyMutex.RLock()
+	defer fake.hasPostBackupVerifyMutex.RUnlock()
+	return len(fake.hasPostBackupVerifyArgsForCall)
+}
+
+func (fake *FakeJob) HasPostBackupVerifyReturns(result1 bool) {
+	fake.HasPostBackupVerifyStub = nil
+	fake.hasPostBackupVerifyReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeJob) HasPostBackupVerifyReturnsOnCall(i int, result1 bool) {
+	fake.HasPostBackupVerifyStub = nil
+	if fake.hasPostBackupVerifyReturnsOnCall == nil {
+		fake.hasPostBackupVerifyReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.hasPostBackupVerifyReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeJob) BackupArtifactName() string {
 	fake.backupArtifactNameMutex.Lock()
 	ret, specificReturn := fake.backupArtifactNameReturnsOnCall[len(fake.backupArtifactNameArgsForCall)]
@@ -425,14 +508,16 @@ func (fake *FakeJob) RestoreArtifactNameReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
-func (fake *FakeJob) Backup() error {
+func (fake *FakeJob) Backup(ctx context.Context) error {
 	fake.backupMutex.Lock()
 	ret, specificReturn := fake.backupReturnsOnCall[len(fake.backupArgsForCall)]
-	fake.backupArgsForCall = append(fake.backupArgsForCall, struct{}{})
-	fake.recordInvocation("Backup", []interface{}{})
+	fake.backupArgsForCall = append(fake.backupArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("Backup", []interface{}{ctx})
 	fake.backupMutex.Unlock()
 	if fake.BackupStub != nil {
-		return fake.BackupStub()
+		return fake.BackupStub(ctx)
 	}
 	if specificReturn {
 		return ret.result1
@@ -446,6 +531,12 @@ func (fake *FakeJob) BackupCallCount() int {
 	return len(fake.backupArgsForCall)
 }
 
+func (fake *FakeJob) BackupArgsForCall(i int) context.Context {
+	fake.backupMutex.RLock()
+	defer fake.backupMutex.RUnlock()
+	return fake.backupArgsForCall[i].ctx
+}
+
 func (fake *FakeJob) BackupReturns(result1 error) {
 	fake.BackupStub = nil
 	fake.backupReturns = struct {
@@ -465,14 +556,112 @@ func (fake *FakeJob) BackupReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeJob) PreBackupLock() error {
+func (fake *FakeJob) PostBackupVerify(ctx context.Context) error {
+	fake.postBackupVerifyMutex.Lock()
+	ret, specificReturn := fake.postBackupVerifyReturnsOnCall[len(fake.postBackupVerifyArgsForCall)]
+	fake.postBackupVerifyArgsForCall = append(fake.postBackupVerifyArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("PostBackupVerify", []interface{}{ctx})
+	fake.postBackupVerifyMutex.Unlock()
+	if fake.PostBackupVerifyStub != nil {
+		return fake.PostBackupVerifyStub(ctx)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.postBackupVerifyReturns.result1
+}
+
+func (fake *FakeJob) PostBackupVerifyCallCount() int {
+	fake.postBackupVerifyMutex.RLock()
+	defer fake.postBackupVerifyMutex.RUnlock()
+	return len(fake.postBackupVerifyArgsForCall)
+}
+
+func (fake *FakeJob) PostBackupVerifyArgsForCall(i int) context.Context {
+	fake.postBackupVerifyMutex.RLock()
+	defer fake.postBackupVerifyMutex.RUnlock()
+	return fake.postBackupVerifyArgsForCall[i].ctx
+}
+
+func (fake *FakeJob) PostBackupVerifyReturns(result1 error) {
+	fake.PostBackupVerifyStub = nil
+	fake.postBackupVerifyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeJob) PostBackupVerifyReturnsOnCall(i int, result1 error) {
+	fake.PostBackupVerifyStub = nil
+	if fake.postBackupVerifyReturnsOnCall == nil {
+		fake.postBackupVerifyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.postBackupVerifyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeJob) PreBackupCheck(ctx context.Context) error {
+	fake.preBackupCheckMutex.Lock()
+	ret, specificReturn := fake.preBackupCheckReturnsOnCall[len(fake.preBackupCheckArgsForCall)]
+	fake.preBackupCheckArgsForCall = append(fake.preBackupCheckArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("PreBackupCheck", []interface{}{ctx})
+	fake.preBackupCheckMutex.Unlock()
+	if fake.PreBackupCheckStub != nil {
+		return fake.PreBackupCheckStub(ctx)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.preBackupCheckReturns.result1
+}
+
+func (fake *FakeJob) PreBackupCheckCallCount() int {
+	fake.preBackupCheckMutex.RLock()
+	defer fake.preBackupCheckMutex.RUnlock()
+	return len(fake.preBackupCheckArgsForCall)
+}
+
+func (fake *FakeJob) PreBackupCheckArgsForCall(i int) context.Context {
+	fake.preBackupCheckMutex.RLock()
+	defer fake.preBackupCheckMutex.RUnlock()
+	return fake.preBackupCheckArgsForCall[i].ctx
+}
+
+func (fake *FakeJob) PreBackupCheckReturns(result1 error) {
+	fake.PreBackupCheckStub = nil
+	fake.preBackupCheckReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeJob) PreBackupCheckReturnsOnCall(i int, result1 error) {
+	fake.PreBackupCheckStub = nil
+	if fake.preBackupCheckReturnsOnCall == nil {
+		fake.preBackupCheckReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.preBackupCheckReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeJob) PreBackupLock(ctx context.Context) error {
 	fake.preBackupLockMutex.Lock()
 	ret, specificReturn := fake.preBackupLockReturnsOnCall[len(fake.preBackupLockArgsForCall)]
-	fake.preBackupLockArgsForCall = append(fake.preBackupLockArgsForCall, struct{}{})
-	fake.recordInvocation("PreBackupLock", []interface{}{})
+	fake.preBackupLockArgsForCall = append(fake.preBackupLockArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("PreBackupLock", []interface{}{ctx})
 	fake.preBackupLockMutex.Unlock()
 	if fake.PreBackupLockStub != nil {
-		return fake.PreBackupLockStub()
+		return fake.PreBackupLockStub(ctx)
 	}
 	if specificReturn {
 		return ret.result1
@@ -486,6 +675,12 @@ func (fake *FakeJob) PreBackupLockCallCount() int {
 	return len(fake.preBackupLockArgsForCall)
 }
 
+func (fake *FakeJob) PreBackupLockArgsForCall(i int) context.Context {
+	fake.preBackupLockMutex.RLock()
+	defer fake.preBackupLockMutex.RUnlock()
+	return fake.preBackupLockArgsForCall[i].ctx
+}
+
 func (fake *FakeJob) PreBackupLockReturns(result1 error) {
 	fake.PreBackupLockStub = nil
 	fake.preBackupLockReturns = struct {
@@ -505,16 +700,17 @@ func (fake *FakeJob) PreBackupLockReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeJob) PostBackupUnlock(afterSuccessfulBackup bool) error {
+func (fake *FakeJob) PostBackupUnlock(ctx context.Context, afterSuccessfulBackup bool) error {
 	fake.postBackupUnlockMutex.Lock()
 	ret, specificReturn := fake.postBackupUnlockReturnsOnCall[len(fake.postBackupUnlockArgsForCall)]
 	fake.postBackupUnlockArgsForCall = append(fake.postBackupUnlockArgsForCall, struct {
+		ctx                   context.Context
 		afterSuccessfulBackup bool
-	}{afterSuccessfulBackup})
-	fake.recordInvocation("PostBackupUnlock", []interface{}{afterSuccessfulBackup})
+	}{ctx, afterSuccessfulBackup})
+	fake.recordInvocation("PostBackupUnlock", []interface{}{ctx, afterSuccessfulBackup})
 	fake.postBackupUnlockMutex.Unlock()
 	if fake.PostBackupUnlockStub != nil {
-		return fake.PostBackupUnlockStub(afterSuccessfulBackup)
+		return fake.PostBackupUnlockStub(ctx, afterSuccessfulBackup)
 	}
 	if specificReturn {
 		return ret.result1
@@ -528,10 +724,10 @@ func (fake *FakeJob) PostBackupUnlockCallCount() int {
 	return len(fake.postBackupUnlockArgsForCall)
 }
 
-func (fake *FakeJob) PostBackupUnlockArgsForCall(i int) bool {
+func (fake *FakeJob) PostBackupUnlockArgsForCall(i int) (context.Context, bool) {
 	fake.postBackupUnlockMutex.RLock()
 	defer fake.postBackupUnlockMutex.RUnlock()
-	return fake.postBackupUnlockArgsForCall[i].afterSuccessfulBackup
+	return fake.postBackupUnlockArgsForCall[i].ctx, fake.postBackupUnlockArgsForCall[i].afterSuccessfulBackup
 }
 
 func (fake *FakeJob) PostBackupUnlockReturns(result1 error) {
@@ -553,14 +749,16 @@ func (fake *FakeJob) PostBackupUnlockReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeJob) PreRestoreLock() error {
+func (fake *FakeJob) PreRestoreLock(ctx context.Context) error {
 	fake.preRestoreLockMutex.Lock()
 	ret, specificReturn := fake.preRestoreLockReturnsOnCall[len(fake.preRestoreLockArgsForCall)]
-	fake.preRestoreLockArgsForCall = append(fake.preRestoreLockArgsForCall, struct{}{})
-	fake.recordInvocation("PreRestoreLock", []interface{}{})
+	fake.preRestoreLockArgsForCall = append(fake.preRestoreLockArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("PreRestoreLock", []interface{}{ctx})
 	fake.preRestoreLockMutex.Unlock()
 	if fake.PreRestoreLockStub != nil {
-		return fake.PreRestoreLockStub()
+		return fake.PreRestoreLockStub(ctx)
 	}
 	if specificReturn {
 		return ret.result1
@@ -574,6 +772,12 @@ func (fake *FakeJob) PreRestoreLockCallCount() int {
 	return len(fake.preRestoreLockArgsForCall)
 }
 
+func (fake *FakeJob) PreRestoreLockArgsForCall(i int) context.Context {
+	fake.preRestoreLockMutex.RLock()
+	defer fake.preRestoreLockMutex.RUnlock()
+	return fake.preRestoreLockArgsForCall[i].ctx
+}
+
 func (fake *FakeJob) PreRestoreLockReturns(result1 error) {
 	fake.PreRestoreLockStub = nil
 	fake.preRestoreLockReturns = struct {
@@ -593,14 +797,16 @@ func (fake *FakeJob) PreRestoreLockReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeJob) Restore() error {
+func (fake *FakeJob) Restore(ctx context.Context) error {
 	fake.restoreMutex.Lock()
 	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
-	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct{}{})
-	fake.recordInvocation("Restore", []interface{}{})
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("Restore", []interface{}{ctx})
 	fake.restoreMutex.Unlock()
 	if fake.RestoreStub != nil {
-		return fake.RestoreStub()
+		return fake.RestoreStub(ctx)
 	}
 	if specificReturn {
 		return ret.result1
@@ -614,6 +820,12 @@ func (fake *FakeJob) RestoreCallCount() int {
 	return len(fake.restoreArgsForCall)
 }
 
+func (fake *FakeJob) RestoreArgsForCall(i int) context.Context {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return fake.restoreArgsForCall[i].ctx
+}
+
 func (fake *FakeJob) RestoreReturns(result1 error) {
 	fake.RestoreStub = nil
 	fake.restoreReturns = struct {
@@ -633,14 +845,16 @@ func (fake *FakeJob) RestoreReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeJob) PostRestoreUnlock() error {
+func (fake *FakeJob) PostRestoreUnlock(ctx context.Context) error {
 	fake.postRestoreUnlockMutex.Lock()
 	ret, specificReturn := fake.postRestoreUnlockReturnsOnCall[len(fake.postRestoreUnlockArgsForCall)]
-	fake.postRestoreUnlockArgsForCall = append(fake.postRestoreUnlockArgsForCall, struct{}{})
-	fake.recordInvocation("PostRestoreUnlock", []interface{}{})
+	fake.postRestoreUnlockArgsForCall = append(fake.postRestoreUnlockArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("PostRestoreUnlock", []interface{}{ctx})
 	fake.postRestoreUnlockMutex.Unlock()
 	if fake.PostRestoreUnlockStub != nil {
-		return fake.PostRestoreUnlockStub()
+		return fake.PostRestoreUnlockStub(ctx)
 	}
 	if specificReturn {
 		return ret.result1
@@ -654,6 +868,12 @@ func (fake *FakeJob) PostRestoreUnlockCallCount() int {
 	return len(fake.postRestoreUnlockArgsForCall)
 }
 
+func (fake *FakeJob) PostRestoreUnlockArgsForCall(i int) context.Context {
+	fake.postRestoreUnlockMutex.RLock()
+	defer fake.postRestoreUnlockMutex.RUnlock()
+	return fake.postRestoreUnlockArgsForCall[i].ctx
+}
+
 func (fake *FakeJob) PostRestoreUnlockReturns(result1 error) {
 	fake.PostRestoreUnlockStub = nil
 	fake.postRestoreUnlockReturns = struct {
@@ -964,12 +1184,18 @@ func (fake *FakeJob) Invocations() map[string][][]interface{} {
 	defer fake.hasNamedBackupArtifactMutex.RUnlock()
 	fake.hasNamedRestoreArtifactMutex.RLock()
 	defer fake.hasNamedRestoreArtifactMutex.RUnlock()
+	fake.hasPostBackupVerifyMutex.RLock()
+	defer fake.hasPostBackupVerifyMutex.RUnlock()
 	fake.backupArtifactNameMutex.RLock()
 	defer fake.backupArtifactNameMutex.RUnlock()
 	fake.restoreArtifactNameMutex.RLock()
 	defer fake.restoreArtifactNameMutex.RUnlock()
 	fake.backupMutex.RLock()
 	defer fake.backupMutex.RUnlock()
+	fake.postBackupVerifyMutex.RLock()
+	defer fake.postBackupVerifyMutex.RUnlock()
+	fake.preBackupCheckMutex.RLock()
+	defer fake.preBackupCheckMutex.RUnlock()
 	fake.preBackupLockMutex.RLock()
 	defer fake.preBackupLockMutex.RUnlock()
 	fake.postBackupUnlockMutex.RLock()
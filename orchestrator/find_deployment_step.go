@@ -1,5 +1,7 @@
 package orchestrator
 
+import "context"
+
 type FindDeploymentStep struct {
 	deploymentManager DeploymentManager
 	logger            Logger
@@ -9,7 +11,7 @@ func NewFindDeploymentStep(deploymentManager DeploymentManager, logger Logger) S
 	return &FindDeploymentStep{deploymentManager: deploymentManager, logger: logger}
 }
 
-func (s *FindDeploymentStep) Run(session *Session) error {
+func (s *FindDeploymentStep) Run(ctx context.Context, session *Session) error {
 	s.logger.Info("bbr", "Looking for scripts")
 	deployment, err := s.deploymentManager.Find(session.DeploymentName())
 	if err != nil {
@@ -18,5 +20,12 @@ func (s *FindDeploymentStep) Run(session *Session) error {
 
 	session.SetCurrentDeployment(deployment)
 
+	if err := session.Journal().AddSSHUsers(deployment.Instances()); err != nil {
+		if cleanupErr := deployment.Cleanup(); cleanupErr != nil {
+			return NewError(err, cleanupErr)
+		}
+		return err
+	}
+
 	return nil
 }
@@ -1,7 +1,10 @@
 package orchestrator
 
 import (
+	"context"
 	"io"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/event"
 )
 
 type InstanceIdentifer interface {
@@ -17,11 +20,14 @@ type Instance interface {
 	ArtifactDirExists() (bool, error)
 	ArtifactDirCreated() bool
 	MarkArtifactDirCreated()
+	KeepArtifactDir()
 	IsRestorable() bool
-	Backup() error
-	Restore() error
+	Backup(context.Context) error
+	Restore(context.Context) error
 	Cleanup() error
 	CleanupPrevious() error
+	SSHUser() string
+	RemoveSSHUser(username string) error
 	ArtifactsToBackup() []BackupArtifact
 	ArtifactsToRestore() []BackupArtifact
 	CustomBackupArtifactNames() []string
@@ -35,14 +41,17 @@ type Job interface {
 	HasRestore() bool
 	HasNamedBackupArtifact() bool
 	HasNamedRestoreArtifact() bool
+	HasPostBackupVerify() bool
 	BackupArtifactName() string
 	RestoreArtifactName() string
-	Backup() error
-	PreBackupLock() error
-	PostBackupUnlock(afterSuccessfulBackup bool) error
-	PreRestoreLock() error
-	Restore() error
-	PostRestoreUnlock() error
+	Backup(ctx context.Context) error
+	PostBackupVerify(ctx context.Context) error
+	PreBackupCheck(ctx context.Context) error
+	PreBackupLock(ctx context.Context) error
+	PostBackupUnlock(ctx context.Context, afterSuccessfulBackup bool) error
+	PreRestoreLock(ctx context.Context) error
+	Restore(ctx context.Context) error
+	PostRestoreUnlock(ctx context.Context) error
 	Name() string
 	Release() string
 	InstanceIdentifier() string
@@ -163,9 +172,9 @@ func (is instances) CleanupPrevious() error {
 	return ConvertErrors(cleanupPreviousErrors)
 }
 
-func (is instances) Backup() error {
+func (is instances) Backup(ctx context.Context) error {
 	for _, instance := range is {
-		err := instance.Backup()
+		err := instance.Backup(ctx)
 		if err != nil {
 			return err
 		}
@@ -173,9 +182,15 @@ func (is instances) Backup() error {
 	return nil
 }
 
-func (is instances) Restore() error {
+func (is instances) Restore(ctx context.Context, deploymentName string) error {
 	for _, instance := range is {
-		err := instance.Restore()
+		err := instance.Restore(ctx)
+		event.Record(event.Event{
+			Type:       event.InstanceFinished,
+			Action:     "restore",
+			Deployment: deploymentName,
+			Instance:   instance.Name() + "/" + instance.ID(),
+		}.WithResult(err))
 		if err != nil {
 			return err
 		}
@@ -1,62 +1,130 @@
 package orchestrator
 
-import "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/event"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
+
+type JobPreBackupCheckExecutor struct {
+	Job
+	deploymentName string
+}
+
+func NewJobPreBackupCheckExecutable(job Job, deploymentName string) executor.Executable {
+	return JobPreBackupCheckExecutor{Job: job, deploymentName: deploymentName}
+}
+
+func (j JobPreBackupCheckExecutor) Execute(ctx context.Context) error {
+	err := j.PreBackupCheck(ctx)
+	recordJobFinished(j.Job, j.deploymentName, "pre-backup-check", err)
+	return err
+}
 
 type JobPreBackupLockExecutor struct {
 	Job
+	deploymentName string
+}
+
+func NewJobPreBackupLockExecutable(job Job, deploymentName string) executor.Executable {
+	return JobPreBackupLockExecutor{Job: job, deploymentName: deploymentName}
 }
 
-func NewJobPreBackupLockExecutable(job Job) executor.Executable {
-	return JobPreBackupLockExecutor{job}
+func (j JobPreBackupLockExecutor) Execute(ctx context.Context) error {
+	err := j.PreBackupLock(ctx)
+	recordJobFinished(j.Job, j.deploymentName, "pre-backup-lock", err)
+	return err
 }
 
-func (j JobPreBackupLockExecutor) Execute() error {
-	return j.PreBackupLock()
+// VerifyResult is the outcome of a job's post-backup-verify script
+type VerifyResult struct {
+	InstanceIdentifier string
+	JobName            string
+	Error              error
+}
+
+type JobPostBackupVerifyExecutor struct {
+	Job
+	deploymentName string
+	result         *VerifyResult
+}
+
+func NewJobPostBackupVerifyExecutable(job Job, deploymentName string, result *VerifyResult) executor.Executable {
+	return JobPostBackupVerifyExecutor{Job: job, deploymentName: deploymentName, result: result}
+}
+
+func (j JobPostBackupVerifyExecutor) Execute(ctx context.Context) error {
+	err := j.PostBackupVerify(ctx)
+	*j.result = VerifyResult{InstanceIdentifier: j.InstanceIdentifier(), JobName: j.Name(), Error: err}
+	recordJobFinished(j.Job, j.deploymentName, "post-backup-verify", err)
+	return err
 }
 
 type JobPostBackupUnlockExecutor struct {
 	Job
+	deploymentName        string
 	afterSuccessfulBackup bool
 }
 
-func NewJobPostSuccessfulBackupUnlockExecutable(job Job) executor.Executable {
+func NewJobPostSuccessfulBackupUnlockExecutable(job Job, deploymentName string) executor.Executable {
 	return JobPostBackupUnlockExecutor{
 		Job:                   job,
+		deploymentName:        deploymentName,
 		afterSuccessfulBackup: true,
 	}
 }
 
-func NewJobPostFailedBackupUnlockExecutable(job Job) executor.Executable {
+func NewJobPostFailedBackupUnlockExecutable(job Job, deploymentName string) executor.Executable {
 	return JobPostBackupUnlockExecutor{
 		Job:                   job,
+		deploymentName:        deploymentName,
 		afterSuccessfulBackup: false,
 	}
 }
 
-func (j JobPostBackupUnlockExecutor) Execute() error {
-	return j.PostBackupUnlock(j.afterSuccessfulBackup)
+func (j JobPostBackupUnlockExecutor) Execute(ctx context.Context) error {
+	err := j.PostBackupUnlock(ctx, j.afterSuccessfulBackup)
+	recordJobFinished(j.Job, j.deploymentName, "post-backup-unlock", err)
+	return err
 }
 
 type JobPreRestoreLockExecutor struct {
 	Job
+	deploymentName string
 }
 
-func NewJobPreRestoreLockExecutable(job Job) executor.Executable {
-	return JobPreRestoreLockExecutor{job}
+func NewJobPreRestoreLockExecutable(job Job, deploymentName string) executor.Executable {
+	return JobPreRestoreLockExecutor{Job: job, deploymentName: deploymentName}
 }
 
-func (j JobPreRestoreLockExecutor) Execute() error {
-	return j.PreRestoreLock()
+func (j JobPreRestoreLockExecutor) Execute(ctx context.Context) error {
+	err := j.PreRestoreLock(ctx)
+	recordJobFinished(j.Job, j.deploymentName, "pre-restore-lock", err)
+	return err
 }
 
 type JobPostRestoreUnlockExecutor struct {
 	Job
+	deploymentName string
+}
+
+func NewJobPostRestoreUnlockExecutable(job Job, deploymentName string) executor.Executable {
+	return JobPostRestoreUnlockExecutor{Job: job, deploymentName: deploymentName}
 }
 
-func NewJobPostRestoreUnlockExecutable(job Job) executor.Executable {
-	return JobPostRestoreUnlockExecutor{job}
+func (j JobPostRestoreUnlockExecutor) Execute(ctx context.Context) error {
+	err := j.PostRestoreUnlock(ctx)
+	recordJobFinished(j.Job, j.deploymentName, "post-restore-unlock", err)
+	return err
 }
 
-func (j JobPostRestoreUnlockExecutor) Execute() error {
-	return j.PostRestoreUnlock()
+func recordJobFinished(job Job, deploymentName, action string, err error) {
+	event.Record(event.Event{
+		Type:       event.JobFinished,
+		Action:     action,
+		Deployment: deploymentName,
+		Instance:   job.InstanceIdentifier(),
+		Job:        job.Name(),
+	}.WithResult(err))
 }
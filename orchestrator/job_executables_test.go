@@ -1,7 +1,11 @@
 package orchestrator_test
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/event"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
@@ -22,10 +26,10 @@ var _ = Describe("JobExecutables", func() {
 
 	Context("JobPreBackupLockExecutor", func() {
 		BeforeEach(func() {
-			executable = orchestrator.NewJobPreBackupLockExecutable(fakeJob)
+			executable = orchestrator.NewJobPreBackupLockExecutable(fakeJob, "my-deployment")
 		})
 		JustBeforeEach(func() {
-			err = executable.Execute()
+			err = executable.Execute(context.Background())
 		})
 
 		It("executes pre backup lock", func() {
@@ -41,14 +45,40 @@ var _ = Describe("JobExecutables", func() {
 				Expect(err).To(MatchError(ContainSubstring("fake error")))
 			})
 		})
+
+		Context("when events are being recorded", func() {
+			var events *bytes.Buffer
+
+			BeforeEach(func() {
+				events = new(bytes.Buffer)
+				event.SetOutput(events)
+				fakeJob.NameReturns("redis")
+				fakeJob.InstanceIdentifierReturns("redis-server/0")
+			})
+
+			AfterEach(func() {
+				event.SetOutput(nil)
+			})
+
+			It("records the result of the job", func() {
+				Expect(events.String()).To(SatisfyAll(
+					ContainSubstring(`"type":"job_finished"`),
+					ContainSubstring(`"deployment":"my-deployment"`),
+					ContainSubstring(`"action":"pre-backup-lock"`),
+					ContainSubstring(`"instance":"redis-server/0"`),
+					ContainSubstring(`"job":"redis"`),
+					ContainSubstring(`"result":"success"`),
+				))
+			})
+		})
 	})
 
 	Context("JobPostBackupUnlockExecutor", func() {
 		BeforeEach(func() {
-			executable = orchestrator.NewJobPostSuccessfulBackupUnlockExecutable(fakeJob)
+			executable = orchestrator.NewJobPostSuccessfulBackupUnlockExecutable(fakeJob, "my-deployment")
 		})
 		JustBeforeEach(func() {
-			err = executable.Execute()
+			err = executable.Execute(context.Background())
 		})
 
 		It("executes pre backup lock", func() {
@@ -69,10 +99,10 @@ var _ = Describe("JobExecutables", func() {
 
 	Context("JobPreRestoreLockExecutor", func() {
 		BeforeEach(func() {
-			executable = orchestrator.NewJobPreRestoreLockExecutable(fakeJob)
+			executable = orchestrator.NewJobPreRestoreLockExecutable(fakeJob, "my-deployment")
 		})
 		JustBeforeEach(func() {
-			err = executable.Execute()
+			err = executable.Execute(context.Background())
 		})
 
 		It("executes pre backup lock", func() {
@@ -93,10 +123,10 @@ var _ = Describe("JobExecutables", func() {
 
 	Context("JobPostRestoreUnlockExecutor", func() {
 		BeforeEach(func() {
-			executable = orchestrator.NewJobPostRestoreUnlockExecutable(fakeJob)
+			executable = orchestrator.NewJobPostRestoreUnlockExecutable(fakeJob, "my-deployment")
 		})
 		JustBeforeEach(func() {
-			err = executable.Execute()
+			err = executable.Execute(context.Background())
 		})
 
 		It("executes pre backup lock", func() {
@@ -0,0 +1,156 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
+
+type lockOutcome int
+
+const (
+	notRun lockOutcome = iota
+	succeeded
+	failed
+)
+
+type jobLockState struct {
+	job    Job
+	lock   lockOutcome
+	unlock lockOutcome
+}
+
+// jobLocks records which jobs of a deployment have been locked and unlocked. A job whose lock
+// script failed may still be partly locked, so only the jobs which bbr never tried to lock are
+// taken to be unlocked. Until jobs are locked through it, as when cleaning up after an earlier
+// bbr run, every job is taken to be locked.
+type jobLocks struct {
+	mux     sync.Mutex
+	locking bool
+	states  []*jobLockState
+}
+
+func (l *jobLocks) startLocking(orderedJobs [][]Job) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	l.locking = true
+	l.states = nil
+	for _, jobs := range orderedJobs {
+		for _, job := range jobs {
+			l.states = append(l.states, &jobLockState{job: job})
+		}
+	}
+}
+
+func (l *jobLocks) state(job Job) *jobLockState {
+	for _, state := range l.states {
+		if journalJob(state.job) == journalJob(job) {
+			return state
+		}
+	}
+
+	state := &jobLockState{job: job}
+	l.states = append(l.states, state)
+	return state
+}
+
+func (l *jobLocks) recordLock(job Job, err error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.state(job).lock = outcomeOf(err)
+}
+
+func (l *jobLocks) recordUnlock(job Job, err error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.state(job).unlock = outcomeOf(err)
+}
+
+func (l *jobLocks) isLocked(job Job) bool {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	state := l.state(job)
+	if state.unlock == succeeded {
+		return false
+	}
+	return !l.locking || state.lock != notRun
+}
+
+// onlyLocked keeps the jobs which are, or might be, locked, dropping batches left empty
+func (l *jobLocks) onlyLocked(orderedJobs [][]Job) [][]Job {
+	var lockedJobs [][]Job
+	for _, jobs := range orderedJobs {
+		var batch []Job
+		for _, job := range jobs {
+			if l.isLocked(job) {
+				batch = append(batch, job)
+			}
+		}
+		if len(batch) > 0 {
+			lockedJobs = append(lockedJobs, batch)
+		}
+	}
+	return lockedJobs
+}
+
+// describe lists, one line per job in lock order, whether it was locked and unlocked
+func (l *jobLocks) describe() string {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	var lines []string
+	for _, state := range l.states {
+		lines = append(lines, fmt.Sprintf("  %s/%s: %s, %s", state.job.InstanceIdentifier(), state.job.Name(),
+			describeOutcome(state.lock, "locked", "failed to lock", "not locked"),
+			describeOutcome(state.unlock, "unlocked", "failed to unlock", "not unlocked")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func outcomeOf(err error) lockOutcome {
+	if err != nil {
+		return failed
+	}
+	return succeeded
+}
+
+func describeOutcome(outcome lockOutcome, succeededText, failedText, notRunText string) string {
+	switch outcome {
+	case succeeded:
+		return succeededText
+	case failed:
+		return failedText
+	default:
+		return notRunText
+	}
+}
+
+// trackedLockExecutable records the outcome of locking or unlocking a job once it has run
+type trackedLockExecutable struct {
+	executor.Executable
+	job    Job
+	record func(Job, error)
+}
+
+func (e trackedLockExecutable) Execute(ctx context.Context) error {
+	err := e.Executable.Execute(ctx)
+	e.record(e.job, err)
+	return err
+}
+
+func newTrackedLockExecutables(jobs []Job, deploymentName string, newJobExecutable func(Job, string) executor.Executable, record func(Job, error)) []executor.Executable {
+	var executables []executor.Executable
+	for _, job := range jobs {
+		executables = append(executables, trackedLockExecutable{
+			Executable: newJobExecutable(job, deploymentName),
+			job:        job,
+			record:     record,
+		})
+	}
+	return executables
+}
@@ -0,0 +1,364 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const journalFileSuffix = ".bbr-journal"
+
+// Journals keeps, in a local directory, a journal for each deployment being backed up or
+// restored. Each journal records what bbr has done to the deployment and not yet undone, so
+// that backup-cleanup and restore-cleanup can undo exactly that after bbr itself crashed.
+type Journals struct {
+	dir string
+}
+
+func NewJournals(dir string) *Journals {
+	return &Journals{dir: dir}
+}
+
+func (j *Journals) path(deploymentName string) string {
+	return filepath.Join(j.dir, deploymentName+journalFileSuffix)
+}
+
+// Start begins the journal of an operation on a deployment. It fails if a previous
+// operation left outstanding entries behind, as those have to be cleaned up first.
+func (j *Journals) Start(deploymentName, operation string) (*Journal, error) {
+	if err := j.checkDir(); err != nil {
+		return nil, err
+	}
+
+	previous, err := j.Read(deploymentName)
+	if err != nil {
+		return nil, err
+	}
+	if previous.Outstanding() {
+		return nil, NewArtifactDirError(fmt.Sprintf(
+			"A previous %s of '%s' did not finish cleaning up, according to its journal at %s",
+			previous.entries.Operation, deploymentName, previous.path))
+	}
+
+	return j.new(deploymentName, operation), nil
+}
+
+// Continue carries on the journal left behind by a previous operation on a deployment, or
+// begins a new one if there is none
+func (j *Journals) Continue(deploymentName, operation string) (*Journal, error) {
+	if err := j.checkDir(); err != nil {
+		return nil, err
+	}
+
+	previous, err := j.Read(deploymentName)
+	if err != nil || previous != nil {
+		return previous, err
+	}
+
+	return j.new(deploymentName, operation), nil
+}
+
+// Read returns the journal left behind by a previous operation on a deployment, or nil if
+// there is none
+func (j *Journals) Read(deploymentName string) (*Journal, error) {
+	if j == nil {
+		return nil, nil
+	}
+
+	path := j.path(deploymentName)
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to read journal")
+	}
+
+	journal := &Journal{path: path}
+	if err := json.Unmarshal(contents, &journal.entries); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse journal %s", path)
+	}
+	return journal, nil
+}
+
+func (j *Journals) checkDir() error {
+	if j == nil || j.dir == "" {
+		return nil
+	}
+	_, err := os.Stat(j.dir)
+	return err
+}
+
+func (j *Journals) new(deploymentName, operation string) *Journal {
+	if j == nil {
+		return nil
+	}
+	return &Journal{path: j.path(deploymentName), entries: JournalEntries{Operation: operation}}
+}
+
+// Journal is written to disk every time it changes, and removed once it has no entries left
+type Journal struct {
+	mux     sync.Mutex
+	path    string
+	entries JournalEntries
+}
+
+type JournalEntries struct {
+	Operation           string           `json:"operation"`
+	SSHUsers            []JournalSSHUser `json:"ssh_users,omitempty"`
+	LockedJobs          []JournalJob     `json:"locked_jobs,omitempty"`
+	ArtifactDirectories []string         `json:"artifact_directories,omitempty"`
+}
+
+type JournalSSHUser struct {
+	Instance string `json:"instance"`
+	Username string `json:"username"`
+}
+
+type JournalJob struct {
+	Instance string `json:"instance"`
+	Job      string `json:"job"`
+}
+
+func (j *Journal) Path() string {
+	if j == nil {
+		return ""
+	}
+	return j.path
+}
+
+// Entries returns a copy of what the journal records
+func (j *Journal) Entries() JournalEntries {
+	if j == nil {
+		return JournalEntries{}
+	}
+
+	j.mux.Lock()
+	defer j.mux.Unlock()
+	return JournalEntries{
+		Operation:           j.entries.Operation,
+		SSHUsers:            append([]JournalSSHUser(nil), j.entries.SSHUsers...),
+		LockedJobs:          append([]JournalJob(nil), j.entries.LockedJobs...),
+		ArtifactDirectories: append([]string(nil), j.entries.ArtifactDirectories...),
+	}
+}
+
+// Outstanding is whether the journal records anything which has not been undone yet
+func (j *Journal) Outstanding() bool {
+	if j == nil {
+		return false
+	}
+
+	j.mux.Lock()
+	defer j.mux.Unlock()
+	return j.entries.outstanding()
+}
+
+func (j *Journal) AddSSHUsers(instances []Instance) error {
+	return j.update(func(entries *JournalEntries) {
+		for _, instance := range instances {
+			if instance.SSHUser() != "" {
+				entries.SSHUsers = appendSSHUser(entries.SSHUsers, JournalSSHUser{Instance: instanceName(instance), Username: instance.SSHUser()})
+			}
+		}
+	})
+}
+
+func (j *Journal) RemoveSSHUsers(instances []Instance) error {
+	return j.update(func(entries *JournalEntries) {
+		for _, instance := range instances {
+			entries.SSHUsers = removeSSHUser(entries.SSHUsers, JournalSSHUser{Instance: instanceName(instance), Username: instance.SSHUser()})
+		}
+	})
+}
+
+func (j *Journal) AddLockedJobs(jobs []Job) error {
+	return j.update(func(entries *JournalEntries) {
+		for _, job := range jobs {
+			entries.LockedJobs = appendJob(entries.LockedJobs, journalJob(job))
+		}
+	})
+}
+
+func (j *Journal) RemoveLockedJobs(jobs []Job) error {
+	return j.update(func(entries *JournalEntries) {
+		for _, job := range jobs {
+			entries.LockedJobs = removeJob(entries.LockedJobs, journalJob(job))
+		}
+	})
+}
+
+func (j *Journal) AddArtifactDirectories(instances []Instance) error {
+	return j.update(func(entries *JournalEntries) {
+		for _, instance := range instances {
+			entries.ArtifactDirectories = appendString(entries.ArtifactDirectories, instanceName(instance))
+		}
+	})
+}
+
+func (j *Journal) RemoveArtifactDirectories(instances []Instance) error {
+	return j.update(func(entries *JournalEntries) {
+		for _, instance := range instances {
+			entries.ArtifactDirectories = removeString(entries.ArtifactDirectories, instanceName(instance))
+		}
+	})
+}
+
+// Clear removes every entry, and so the journal itself
+func (j *Journal) Clear() error {
+	return j.update(func(entries *JournalEntries) {
+		*entries = JournalEntries{Operation: entries.Operation}
+	})
+}
+
+// update changes the entries and saves them, replacing the previous journal in one rename so
+// that a crash leaves either the old or the new journal behind
+func (j *Journal) update(change func(*JournalEntries)) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mux.Lock()
+	defer j.mux.Unlock()
+	change(&j.entries)
+
+	if !j.entries.outstanding() {
+		if err := os.Remove(j.path); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "failed to remove journal")
+		}
+		return nil
+	}
+
+	contents, err := json.MarshalIndent(j.entries, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal journal")
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(j.path), filepath.Base(j.path))
+	if err != nil {
+		return errors.Wrap(err, "failed to write journal")
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(contents)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), j.path)
+	}
+	return errors.Wrap(err, "failed to write journal")
+}
+
+func (e JournalEntries) outstanding() bool {
+	return len(e.SSHUsers) > 0 || len(e.LockedJobs) > 0 || len(e.ArtifactDirectories) > 0
+}
+
+// Describe lists the entries, one per line
+func (e JournalEntries) Describe() string {
+	var lines []string
+	for _, job := range e.LockedJobs {
+		lines = append(lines, fmt.Sprintf("job %s on %s is locked", job.Job, job.Instance))
+	}
+	for _, instance := range e.ArtifactDirectories {
+		lines = append(lines, fmt.Sprintf("%s exists on %s", ArtifactDirectory, instance))
+	}
+	for _, user := range e.SSHUsers {
+		lines = append(lines, fmt.Sprintf("SSH user %s exists on %s", user.Username, user.Instance))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func jobsOf(deployment Deployment) []Job {
+	return instances(deployment.Instances()).Jobs()
+}
+
+// unlockedJobsOf returns the jobs of the deployment which are not locked, so that the journal
+// only records those which are
+func unlockedJobsOf(deployment Deployment) []Job {
+	locked := map[JournalJob]bool{}
+	for _, job := range deployment.LockedJobs() {
+		locked[journalJob(job)] = true
+	}
+
+	var unlocked []Job
+	for _, job := range jobsOf(deployment) {
+		if !locked[journalJob(job)] {
+			unlocked = append(unlocked, job)
+		}
+	}
+	return unlocked
+}
+
+func instanceName(instance InstanceIdentifer) string {
+	return instance.Name() + "/" + instance.ID()
+}
+
+func journalJob(job Job) JournalJob {
+	return JournalJob{Instance: job.InstanceIdentifier(), Job: job.Name()}
+}
+
+func appendSSHUser(users []JournalSSHUser, user JournalSSHUser) []JournalSSHUser {
+	for _, u := range users {
+		if u == user {
+			return users
+		}
+	}
+	return append(users, user)
+}
+
+func removeSSHUser(users []JournalSSHUser, user JournalSSHUser) []JournalSSHUser {
+	var remaining []JournalSSHUser
+	for _, u := range users {
+		if u != user {
+			remaining = append(remaining, u)
+		}
+	}
+	return remaining
+}
+
+func appendJob(jobs []JournalJob, job JournalJob) []JournalJob {
+	for _, j := range jobs {
+		if j == job {
+			return jobs
+		}
+	}
+	return append(jobs, job)
+}
+
+func removeJob(jobs []JournalJob, job JournalJob) []JournalJob {
+	var remaining []JournalJob
+	for _, j := range jobs {
+		if j != job {
+			remaining = append(remaining, j)
+		}
+	}
+	return remaining
+}
+
+func appendString(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func removeString(values []string, value string) []string {
+	var remaining []string
+	for _, v := range values {
+		if v != value {
+			remaining = append(remaining, v)
+		}
+	}
+	return remaining
+}
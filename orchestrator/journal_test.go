@@ -0,0 +1,134 @@
+package orchestrator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Journal", func() {
+	var (
+		dir         string
+		journals    *orchestrator.Journals
+		journalPath string
+		instance    *fakes.FakeInstance
+		job         *fakes.FakeJob
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "journal")
+		Expect(err).NotTo(HaveOccurred())
+		journals = orchestrator.NewJournals(dir)
+		journalPath = filepath.Join(dir, "redis.bbr-journal")
+
+		instance = new(fakes.FakeInstance)
+		instance.NameReturns("redis")
+		instance.IDReturns("abc")
+		instance.SSHUserReturns("bbr-123")
+		job = new(fakes.FakeJob)
+		job.NameReturns("redis-server")
+		job.InstanceIdentifierReturns("redis/abc")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("is only written once it has entries, and removed once it has none left", func() {
+		journal, err := journals.Start("redis", "backup")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(journalPath).NotTo(BeAnExistingFile())
+
+		Expect(journal.AddSSHUsers([]orchestrator.Instance{instance})).To(Succeed())
+		Expect(journal.AddLockedJobs([]orchestrator.Job{job})).To(Succeed())
+		Expect(journal.AddArtifactDirectories([]orchestrator.Instance{instance})).To(Succeed())
+		Expect(journalPath).To(BeAnExistingFile())
+
+		Expect(journal.RemoveLockedJobs([]orchestrator.Job{job})).To(Succeed())
+		Expect(journal.RemoveArtifactDirectories([]orchestrator.Instance{instance})).To(Succeed())
+		Expect(journalPath).To(BeAnExistingFile())
+
+		Expect(journal.RemoveSSHUsers([]orchestrator.Instance{instance})).To(Succeed())
+		Expect(journalPath).NotTo(BeAnExistingFile())
+		Expect(journal.Outstanding()).To(BeFalse())
+	})
+
+	It("can be read back after bbr exited", func() {
+		journal, err := journals.Start("redis", "restore")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(journal.AddSSHUsers([]orchestrator.Instance{instance})).To(Succeed())
+		Expect(journal.AddLockedJobs([]orchestrator.Job{job, job})).To(Succeed())
+		Expect(journal.AddArtifactDirectories([]orchestrator.Instance{instance})).To(Succeed())
+
+		previous, err := journals.Read("redis")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(previous.Path()).To(Equal(journalPath))
+		Expect(previous.Entries()).To(Equal(orchestrator.JournalEntries{
+			Operation:           "restore",
+			SSHUsers:            []orchestrator.JournalSSHUser{{Instance: "redis/abc", Username: "bbr-123"}},
+			LockedJobs:          []orchestrator.JournalJob{{Instance: "redis/abc", Job: "redis-server"}},
+			ArtifactDirectories: []string{"redis/abc"},
+		}))
+		Expect(previous.Entries().Describe()).To(Equal("job redis-server on redis/abc is locked\n" +
+			"/var/vcap/store/bbr-backup exists on redis/abc\n" +
+			"SSH user bbr-123 exists on redis/abc"))
+	})
+
+	Context("when a previous operation left outstanding entries behind", func() {
+		BeforeEach(func() {
+			previous, err := journals.Start("redis", "backup")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(previous.AddLockedJobs([]orchestrator.Job{job})).To(Succeed())
+		})
+
+		It("cannot be started again", func() {
+			_, err := journals.Start("redis", "backup")
+			Expect(err).To(BeAssignableToTypeOf(orchestrator.ArtifactDirError{}))
+			Expect(err).To(MatchError(ContainSubstring("A previous backup of 'redis' did not finish cleaning up, according to its journal at " + journalPath)))
+		})
+
+		It("can be continued", func() {
+			journal, err := journals.Continue("redis", "backup")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(journal.Entries().LockedJobs).To(HaveLen(1))
+		})
+
+		It("can be cleared", func() {
+			journal, err := journals.Read("redis")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(journal.Clear()).To(Succeed())
+			Expect(journalPath).NotTo(BeAnExistingFile())
+		})
+	})
+
+	It("reads no journal when there is none", func() {
+		journal, err := journals.Read("redis")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(journal).To(BeNil())
+	})
+
+	It("fails to read a journal which is not valid", func() {
+		Expect(ioutil.WriteFile(journalPath, []byte("not json"), 0644)).To(Succeed())
+		_, err := journals.Read("redis")
+		Expect(err).To(MatchError(ContainSubstring("failed to parse journal " + journalPath)))
+	})
+
+	It("fails to start when the journal directory does not exist", func() {
+		_, err := orchestrator.NewJournals(filepath.Join(dir, "missing")).Start("redis", "backup")
+		Expect(err).To(MatchError(ContainSubstring(filepath.Join(dir, "missing") + ": no such file or directory")))
+	})
+
+	It("does nothing without a journal directory", func() {
+		var noJournals *orchestrator.Journals
+		journal, err := noJournals.Start("redis", "backup")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(journal.AddLockedJobs([]orchestrator.Job{job})).To(Succeed())
+		Expect(journal.Outstanding()).To(BeFalse())
+	})
+})
@@ -0,0 +1,29 @@
+package orchestrator
+
+import "context"
+
+// KeepArtifactsStep stops the cleanup from removing the artifact directories of a backup
+// which could not be drained, so that the backup can still be resumed. The hint tells the
+// operator how to resume or remove it, which depends on the kind of backup.
+type KeepArtifactsStep struct {
+	logger Logger
+	hint   string
+}
+
+func NewKeepArtifactsStep(logger Logger, hint string) Step {
+	return &KeepArtifactsStep{logger: logger, hint: hint}
+}
+
+func (s *KeepArtifactsStep) Run(ctx context.Context, session *Session) error {
+	for _, instance := range session.CurrentDeployment().Instances() {
+		if instance.ArtifactDirCreated() {
+			instance.KeepArtifactDir()
+		}
+	}
+
+	s.logger.Warn("bbr", "The backup of %s was not fully drained, so %s has been kept on its instances. %s",
+		session.DeploymentName(), ArtifactDirectory, s.hint)
+	return nil
+}
+
+func (s *KeepArtifactsStep) cleansUp() {}
@@ -1,20 +1,34 @@
 package orchestrator
 
-import "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
 
 type LockStep struct {
 	lockOrderer LockOrderer
 	executor    executor.Executor
 }
 
-func (s *LockStep) Run(session *Session) error {
-	err := session.CurrentDeployment().PreBackupLock(s.lockOrderer, s.executor)
-	if err != nil {
+func (s *LockStep) Run(ctx context.Context, session *Session) error {
+	if err := session.Journal().AddLockedJobs(jobsOf(session.CurrentDeployment())); err != nil {
 		return NewLockError(err.Error())
 	}
+
+	err := session.CurrentDeployment().PreBackupLock(ctx, s.lockOrderer, s.executor)
+
+	if journalErr := session.Journal().RemoveLockedJobs(unlockedJobsOf(session.CurrentDeployment())); journalErr != nil && err == nil {
+		err = journalErr
+	}
+	if err != nil {
+		return withTimeout(NewLockError(err.Error()), err)
+	}
 	return nil
 }
 
 func NewLockStep(lockOrderer LockOrderer, executor executor.Executor) Step {
 	return &LockStep{lockOrderer: lockOrderer, executor: executor}
 }
+
+func (s *LockStep) locksJobs() {}
@@ -1,6 +1,10 @@
 package orchestrator
 
-import "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
 
 type PostBackupUnlockStep struct {
 	afterSuccessfulBackup bool
@@ -16,10 +20,16 @@ func NewPostBackupUnlockStep(afterSuccessfulBackup bool, lockOrderer LockOrderer
 	}
 }
 
-func (s *PostBackupUnlockStep) Run(session *Session) error {
-	err := session.CurrentDeployment().PostBackupUnlock(s.afterSuccessfulBackup, s.lockOrderer, s.executor)
+func (s *PostBackupUnlockStep) Run(ctx context.Context, session *Session) error {
+	err := session.CurrentDeployment().PostBackupUnlock(ctx, s.afterSuccessfulBackup, s.lockOrderer, s.executor)
+
+	if journalErr := session.Journal().RemoveLockedJobs(unlockedJobsOf(session.CurrentDeployment())); journalErr != nil && err == nil {
+		err = journalErr
+	}
 	if err != nil {
-		return NewPostUnlockError(err.Error())
+		return withTimeout(NewPostUnlockError(err.Error()), err)
 	}
 	return nil
 }
+
+func (s *PostBackupUnlockStep) cleansUp() {}
@@ -0,0 +1,33 @@
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
+
+type PostBackupVerifyStep struct {
+	logger   Logger
+	executor executor.Executor
+}
+
+func NewPostBackupVerifyStep(logger Logger, executor executor.Executor) Step {
+	return &PostBackupVerifyStep{logger: logger, executor: executor}
+}
+
+// Run verifies the artifacts written by the backup scripts while they are still on the
+// instances, recording the results in the backup metadata whether or not they pass.
+func (s *PostBackupVerifyStep) Run(ctx context.Context, session *Session) error {
+	results, err := session.CurrentDeployment().PostBackupVerify(ctx, s.executor)
+
+	if len(results) > 0 {
+		if recordErr := session.CurrentArtifact().AddVerifyResults(results); recordErr != nil {
+			s.logger.Warn("bbr", "Failed to record the results of post-backup-verify scripts: %s", recordErr)
+		}
+	}
+
+	if err != nil {
+		return withTimeout(NewPostBackupVerifyError(err.Error()), err)
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package orchestrator
 
-import "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
 
 type PostRestoreUnlockStep struct {
 	lockOrderer LockOrderer
@@ -14,12 +18,16 @@ func NewPostRestoreUnlockStep(lockOrderer LockOrderer, executor executor.Executo
 	}
 }
 
-func (s *PostRestoreUnlockStep) Run(session *Session) error {
-	err := session.CurrentDeployment().PostRestoreUnlock(s.lockOrderer, s.executor)
+func (s *PostRestoreUnlockStep) Run(ctx context.Context, session *Session) error {
+	err := session.CurrentDeployment().PostRestoreUnlock(ctx, s.lockOrderer, s.executor)
 
+	if journalErr := session.Journal().RemoveLockedJobs(unlockedJobsOf(session.CurrentDeployment())); journalErr != nil && err == nil {
+		err = journalErr
+	}
 	if err != nil {
-		return NewPostUnlockError(err.Error())
+		return withTimeout(NewPostUnlockError(err.Error()), err)
 	}
-
 	return nil
 }
+
+func (s *PostRestoreUnlockStep) cleansUp() {}
@@ -0,0 +1,24 @@
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+	"github.com/pkg/errors"
+)
+
+type PreBackupCheckStep struct {
+	executor executor.Executor
+}
+
+func NewPreBackupCheckStep(executor executor.Executor) Step {
+	return &PreBackupCheckStep{executor: executor}
+}
+
+func (s *PreBackupCheckStep) Run(ctx context.Context, session *Session) error {
+	err := session.CurrentDeployment().PreBackupCheck(ctx, s.executor)
+	if err != nil {
+		return withTimeout(errors.Errorf("Deployment '%s' cannot be backed up: %s", session.DeploymentName(), err.Error()), err)
+	}
+	return nil
+}
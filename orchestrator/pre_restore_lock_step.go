@@ -1,6 +1,8 @@
 package orchestrator
 
 import (
+	"context"
+
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/pkg/errors"
 )
@@ -17,11 +19,21 @@ func NewPreRestoreLockStep(lockOrderer LockOrderer, executor executor.Executor)
 	}
 }
 
-func (s *PreRestoreLockStep) Run(session *Session) error {
-	err := session.CurrentDeployment().PreRestoreLock(s.lockOrderer, s.executor)
+func (s *PreRestoreLockStep) Run(ctx context.Context, session *Session) error {
+	if err := session.Journal().AddLockedJobs(jobsOf(session.CurrentDeployment())); err != nil {
+		return errors.Wrap(err, "pre-restore-lock failed")
+	}
+
+	err := session.CurrentDeployment().PreRestoreLock(ctx, s.lockOrderer, s.executor)
+
+	if journalErr := session.Journal().RemoveLockedJobs(unlockedJobsOf(session.CurrentDeployment())); journalErr != nil && err == nil {
+		err = journalErr
+	}
 
 	if err != nil {
-		return errors.Wrap(err, "pre-restore-lock failed")
+		return withTimeout(errors.Wrap(err, "pre-restore-lock failed"), err)
 	}
 	return nil
 }
+
+func (s *PreRestoreLockStep) locksJobs() {}
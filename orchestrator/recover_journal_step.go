@@ -0,0 +1,88 @@
+package orchestrator
+
+import "context"
+
+type RecoverJournalStep struct {
+	logger Logger
+}
+
+func NewRecoverJournalStep(logger Logger) Step {
+	return &RecoverJournalStep{logger: logger}
+}
+
+// Run narrows the deployment down to what the journal of an interrupted backup or restore
+// says is outstanding: only the jobs it locked are unlocked, and only the artifact
+// directories it created are removed. Without a journal, everything is cleaned up.
+func (s *RecoverJournalStep) Run(ctx context.Context, session *Session) error {
+	journal := session.Journal()
+	if journal == nil {
+		s.logger.Info("bbr", "No journal found for '%s', cleaning up every job and instance", session.DeploymentName())
+		return nil
+	}
+
+	entries := journal.Entries()
+	s.logger.Info("bbr", "Cleaning up after the %s of '%s', according to its journal at %s:\n%s",
+		entries.Operation, session.DeploymentName(), journal.Path(), entries.Describe())
+
+	deployment := session.CurrentDeployment()
+	var recovered []Instance
+	for _, instance := range deployment.Instances() {
+		var lockedJobs []Job
+		for _, job := range instance.Jobs() {
+			if containsJob(entries.LockedJobs, journalJob(job)) {
+				lockedJobs = append(lockedJobs, job)
+			}
+		}
+		if containsString(entries.ArtifactDirectories, instanceName(instance)) {
+			instance.MarkArtifactDirCreated()
+		}
+		recovered = append(recovered, journaledInstance{Instance: instance, jobs: lockedJobs})
+	}
+
+	for _, job := range entries.LockedJobs {
+		if findInstance(recovered, job.Instance) == nil {
+			s.logger.Warn("bbr", "Cannot unlock job %s, as %s is no longer part of the deployment", job.Job, job.Instance)
+		}
+	}
+	for _, name := range entries.ArtifactDirectories {
+		if findInstance(recovered, name) == nil {
+			s.logger.Warn("bbr", "Cannot remove %s, as %s is no longer part of the deployment", ArtifactDirectory, name)
+		}
+	}
+	for _, user := range entries.SSHUsers {
+		if findInstance(recovered, user.Instance) == nil {
+			s.logger.Warn("bbr", "Cannot remove SSH user %s, as %s is no longer part of the deployment", user.Username, user.Instance)
+		}
+	}
+
+	session.SetCurrentDeployment(NewDeployment(deployment.Name(), s.logger, recovered))
+	return nil
+}
+
+// journaledInstance only has the jobs which the journal says are locked
+type journaledInstance struct {
+	Instance
+	jobs []Job
+}
+
+func (i journaledInstance) Jobs() []Job {
+	return i.jobs
+}
+
+func findInstance(instances []Instance, name string) Instance {
+	for _, instance := range instances {
+		if instanceName(instance) == name {
+			return instance
+		}
+	}
+	return nil
+}
+
+func containsJob(jobs []JournalJob, job JournalJob) bool {
+	for _, j := range jobs {
+		if j == job {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+type ReopenArtifactStep struct {
+	logger        Logger
+	backupManager BackupManager
+}
+
+func NewReopenArtifactStep(logger Logger, backupManager BackupManager) Step {
+	return &ReopenArtifactStep{logger: logger, backupManager: backupManager}
+}
+
+func (s *ReopenArtifactStep) Run(ctx context.Context, session *Session) error {
+	s.logger.Info("bbr", "Resuming backup of %s from %s...\n", session.DeploymentName(), session.CurrentArtifactPath())
+
+	artifact, err := s.backupManager.Open(session.CurrentArtifactPath(), s.logger)
+	if err != nil {
+		return errors.Wrap(err, "Could not open backup to resume")
+	}
+
+	match, err := artifact.DeploymentMatches(session.DeploymentName(), session.CurrentDeployment().Instances())
+	if err != nil {
+		return errors.Wrap(err, "Could not check backup to resume")
+	}
+	if !match {
+		return errors.Errorf("Backup at %s does not match deployment %s", session.CurrentArtifactPath(), session.DeploymentName())
+	}
+
+	session.SetCurrentArtifact(artifact)
+	return nil
+}
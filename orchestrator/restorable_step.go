@@ -1,6 +1,8 @@
 package orchestrator
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 )
 
@@ -14,7 +16,7 @@ func NewRestorableStep(lockOrderer LockOrderer) Step {
 	}
 }
 
-func (s *RestorableStep) Run(session *Session) error {
+func (s *RestorableStep) Run(ctx context.Context, session *Session) error {
 	if !session.CurrentDeployment().IsRestorable() {
 		return errors.Errorf("Deployment '%s' has no restore scripts", session.DeploymentName())
 	}
@@ -1,33 +1,50 @@
 package orchestrator
 
-import "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+import (
+	"context"
 
-func NewRestoreCleaner(logger Logger, deploymentManager DeploymentManager, lockOrderer LockOrderer, executor executor.Executor) *RestoreCleaner {
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
+
+func NewRestoreCleaner(logger Logger, deploymentManager DeploymentManager, lockOrderer LockOrderer, executor executor.Executor, journals *Journals) *RestoreCleaner {
 	workflow := NewWorkflow()
 	findDeploymentStep := NewFindDeploymentStep(deploymentManager, logger)
+	recoverJournalStep := NewRecoverJournalStep(logger)
 	postRestoreUnlockStep := NewPostRestoreUnlockStep(lockOrderer, executor)
 	cleanupPreviousStep := NewCleanupPreviousStep()
 
-	workflow.StartWith(findDeploymentStep).OnSuccess(postRestoreUnlockStep)
+	workflow.StartWith(findDeploymentStep).OnSuccess(recoverJournalStep)
+	workflow.Add(recoverJournalStep).OnSuccess(postRestoreUnlockStep)
 	workflow.Add(postRestoreUnlockStep).OnSuccessOrFailure(cleanupPreviousStep)
 	workflow.Add(cleanupPreviousStep)
 
 	return &RestoreCleaner{
 		Logger:   logger,
 		Workflow: workflow,
+		journals: journals,
 	}
 }
 
 type RestoreCleaner struct {
 	Logger
 	*Workflow
+	journals *Journals
 }
 
 func (c RestoreCleaner) Cleanup(deploymentName string) Error {
+	journal, err := c.journals.Read(deploymentName)
+	if err != nil {
+		return NewError(err)
+	}
+
 	session := NewSession(deploymentName)
-	currentError := c.Workflow.Run(session)
+	session.SetJournal(journal)
+	currentError := c.Workflow.Run(context.Background(), session)
 
 	if len(currentError) == 0 {
+		if err := journal.Clear(); err != nil {
+			return NewError(NewCleanupError(err.Error()))
+		}
 		c.Logger.Info("bbr", "'%s' cleaned up\n", deploymentName)
 	}
 	return currentError
@@ -1,6 +1,7 @@
 package orchestrator_test
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
@@ -26,7 +27,7 @@ var _ = Describe("Restore Cleanup", func() {
 		deployment = new(fakes.FakeDeployment)
 		deploymentManager = new(fakes.FakeDeploymentManager)
 		logger = new(fakes.FakeLogger)
-		restoreCleaner = orchestrator.NewRestoreCleaner(logger, deploymentManager, fakeLockOrderer, executor.NewSerialExecutor())
+		restoreCleaner = orchestrator.NewRestoreCleaner(logger, deploymentManager, fakeLockOrderer, executor.NewSerialExecutor(), nil)
 	})
 
 	JustBeforeEach(func() {
@@ -57,7 +58,7 @@ var _ = Describe("Restore Cleanup", func() {
 		var currentSequenceNumber, unlockCallIndex, cleanupCallIndex int
 		BeforeEach(func() {
 			deploymentManager.FindReturns(deployment, nil)
-			deployment.PostRestoreUnlockStub = func(orderer orchestrator.LockOrderer, _ executor.Executor) error {
+			deployment.PostRestoreUnlockStub = func(_ context.Context, orderer orchestrator.LockOrderer, _ executor.Executor) error {
 				unlockCallIndex = currentSequenceNumber
 				currentSequenceNumber = currentSequenceNumber + 1
 				return nil
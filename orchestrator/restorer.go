@@ -1,15 +1,22 @@
 package orchestrator
 
-import "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+import (
+	"context"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
 
 type Restorer struct {
 	workflow *Workflow
+	journals *Journals
 }
 
 func NewRestorer(backupManager BackupManager, logger Logger, deploymentManager DeploymentManager,
-	lockOrderer LockOrderer, executor executor.Executor, artifactCopier ArtifactCopier) *Restorer {
-	workflow := NewWorkflow()
-	validateArtifactStep := NewValidateArtifactStep(logger, backupManager)
+	lockOrderer LockOrderer, executor executor.Executor, artifactCopier ArtifactCopier, scriptEnvironment *ScriptEnvironment,
+	journals *Journals, abort *Abort, lockTimeout time.Duration) *Restorer {
+	workflow := NewAbortableWorkflow(abort, lockTimeout)
+	validateArtifactStep := NewValidateArtifactStep(logger, backupManager, scriptEnvironment)
 	findDeploymentStep := NewFindDeploymentStep(deploymentManager, logger)
 	restorableStep := NewRestorableStep(lockOrderer)
 	cleanupStep := NewCleanupStep()
@@ -28,12 +35,19 @@ func NewRestorer(backupManager BackupManager, logger Logger, deploymentManager D
 	workflow.Add(cleanupStep)
 	return &Restorer{
 		workflow: workflow,
+		journals: journals,
 	}
 }
 
-func (r Restorer) Restore(deploymentName, backupPath string) Error {
+func (r Restorer) Restore(ctx context.Context, deploymentName, backupPath string) Error {
+	journal, err := r.journals.Start(deploymentName, "restore")
+	if err != nil {
+		return NewError(err)
+	}
+
 	session := NewSession(deploymentName)
 	session.SetCurrentArtifactPath(backupPath)
+	session.SetJournal(journal)
 
-	return r.workflow.Run(session)
+	return r.workflow.Run(ctx, session)
 }
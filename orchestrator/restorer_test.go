@@ -1,6 +1,7 @@
 package orchestrator_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -27,6 +28,7 @@ var _ = Describe("restorer", func() {
 			artifactPath      string
 			lockOrderer       *fakes.FakeLockOrderer
 			artifactCopier    *fakes.FakeArtifactCopier
+			scriptEnvironment *orchestrator.ScriptEnvironment
 		)
 
 		BeforeEach(func() {
@@ -38,6 +40,7 @@ var _ = Describe("restorer", func() {
 			deployment = new(fakes.FakeDeployment)
 			lockOrderer = new(fakes.FakeLockOrderer)
 			artifactCopier = new(fakes.FakeArtifactCopier)
+			scriptEnvironment = orchestrator.NewScriptEnvironment("1.2.3", nil)
 
 			artifactManager.OpenReturns(artifact, nil)
 			deploymentManager.FindReturns(deployment, nil)
@@ -46,20 +49,28 @@ var _ = Describe("restorer", func() {
 			artifact.DeploymentMatchesReturns(true, nil)
 			artifact.ValidReturns(true, nil)
 
-			b = orchestrator.NewRestorer(artifactManager, logger, deploymentManager, lockOrderer, executor.NewSerialExecutor(), artifactCopier)
+			b = orchestrator.NewRestorer(artifactManager, logger, deploymentManager, lockOrderer, executor.NewSerialExecutor(), artifactCopier, scriptEnvironment, nil, nil, 0)
 
 			deploymentName = "deployment-to-restore"
 			artifactPath = "/some/path"
 		})
 
 		JustBeforeEach(func() {
-			restoreError = b.Restore(deploymentName, artifactPath)
+			restoreError = b.Restore(context.Background(), deploymentName, artifactPath)
 		})
 
 		It("does not fail", func() {
 			Expect(restoreError).NotTo(HaveOccurred())
 		})
 
+		It("tells job scripts which backup is being restored", func() {
+			Expect(scriptEnvironment.Variables("redis", "0")).To(SatisfyAll(
+				HaveKeyWithValue("BBR_BACKUP_ID", "path"),
+				HaveKeyWithValue("BBR_ORIGINAL_INSTANCE_GROUP", "redis"),
+				HaveKeyWithValue("BBR_ORIGINAL_INSTANCE_INDEX", "0"),
+			))
+		})
+
 		It("ensures that instance is cleaned up", func() {
 			Expect(deployment.CleanupCallCount()).To(Equal(1))
 		})
@@ -95,7 +106,7 @@ var _ = Describe("restorer", func() {
 		It("streams the local backup to the deployment", func() {
 			Expect(artifactCopier.UploadBackupToDeploymentCallCount()).To(Equal(1))
 
-			uploadedArtifact, uploadedToDeployment := artifactCopier.UploadBackupToDeploymentArgsForCall(0)
+			_, uploadedArtifact, uploadedToDeployment := artifactCopier.UploadBackupToDeploymentArgsForCall(0)
 			Expect(uploadedArtifact).To(Equal(artifact))
 			Expect(uploadedToDeployment).To(Equal(deployment))
 		})
@@ -0,0 +1,30 @@
+package orchestrator
+
+import "context"
+
+type SaveScriptLogsStep struct {
+	logger     Logger
+	scriptLogs *ScriptLogs
+}
+
+func NewSaveScriptLogsStep(logger Logger, scriptLogs *ScriptLogs) Step {
+	return &SaveScriptLogsStep{logger: logger, scriptLogs: scriptLogs}
+}
+
+// Run saves the output of the scripts into the backup. The logs are only a record of the
+// backup, so failing to save them does not fail it.
+func (s *SaveScriptLogsStep) Run(ctx context.Context, session *Session) error {
+	logs := s.scriptLogs.Take()
+	if session.CurrentArtifact() == nil {
+		return nil
+	}
+
+	for _, log := range logs {
+		if err := session.CurrentArtifact().AddLog(log.Name(), log.Output); err != nil {
+			s.logger.Warn("bbr", "Failed to save the output of %s %s on %s: %s", log.Phase, log.Job, log.Instance, err)
+		}
+	}
+	return nil
+}
+
+func (s *SaveScriptLogsStep) cleansUp() {}
@@ -0,0 +1,92 @@
+package orchestrator
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimestampFormat = "20060102T150405Z"
+
+// ScriptEnvironment holds the variables which every job script of a run is given: the bbr
+// version, any variables set by the operator, and the backup being taken or restored. It is
+// shared between the jobs and the backuper or restorer, which sets the backup before any
+// lock, backup or restore scripts run.
+type ScriptEnvironment struct {
+	mux             sync.RWMutex
+	version         string
+	extra           map[string]string
+	backupID        string
+	backupTimestamp string
+	restoredBackup  Backup
+}
+
+func NewScriptEnvironment(version string, extra map[string]string) *ScriptEnvironment {
+	return &ScriptEnvironment{version: version, extra: extra}
+}
+
+func (e *ScriptEnvironment) SetBackup(id, timestamp string) {
+	if e == nil {
+		return
+	}
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.backupID, e.backupTimestamp = id, timestamp
+}
+
+// SetRestoredBackup sets the backup being restored. The backup ID is the name of its
+// directory, which ends in the time the backup was taken.
+func (e *ScriptEnvironment) SetRestoredBackup(id string, backup Backup) {
+	if e == nil {
+		return
+	}
+
+	timestamp := ""
+	if separator := strings.LastIndex(id, "_"); separator != -1 {
+		if _, err := time.Parse(backupTimestampFormat, id[separator+1:]); err == nil {
+			timestamp = id[separator+1:]
+		}
+	}
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.backupID, e.backupTimestamp, e.restoredBackup = id, timestamp, backup
+}
+
+// Variables returns the variables for a script running on the given instance. When
+// restoring, the instance the artifacts were backed up from is described by
+// BBR_ORIGINAL_INSTANCE_GROUP, BBR_ORIGINAL_INSTANCE_INDEX and, if the backup recorded it,
+// BBR_ORIGINAL_INSTANCE_ID.
+func (e *ScriptEnvironment) Variables(instanceGroup, instanceIndex string) map[string]string {
+	variables := map[string]string{}
+	if e == nil {
+		return variables
+	}
+
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+
+	for name, value := range e.extra {
+		variables[name] = value
+	}
+	if e.version != "" {
+		variables["BBR_VERSION"] = e.version
+	}
+	if e.backupID != "" {
+		variables["BBR_BACKUP_ID"] = e.backupID
+	}
+	if e.backupTimestamp != "" {
+		variables["BBR_BACKUP_TIMESTAMP"] = e.backupTimestamp
+	}
+
+	if e.restoredBackup != nil {
+		variables["BBR_ORIGINAL_INSTANCE_GROUP"] = instanceGroup
+		variables["BBR_ORIGINAL_INSTANCE_INDEX"] = instanceIndex
+		if id, err := e.restoredBackup.OriginalInstanceID(instanceGroup, instanceIndex); err == nil && id != "" {
+			variables["BBR_ORIGINAL_INSTANCE_ID"] = id
+		}
+	}
+
+	return variables
+}
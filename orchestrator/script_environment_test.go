@@ -0,0 +1,71 @@
+package orchestrator_test
+
+import (
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ScriptEnvironment", func() {
+	var scriptEnvironment *orchestrator.ScriptEnvironment
+
+	BeforeEach(func() {
+		scriptEnvironment = orchestrator.NewScriptEnvironment("1.2.3", map[string]string{"SITE": "london"})
+	})
+
+	It("gives every script the bbr version and the variables set by the operator", func() {
+		Expect(scriptEnvironment.Variables("redis", "0")).To(Equal(map[string]string{
+			"BBR_VERSION": "1.2.3",
+			"SITE":        "london",
+		}))
+	})
+
+	It("describes the backup being taken", func() {
+		scriptEnvironment.SetBackup("redis_20170102T150405Z", "20170102T150405Z")
+
+		Expect(scriptEnvironment.Variables("redis", "0")).To(SatisfyAll(
+			HaveKeyWithValue("BBR_BACKUP_ID", "redis_20170102T150405Z"),
+			HaveKeyWithValue("BBR_BACKUP_TIMESTAMP", "20170102T150405Z"),
+			Not(HaveKey("BBR_ORIGINAL_INSTANCE_ID")),
+		))
+	})
+
+	Context("when restoring", func() {
+		var backup *fakes.FakeBackup
+
+		BeforeEach(func() {
+			backup = new(fakes.FakeBackup)
+			backup.OriginalInstanceIDReturns("3e2fe0fd-b7f5-4a2c-a5d6-9b3a4c0c5c31", nil)
+		})
+
+		It("describes the backup and the instance the artifacts were backed up from", func() {
+			scriptEnvironment.SetRestoredBackup("redis_20170102T150405Z", backup)
+
+			Expect(scriptEnvironment.Variables("redis", "1")).To(SatisfyAll(
+				HaveKeyWithValue("BBR_BACKUP_ID", "redis_20170102T150405Z"),
+				HaveKeyWithValue("BBR_BACKUP_TIMESTAMP", "20170102T150405Z"),
+				HaveKeyWithValue("BBR_ORIGINAL_INSTANCE_GROUP", "redis"),
+				HaveKeyWithValue("BBR_ORIGINAL_INSTANCE_INDEX", "1"),
+				HaveKeyWithValue("BBR_ORIGINAL_INSTANCE_ID", "3e2fe0fd-b7f5-4a2c-a5d6-9b3a4c0c5c31"),
+			))
+
+			name, index := backup.OriginalInstanceIDArgsForCall(0)
+			Expect(name).To(Equal("redis"))
+			Expect(index).To(Equal("1"))
+		})
+
+		It("does not set a timestamp for backups whose name does not end in one", func() {
+			scriptEnvironment.SetRestoredBackup("my-backup", backup)
+
+			Expect(scriptEnvironment.Variables("redis", "1")).NotTo(HaveKey("BBR_BACKUP_TIMESTAMP"))
+		})
+	})
+
+	It("is empty when there is no environment", func() {
+		var noEnvironment *orchestrator.ScriptEnvironment
+		noEnvironment.SetBackup("redis_20170102T150405Z", "20170102T150405Z")
+
+		Expect(noEnvironment.Variables("redis", "0")).To(BeEmpty())
+	})
+})
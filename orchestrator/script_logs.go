@@ -0,0 +1,53 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ScriptLogs collects the output of the lock, backup and unlock scripts of each job, so
+// that it can be saved into the backup once the scripts have run.
+type ScriptLogs struct {
+	mux  sync.Mutex
+	logs []ScriptLog
+}
+
+type ScriptLog struct {
+	Instance string
+	Job      string
+	Phase    string
+	Output   []byte
+}
+
+// Name identifies the log within a backup, as <instance>-<job>-<phase>
+func (l ScriptLog) Name() string {
+	return fmt.Sprintf("%s-%s-%s", strings.Replace(l.Instance, "/", "-", -1), l.Job, l.Phase)
+}
+
+func NewScriptLogs() *ScriptLogs {
+	return &ScriptLogs{}
+}
+
+func (l *ScriptLogs) Add(instance, job, phase string, output []byte) {
+	if l == nil {
+		return
+	}
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.logs = append(l.logs, ScriptLog{Instance: instance, Job: job, Phase: phase, Output: output})
+}
+
+// Take returns the logs collected so far, and forgets them
+func (l *ScriptLogs) Take() []ScriptLog {
+	if l == nil {
+		return nil
+	}
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	logs := l.logs
+	l.logs = nil
+	return logs
+}
@@ -0,0 +1,93 @@
+package orchestrator
+
+import "fmt"
+
+// Selection restricts a command to some of the instances and jobs of a deployment. Each
+// include list that is set must match, and nothing in an exclude list may match.
+// Instances are given as <instance-group>/<id or index>.
+type Selection struct {
+	InstanceGroups        []string
+	ExcludeInstanceGroups []string
+	Instances             []string
+	ExcludeInstances      []string
+	AZs                   []string
+	ExcludeAZs            []string
+	Jobs                  []string
+	ExcludeJobs           []string
+	Artifacts             []string
+}
+
+func (s Selection) IsEmpty() bool {
+	return len(s.InstanceGroups) == 0 && len(s.ExcludeInstanceGroups) == 0 &&
+		len(s.Instances) == 0 && len(s.ExcludeInstances) == 0 &&
+		len(s.AZs) == 0 && len(s.ExcludeAZs) == 0 &&
+		len(s.Jobs) == 0 && len(s.ExcludeJobs) == 0 &&
+		len(s.Artifacts) == 0
+}
+
+func (s Selection) IncludesInstanceGroup(instanceGroup string) bool {
+	return matchesFilter(s.InstanceGroups, s.ExcludeInstanceGroups, instanceGroup)
+}
+
+func (s Selection) IncludesInstance(instanceGroup, id, index, az string) bool {
+	if !s.IncludesInstanceGroup(instanceGroup) || !matchesFilter(s.AZs, s.ExcludeAZs, az) {
+		return false
+	}
+
+	byID := fmt.Sprintf("%s/%s", instanceGroup, id)
+	byIndex := fmt.Sprintf("%s/%s", instanceGroup, index)
+	if len(s.Instances) > 0 && !containsString(s.Instances, byID) && !containsString(s.Instances, byIndex) {
+		return false
+	}
+	return !containsString(s.ExcludeInstances, byID) && !containsString(s.ExcludeInstances, byIndex)
+}
+
+func (s Selection) IncludesJob(job string) bool {
+	return matchesFilter(s.Jobs, s.ExcludeJobs, job)
+}
+
+func (s Selection) IncludesArtifact(artifact string) bool {
+	return matchesFilter(s.Artifacts, nil, artifact)
+}
+
+// FilterJobs keeps the selected jobs whose artifact, named after the job unless it has a
+// custom name, is also selected.
+func (s Selection) FilterJobs(jobs Jobs) Jobs {
+	if len(s.Jobs) == 0 && len(s.ExcludeJobs) == 0 && len(s.Artifacts) == 0 {
+		return jobs
+	}
+
+	selectedJobs := Jobs{}
+	for _, job := range jobs {
+		if s.IncludesJob(job.Name()) && s.includesArtifactOf(job) {
+			selectedJobs = append(selectedJobs, job)
+		}
+	}
+	return selectedJobs
+}
+
+func (s Selection) includesArtifactOf(job Job) bool {
+	if s.IncludesArtifact(job.Name()) {
+		return true
+	}
+	if job.HasNamedBackupArtifact() && s.IncludesArtifact(job.BackupArtifactName()) {
+		return true
+	}
+	return job.HasNamedRestoreArtifact() && s.IncludesArtifact(job.RestoreArtifactName())
+}
+
+func matchesFilter(include, exclude []string, value string) bool {
+	if len(include) > 0 && !containsString(include, value) {
+		return false
+	}
+	return !containsString(exclude, value)
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
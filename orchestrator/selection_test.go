@@ -0,0 +1,65 @@
+package orchestrator_test
+
+import (
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Selection", func() {
+	It("includes everything when it is empty", func() {
+		selection := orchestrator.Selection{}
+
+		Expect(selection.IsEmpty()).To(BeTrue())
+		Expect(selection.IncludesInstance("redis", "abc", "0", "z1")).To(BeTrue())
+		Expect(selection.IncludesJob("redis-server")).To(BeTrue())
+	})
+
+	It("requires every include filter to match", func() {
+		selection := orchestrator.Selection{InstanceGroups: []string{"redis"}, AZs: []string{"z1"}}
+
+		Expect(selection.IncludesInstance("redis", "abc", "0", "z1")).To(BeTrue())
+		Expect(selection.IncludesInstance("redis", "abc", "0", "z2")).To(BeFalse())
+		Expect(selection.IncludesInstance("mysql", "abc", "0", "z1")).To(BeFalse())
+	})
+
+	It("matches instances by id or index", func() {
+		selection := orchestrator.Selection{Instances: []string{"redis/abc", "redis/2"}}
+
+		Expect(selection.IncludesInstance("redis", "abc", "0", "")).To(BeTrue())
+		Expect(selection.IncludesInstance("redis", "def", "2", "")).To(BeTrue())
+		Expect(selection.IncludesInstance("redis", "ghi", "1", "")).To(BeFalse())
+	})
+
+	It("excludes anything matching an exclude filter", func() {
+		selection := orchestrator.Selection{ExcludeInstances: []string{"redis/0"}, ExcludeAZs: []string{"z3"}}
+
+		Expect(selection.IncludesInstance("redis", "abc", "0", "z1")).To(BeFalse())
+		Expect(selection.IncludesInstance("redis", "def", "1", "z3")).To(BeFalse())
+		Expect(selection.IncludesInstance("redis", "def", "1", "z1")).To(BeTrue())
+	})
+
+	It("filters jobs by name", func() {
+		redis := new(fakes.FakeJob)
+		redis.NameReturns("redis")
+		consul := new(fakes.FakeJob)
+		consul.NameReturns("consul_agent")
+
+		selection := orchestrator.Selection{ExcludeJobs: []string{"consul_agent"}}
+
+		Expect(selection.FilterJobs(orchestrator.Jobs{redis, consul})).To(Equal(orchestrator.Jobs{redis}))
+	})
+	It("filters jobs by the name of their artifact", func() {
+		uaa := new(fakes.FakeJob)
+		uaa.NameReturns("uaa")
+		database := new(fakes.FakeJob)
+		database.NameReturns("bbr-uaadb")
+		database.HasNamedRestoreArtifactReturns(true)
+		database.RestoreArtifactNameReturns("uaa-database")
+
+		selection := orchestrator.Selection{Artifacts: []string{"uaa-database"}}
+
+		Expect(selection.FilterJobs(orchestrator.Jobs{uaa, database})).To(Equal(orchestrator.Jobs{database}))
+	})
+})
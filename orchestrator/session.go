@@ -5,6 +5,7 @@ type Session struct {
 	deployment          Deployment
 	currentArtifact     Backup
 	currentArtifactPath string
+	journal             *Journal
 }
 
 func NewSession(deploymentName string) *Session {
@@ -38,3 +39,12 @@ func (session *Session) SetCurrentArtifactPath(artifactPath string) {
 func (session *Session) CurrentArtifactPath() string {
 	return session.currentArtifactPath
 }
+
+func (session *Session) SetJournal(journal *Journal) {
+	session.journal = journal
+}
+
+// Journal is nil unless the run is journalled
+func (session *Session) Journal() *Journal {
+	return session.journal
+}
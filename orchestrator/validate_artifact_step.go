@@ -1,25 +1,30 @@
 package orchestrator
 
 import (
+	"context"
+	"path/filepath"
+
 	"github.com/pkg/errors"
 )
 
-func NewValidateArtifactStep(logger Logger, backupManager BackupManager) Step {
-	return &ValidateArtifactStep{logger: logger, backupManager: backupManager}
+func NewValidateArtifactStep(logger Logger, backupManager BackupManager, scriptEnvironment *ScriptEnvironment) Step {
+	return &ValidateArtifactStep{logger: logger, backupManager: backupManager, scriptEnvironment: scriptEnvironment}
 }
 
 type ValidateArtifactStep struct {
-	logger        Logger
-	backupManager BackupManager
+	logger            Logger
+	backupManager     BackupManager
+	scriptEnvironment *ScriptEnvironment
 }
 
-func (s *ValidateArtifactStep) Run(session *Session) error {
+func (s *ValidateArtifactStep) Run(ctx context.Context, session *Session) error {
 	s.logger.Info("bbr", "Starting restore of %s...\n", session.deploymentName)
 	backup, err := s.backupManager.Open(session.CurrentArtifactPath(), s.logger)
 	if err != nil {
 		return errors.Wrap(err, "Could not open backup")
 	}
 	session.SetCurrentArtifact(backup)
+	s.scriptEnvironment.SetRestoredBackup(filepath.Base(session.CurrentArtifactPath()), backup)
 
 	s.logger.Info("bbr", "Validating backup artifact for %s...\n", session.deploymentName)
 	if valid, err := backup.Valid(); err != nil {
@@ -1,31 +1,137 @@
 package orchestrator
 
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/event"
+)
+
 type Workflow struct {
 	StartingNode *Node
 	Nodes        []*Node
+	abort        *Abort
+	lockTimeout  time.Duration
 }
 
 func NewWorkflow() *Workflow {
 	return &Workflow{}
 }
 
-func (workflow *Workflow) Run(session *Session) Error {
+// NewAbortableWorkflow returns a workflow which, once aborted, skips to the steps which
+// unlock jobs and clean up. It does the same once jobs have been locked for lockTimeout,
+// if that is not zero.
+func NewAbortableWorkflow(abort *Abort, lockTimeout time.Duration) *Workflow {
+	return &Workflow{abort: abort, lockTimeout: lockTimeout}
+}
+
+// Run runs the steps of the workflow until ctx is done, then only runs the steps which
+// clean up. Those are given a context which is never done, so that jobs are always
+// unlocked.
+func (workflow *Workflow) Run(ctx context.Context, session *Session) Error {
 	var errs Error
 	currentNode := workflow.StartingNode
 
+	// the lock timeout applies from when jobs start to be locked until they are unlocked
+	var lockDeadline time.Time
+
 	for currentNode != nil {
-		err := currentNode.step.Run(session)
+		_, cleansUp := currentNode.step.(cleanupStep)
+		if cleansUp {
+			lockDeadline = time.Time{}
+		} else if stopErr := workflow.stopped(ctx, lockDeadline); stopErr != nil {
+			errs = withStopError(errs, stopErr)
+			currentNode = workflow.findNode(currentNode.failStep)
+			continue
+		}
+
+		if _, locks := currentNode.step.(lockStep); locks && workflow.lockTimeout > 0 {
+			lockDeadline = time.Now().Add(workflow.lockTimeout)
+		}
+
+		name := stepName(currentNode.step)
+		event.Record(event.Event{Type: event.StepStarted, Deployment: session.DeploymentName(), Step: name})
+		err := workflow.runStep(ctx, lockDeadline, currentNode.step, cleansUp, session)
+		event.Record(event.Event{Type: event.StepFinished, Deployment: session.DeploymentName(), Step: name}.WithResult(err))
 		if err != nil {
 			errs = append(errs, err)
+		}
+
+		if stopErr := workflow.stopped(ctx, lockDeadline); !cleansUp && stopErr != nil {
+			errs = withStopError(errs, stopErr)
+			currentNode = workflow.findNode(currentNode.failStep)
+		} else if err != nil {
 			currentNode = workflow.findNode(currentNode.failStep)
 		} else {
 			currentNode = workflow.findNode(currentNode.successStep)
 		}
 	}
 
+	recordDeploymentFinished(session.DeploymentName(), errs)
 	return errs
 }
 
+func (workflow *Workflow) runStep(ctx context.Context, lockDeadline time.Time, step Step, cleansUp bool, session *Session) error {
+	if cleansUp {
+		defer workflow.abort.startCleanup()()
+		return step.Run(context.Background(), session)
+	}
+
+	if !lockDeadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, lockDeadline)
+		defer cancel()
+	}
+	return step.Run(ctx, session)
+}
+
+// stopped returns why the workflow has to stop early, if it does
+func (workflow *Workflow) stopped(ctx context.Context, lockDeadline time.Time) error {
+	switch {
+	case workflow.abort.Aborted() || ctx.Err() == context.Canceled:
+		return NewAbortError("bbr was aborted")
+	case ctx.Err() != nil:
+		return NewTimeoutError(NewAbortError("bbr did not finish before its timeout"))
+	case !lockDeadline.IsZero() && !time.Now().Before(lockDeadline):
+		return NewTimeoutError(NewAbortError(fmt.Sprintf("jobs were locked for longer than the lock timeout of %s", workflow.lockTimeout)))
+	}
+	return nil
+}
+
+func withStopError(errs Error, stopErr error) Error {
+	for _, err := range errs {
+		if _, ok := withoutTimeout(err).(AbortError); ok {
+			return errs
+		}
+	}
+	return append(errs, stopErr)
+}
+
+func recordDeploymentFinished(deploymentName string, errs Error) {
+	deploymentFinished := event.Event{Type: event.DeploymentFinished, Deployment: deploymentName}
+	if len(errs) == 0 {
+		event.Record(deploymentFinished.WithResult(nil))
+		return
+	}
+
+	deploymentFinished.ErrorTypes = ErrorTypes(errs)
+	event.Record(deploymentFinished.WithResult(errs))
+}
+
+var wordBoundary = regexp.MustCompile("([a-z0-9])([A-Z])")
+
+// stepName turns the type of a step, such as *orchestrator.PostBackupUnlockStep, into
+// post-backup-unlock
+func stepName(step Step) string {
+	name := fmt.Sprintf("%T", step)
+	name = name[strings.LastIndex(name, ".")+1:]
+	name = strings.TrimSuffix(name, "Step")
+	return strings.ToLower(wordBoundary.ReplaceAllString(name, "$1-$2"))
+}
+
 func (workflow *Workflow) findNode(step Step) *Node {
 	if step == nil {
 		return nil
@@ -52,7 +158,13 @@ func (workflow *Workflow) StartWith(step Step) *Node {
 }
 
 type Step interface {
-	Run(*Session) error
+	Run(context.Context, *Session) error
+}
+
+// lockStep is implemented by steps which lock jobs
+type lockStep interface {
+	Step
+	locksJobs()
 }
 
 type Node struct {
@@ -0,0 +1,188 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bytesize"
+)
+
+const DefaultInterval = 30 * time.Second
+
+const (
+	statusRedrawInterval = 200 * time.Millisecond
+	barWidth             = 30
+)
+
+type Logger interface {
+	Info(tag, msg string, args ...interface{})
+}
+
+type StatusLine interface {
+	SetStatusLine(line string)
+}
+
+// Reporter logs the progress of each artifact transfer every interval and, when given a
+// status line, keeps an aggregate progress bar of all transfers in flight up to date.
+type Reporter struct {
+	interval    time.Duration
+	status      StatusLine
+	mux         sync.Mutex
+	inFlight    int
+	expected    int64
+	transferred int64
+	started     time.Time
+	lastDrawn   time.Time
+}
+
+func NewReporter(interval time.Duration, status StatusLine) *Reporter {
+	return &Reporter{interval: interval, status: status}
+}
+
+// Start begins tracking a transfer. The expected size is a size as reported by `du -sh`,
+// and is only used for percentages and ETAs, so it may be approximate or empty.
+func (r *Reporter) Start(description, size string, logger Logger) *Transfer {
+	expected, err := bytesize.Parse(size)
+	if err != nil {
+		expected = 0
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	now := time.Now()
+	if r.inFlight == 0 {
+		r.expected, r.transferred, r.started = 0, 0, now
+	}
+	r.inFlight++
+	r.expected += expected
+
+	return &Transfer{
+		reporter:     r,
+		description:  description,
+		logger:       logger,
+		expected:     expected,
+		started:      now,
+		lastReported: now,
+	}
+}
+
+func (r *Reporter) drawStatusLine(now time.Time) {
+	if r.status == nil || now.Sub(r.lastDrawn) < statusRedrawInterval {
+		return
+	}
+	r.lastDrawn = now
+
+	filled := barWidth * percentage(r.transferred, r.expected) / 100
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled)
+	r.status.SetStatusLine(fmt.Sprintf("Copying %d artifacts [%s] %s", r.inFlight, bar, summary(r.transferred, r.expected, now.Sub(r.started))))
+}
+
+func (r *Reporter) finish() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.inFlight--
+	if r.inFlight == 0 && r.status != nil {
+		r.lastDrawn = time.Time{}
+		r.status.SetStatusLine("")
+	}
+}
+
+type Transfer struct {
+	reporter     *Reporter
+	description  string
+	logger       Logger
+	expected     int64
+	transferred  int64
+	started      time.Time
+	lastReported time.Time
+}
+
+func (t *Transfer) Writer(w io.Writer) io.Writer {
+	return &countingWriter{out: w, transfer: t}
+}
+
+func (t *Transfer) Reader(r io.Reader) io.Reader {
+	return &countingReader{in: r, transfer: t}
+}
+
+func (t *Transfer) Finish() {
+	t.reporter.finish()
+}
+
+func (t *Transfer) add(n int) {
+	r := t.reporter
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	t.transferred += int64(n)
+	r.transferred += int64(n)
+
+	now := time.Now()
+	if now.Sub(t.lastReported) >= r.interval {
+		t.lastReported = now
+		t.logger.Info("bbr", "Copying backup -- %s -- %s", t.description, summary(t.transferred, t.expected, now.Sub(t.started)))
+	}
+	r.drawStatusLine(now)
+}
+
+// summary describes a transfer as, for example, 12G of 120G (10%), 95M/s, ETA 19m20s
+func summary(transferred, expected int64, elapsed time.Duration) string {
+	text := bytesize.Format(transferred)
+	if expected > 0 {
+		text += fmt.Sprintf(" of %s (%d%%)", bytesize.Format(expected), percentage(transferred, expected))
+	}
+
+	if elapsed < time.Second || transferred == 0 {
+		return text
+	}
+
+	bytesPerSecond := float64(transferred) / elapsed.Seconds()
+	text += fmt.Sprintf(", %s/s", bytesize.Format(int64(bytesPerSecond)))
+
+	if expected > transferred {
+		eta := time.Duration(float64(expected-transferred)/bytesPerSecond) * time.Second
+		text += fmt.Sprintf(", ETA %s", eta)
+	}
+	return text
+}
+
+func percentage(transferred, expected int64) int {
+	if expected <= 0 {
+		return 0
+	}
+	if transferred >= expected {
+		return 100
+	}
+	return int(transferred * 100 / expected)
+}
+
+type countingWriter struct {
+	out      io.Writer
+	transfer *Transfer
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.out.Write(p)
+	if n > 0 {
+		w.transfer.add(n)
+	}
+	return n, err
+}
+
+type countingReader struct {
+	in       io.Reader
+	transfer *Transfer
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.in.Read(p)
+	if n > 0 {
+		r.transfer.add(n)
+	}
+	return n, err
+}
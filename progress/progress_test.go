@@ -0,0 +1,98 @@
+package progress_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/progress"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Info(tag, msg string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(msg, args...))
+}
+
+type recordingStatusLine struct {
+	lines []string
+}
+
+func (s *recordingStatusLine) SetStatusLine(line string) {
+	s.lines = append(s.lines, line)
+}
+
+var _ = Describe("Reporter", func() {
+	var (
+		logger     *recordingLogger
+		statusLine *recordingStatusLine
+	)
+
+	BeforeEach(func() {
+		logger = new(recordingLogger)
+		statusLine = new(recordingStatusLine)
+	})
+
+	It("logs the progress of a transfer against its expected size", func() {
+		reporter := progress.NewReporter(0, nil)
+		out := new(bytes.Buffer)
+
+		transfer := reporter.Start("for job redis on redis/0", "4.0K", logger)
+		n, err := transfer.Writer(out).Write(make([]byte, 1024))
+		transfer.Finish()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(1024))
+		Expect(out.Len()).To(Equal(1024))
+		Expect(logger.lines).To(ConsistOf("Copying backup -- for job redis on redis/0 -- 1.0K of 4.0K (25%)"))
+	})
+
+	It("counts bytes read through a reader", func() {
+		reporter := progress.NewReporter(0, nil)
+
+		transfer := reporter.Start("for job redis on redis/0", "", logger)
+		data, err := ioutil.ReadAll(transfer.Reader(bytes.NewReader(make([]byte, 2048))))
+		transfer.Finish()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(HaveLen(2048))
+		Expect(logger.lines).To(ContainElement("Copying backup -- for job redis on redis/0 -- 2.0K"))
+	})
+
+	It("only logs once per interval", func() {
+		reporter := progress.NewReporter(progress.DefaultInterval, nil)
+
+		transfer := reporter.Start("for job redis on redis/0", "4.0K", logger)
+		transfer.Writer(ioutil.Discard).Write(make([]byte, 1024))
+		transfer.Writer(ioutil.Discard).Write(make([]byte, 1024))
+		transfer.Finish()
+
+		Expect(logger.lines).To(BeEmpty())
+	})
+
+	It("shows the aggregate progress of all transfers on the status line", func() {
+		reporter := progress.NewReporter(progress.DefaultInterval, statusLine)
+
+		redis := reporter.Start("for job redis on redis/0", "2.0K", logger)
+		reporter.Start("for job mysql on mysql/0", "2.0K", logger)
+		redis.Writer(ioutil.Discard).Write(make([]byte, 1024))
+
+		Expect(statusLine.lines).To(HaveLen(1))
+		Expect(statusLine.lines[0]).To(HavePrefix("Copying 2 artifacts [=======                       ] 1.0K of 4.0K (25%)"))
+	})
+
+	It("clears the status line once every transfer has finished", func() {
+		reporter := progress.NewReporter(progress.DefaultInterval, statusLine)
+
+		transfer := reporter.Start("for job redis on redis/0", "2.0K", logger)
+		transfer.Writer(ioutil.Discard).Write(make([]byte, 1024))
+		transfer.Finish()
+
+		Expect(statusLine.lines).To(HaveLen(2))
+		Expect(statusLine.lines[1]).To(BeEmpty())
+	})
+})
@@ -0,0 +1,144 @@
+package ratelimit
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+const maxChunkSize = 32 * 1024
+
+// Limiter allows a number of bytes per second, shared by every stream using it. Streams
+// reserve their bytes in small chunks and are served in the order they asked, so the
+// limit is split fairly between them.
+type Limiter struct {
+	bytesPerSecond float64
+	mux            sync.Mutex
+	available      float64
+	updatedAt      time.Time
+}
+
+func NewLimiter(bytesPerSecond int64) *Limiter {
+	return &Limiter{bytesPerSecond: float64(bytesPerSecond), updatedAt: time.Now()}
+}
+
+func (l *Limiter) Wait(n int) {
+	l.mux.Lock()
+	now := time.Now()
+	l.available += now.Sub(l.updatedAt).Seconds() * l.bytesPerSecond
+	if l.available > l.bytesPerSecond {
+		l.available = l.bytesPerSecond
+	}
+	l.updatedAt = now
+	l.available -= float64(n)
+	deficit := -l.available
+	l.mux.Unlock()
+
+	if deficit > 0 {
+		time.Sleep(time.Duration(deficit / l.bytesPerSecond * float64(time.Second)))
+	}
+}
+
+// Throttle limits artifact transfers to a total rate across all streams and to a rate per
+// stream. A rate of 0 is unlimited.
+type Throttle struct {
+	Total     *Limiter
+	PerStream int64
+}
+
+func NewThrottle(totalBytesPerSecond, perStreamBytesPerSecond int64) Throttle {
+	throttle := Throttle{PerStream: perStreamBytesPerSecond}
+	if totalBytesPerSecond > 0 {
+		throttle.Total = NewLimiter(totalBytesPerSecond)
+	}
+	return throttle
+}
+
+func (t Throttle) Writer(w io.Writer) io.Writer {
+	limiters := t.streamLimiters()
+	if len(limiters) == 0 {
+		return w
+	}
+	return &writer{out: w, limiters: limiters, chunkSize: chunkSize(limiters)}
+}
+
+func (t Throttle) Reader(r io.Reader) io.Reader {
+	limiters := t.streamLimiters()
+	if len(limiters) == 0 {
+		return r
+	}
+	return &reader{in: r, limiters: limiters, chunkSize: chunkSize(limiters)}
+}
+
+func (t Throttle) streamLimiters() []*Limiter {
+	var limiters []*Limiter
+	if t.Total != nil {
+		limiters = append(limiters, t.Total)
+	}
+	if t.PerStream > 0 {
+		limiters = append(limiters, NewLimiter(t.PerStream))
+	}
+	return limiters
+}
+
+func chunkSize(limiters []*Limiter) int {
+	size := maxChunkSize
+	for _, limiter := range limiters {
+		if int(limiter.bytesPerSecond) < size {
+			size = int(limiter.bytesPerSecond)
+		}
+	}
+	if size < 1 {
+		return 1
+	}
+	return size
+}
+
+func wait(limiters []*Limiter, n int) {
+	for _, limiter := range limiters {
+		limiter.Wait(n)
+	}
+}
+
+type writer struct {
+	out       io.Writer
+	limiters  []*Limiter
+	chunkSize int
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > w.chunkSize {
+			chunk = chunk[:w.chunkSize]
+		}
+
+		wait(w.limiters, len(chunk))
+		n, err := w.out.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+type reader struct {
+	in        io.Reader
+	limiters  []*Limiter
+	chunkSize int
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if len(p) > r.chunkSize {
+		p = p[:r.chunkSize]
+	}
+
+	n, err := r.in.Read(p)
+	if n > 0 {
+		wait(r.limiters, n)
+	}
+	return n, err
+}
@@ -0,0 +1,66 @@
+package ratelimit_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ratelimit"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Throttle", func() {
+	data := bytes.Repeat([]byte("a"), 20*1024)
+
+	It("passes streams through untouched when there is no limit", func() {
+		out := new(bytes.Buffer)
+		w := ratelimit.NewThrottle(0, 0).Writer(out)
+
+		Expect(w).To(BeIdenticalTo(out))
+	})
+
+	It("limits the rate of each stream", func() {
+		out := new(bytes.Buffer)
+		w := ratelimit.NewThrottle(0, 100*1024).Writer(out)
+
+		start := time.Now()
+		n, err := w.Write(data)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(len(data)))
+		Expect(out.Bytes()).To(Equal(data))
+		Expect(time.Since(start)).To(BeNumerically(">=", 150*time.Millisecond))
+	})
+
+	It("limits the rate of readers", func() {
+		r := ratelimit.NewThrottle(0, 100*1024).Reader(bytes.NewReader(data))
+
+		start := time.Now()
+		read, err := ioutil.ReadAll(r)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(read).To(Equal(data))
+		Expect(time.Since(start)).To(BeNumerically(">=", 150*time.Millisecond))
+	})
+
+	It("shares the total rate between streams", func() {
+		throttle := ratelimit.NewThrottle(100*1024, 0)
+
+		start := time.Now()
+		wg := sync.WaitGroup{}
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, err := throttle.Writer(ioutil.Discard).Write(data[:10*1024])
+				Expect(err).NotTo(HaveOccurred())
+			}()
+		}
+		wg.Wait()
+
+		Expect(time.Since(start)).To(BeNumerically(">=", 150*time.Millisecond))
+	})
+})
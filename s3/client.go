@@ -0,0 +1,222 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	URLScheme = "s3://"
+
+	defaultRegion   = "us-east-1"
+	defaultPartSize = 16 * 1024 * 1024
+	minimumPartSize = 5 * 1024 * 1024
+)
+
+var ErrNotFound = errors.New("object not found")
+
+type Client struct {
+	endpoint        *url.URL
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	partSize        int
+	httpClient      *http.Client
+	nowFunc         func() time.Time
+}
+
+func NewClient(endpoint, region, accessKeyID, secretAccessKey, sessionToken string) (*Client, error) {
+	if region == "" {
+		region = defaultRegion
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid S3 endpoint %s", endpoint)
+	}
+	if endpointURL.Scheme != "http" && endpointURL.Scheme != "https" {
+		return nil, errors.Errorf("invalid S3 endpoint %s: scheme must be http or https", endpoint)
+	}
+
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, errors.New("S3 credentials are required: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+
+	return &Client{
+		endpoint:        endpointURL,
+		region:          region,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		sessionToken:    sessionToken,
+		partSize:        defaultPartSize,
+		httpClient:      http.DefaultClient,
+		nowFunc:         time.Now,
+	}, nil
+}
+
+// NewClientFromEnvironment reads the standard AWS environment variables. AWS_ENDPOINT_URL
+// points the client at an S3-compatible store such as MinIO.
+func NewClientFromEnvironment() (*Client, error) {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+
+	return NewClient(
+		os.Getenv("AWS_ENDPOINT_URL"),
+		region,
+		os.Getenv("AWS_ACCESS_KEY_ID"),
+		os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		os.Getenv("AWS_SESSION_TOKEN"),
+	)
+}
+
+func (c *Client) SetPartSize(partSize int) {
+	if partSize < minimumPartSize {
+		partSize = minimumPartSize
+	}
+	c.partSize = partSize
+}
+
+func IsURL(path string) bool {
+	return strings.HasPrefix(path, URLScheme)
+}
+
+// ParseURL splits s3://bucket/some/prefix into its bucket and key prefix.
+func ParseURL(path string) (string, string, error) {
+	if !IsURL(path) {
+		return "", "", errors.Errorf("%s is not an s3:// URL", path)
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(path, URLScheme), "/", 2)
+	if parts[0] == "" {
+		return "", "", errors.Errorf("%s does not name a bucket", path)
+	}
+
+	prefix := ""
+	if len(parts) == 2 {
+		prefix = strings.Trim(parts[1], "/")
+	}
+	return parts[0], prefix, nil
+}
+
+func (c *Client) Get(bucket, key string) (io.ReadCloser, error) {
+	response, err := c.do("GET", bucket, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		defer response.Body.Close()
+		return nil, responseError(response, "GET", bucket, key)
+	}
+	return response.Body, nil
+}
+
+// Head returns the size of the object in bytes, or ErrNotFound if there is no such object.
+func (c *Client) Head(bucket, key string) (int64, error) {
+	response, err := c.do("HEAD", bucket, key, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return 0, responseError(response, "HEAD", bucket, key)
+	}
+	return response.ContentLength, nil
+}
+
+func (c *Client) Put(bucket, key string, body []byte) error {
+	response, err := c.do("PUT", bucket, key, nil, body)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return responseError(response, "PUT", bucket, key)
+	}
+	return nil
+}
+
+// NewUpload returns a writer which streams into the object in parts. The object only
+// becomes visible once the writer is closed, unless it is closed with CloseWithError.
+func (c *Client) NewUpload(bucket, key string) io.WriteCloser {
+	return &upload{client: c, bucket: bucket, key: key}
+}
+
+// objectURL escapes each segment of the key, so keys holding characters such as ?, # or %
+// still name the object rather than being read as the query or fragment of the URL.
+func (c *Client) objectURL(bucket, key string, query url.Values) *url.URL {
+	segments := strings.Split(bucket+"/"+key, "/")
+	escapedSegments := make([]string, len(segments))
+	for i, segment := range segments {
+		escapedSegments[i] = url.PathEscape(segment)
+	}
+
+	objectURL := *c.endpoint
+	basePath := strings.TrimSuffix(c.endpoint.Path, "/")
+	objectURL.Path = basePath + "/" + strings.Join(segments, "/")
+	objectURL.RawPath = strings.TrimSuffix(c.endpoint.EscapedPath(), "/") + "/" + strings.Join(escapedSegments, "/")
+	objectURL.RawQuery = query.Encode()
+	return &objectURL
+}
+
+func (c *Client) do(method, bucket, key string, query url.Values, body []byte) (*http.Response, error) {
+	request, err := http.NewRequest(method, c.objectURL(bucket, key, query).String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build %s request for %s/%s", method, bucket, key)
+	}
+	request.ContentLength = int64(len(body))
+
+	c.sign(request, body)
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s/%s failed", method, bucket, key)
+	}
+	return response, nil
+}
+
+type errorResponse struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func responseError(response *http.Response, method, bucket, key string) error {
+	if response.StatusCode == http.StatusNotFound {
+		return errors.Wrapf(ErrNotFound, "%s/%s", bucket, key)
+	}
+
+	body, _ := ioutil.ReadAll(response.Body)
+	if err := bodyError(body, method, bucket, key); err != nil {
+		return err
+	}
+	return errors.Errorf("%s %s/%s failed with status %s", method, bucket, key, response.Status)
+}
+
+func bodyError(body []byte, method, bucket, key string) error {
+	var s3Error errorResponse
+	if xml.Unmarshal(body, &s3Error) == nil && s3Error.Code != "" {
+		return errors.Errorf("%s %s/%s failed: %s: %s", method, bucket, key, s3Error.Code, s3Error.Message)
+	}
+	return nil
+}
+
+func IsNotFound(err error) bool {
+	return errors.Cause(err) == ErrNotFound
+}
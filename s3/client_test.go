@@ -0,0 +1,182 @@
+package s3_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/s3"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/s3/s3test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var server *s3test.Server
+	var client *s3.Client
+
+	BeforeEach(func() {
+		server = s3test.NewServer()
+
+		var err error
+		client, err = s3.NewClient(server.URL, "eu-west-1", "access-key", "secret-key", "")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("NewClient", func() {
+		It("requires credentials", func() {
+			_, err := s3.NewClient(server.URL, "", "", "", "")
+			Expect(err).To(MatchError(ContainSubstring("S3 credentials are required")))
+		})
+
+		It("rejects endpoints which are not http or https", func() {
+			_, err := s3.NewClient("ftp://example.com", "", "access-key", "secret-key", "")
+			Expect(err).To(MatchError(ContainSubstring("scheme must be http or https")))
+		})
+	})
+
+	Describe("ParseURL", func() {
+		It("splits the bucket from the prefix", func() {
+			bucket, prefix, err := s3.ParseURL("s3://my-bucket/backups/nightly/")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bucket).To(Equal("my-bucket"))
+			Expect(prefix).To(Equal("backups/nightly"))
+		})
+
+		It("allows a URL with no prefix", func() {
+			bucket, prefix, err := s3.ParseURL("s3://my-bucket")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bucket).To(Equal("my-bucket"))
+			Expect(prefix).To(Equal(""))
+		})
+
+		It("fails when no bucket is given", func() {
+			_, _, err := s3.ParseURL("s3:///backups")
+			Expect(err).To(MatchError(ContainSubstring("does not name a bucket")))
+		})
+
+		It("fails for paths which are not s3 URLs", func() {
+			Expect(s3.IsURL("/var/backups")).To(BeFalse())
+			_, _, err := s3.ParseURL("/var/backups")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Put, Get and Head", func() {
+		It("round trips an object", func() {
+			Expect(client.Put("bucket", "some/key", []byte("contents"))).To(Succeed())
+
+			size, err := client.Head("bucket", "some/key")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(size).To(Equal(int64(8)))
+
+			reader, err := client.Get("bucket", "some/key")
+			Expect(err).NotTo(HaveOccurred())
+			defer reader.Close()
+			Expect(ioutil.ReadAll(reader)).To(Equal([]byte("contents")))
+		})
+
+		It("escapes keys which hold characters with a meaning in URLs", func() {
+			key := "my backup/a+b%c?d#e.tar"
+			Expect(client.Put("bucket", key, []byte("contents"))).To(Succeed())
+
+			reader, err := client.Get("bucket", key)
+			Expect(err).NotTo(HaveOccurred())
+			defer reader.Close()
+			Expect(ioutil.ReadAll(reader)).To(Equal([]byte("contents")))
+
+			Expect(server.Requests).To(ContainElement("PUT /bucket/my%20backup/a%2Bb%25c%3Fd%23e.tar"))
+		})
+
+		It("reports missing objects as not found", func() {
+			_, err := client.Head("bucket", "missing")
+			Expect(s3.IsNotFound(err)).To(BeTrue())
+
+			_, err = client.Get("bucket", "missing")
+			Expect(s3.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Describe("NewUpload", func() {
+		var upload io.WriteCloser
+
+		BeforeEach(func() {
+			client.SetPartSize(0)
+			upload = client.NewUpload("bucket", "artifact.tar")
+		})
+
+		Context("when the object is smaller than a part", func() {
+			It("uploads it with a single PUT", func() {
+				_, err := upload.Write([]byte("small"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(upload.Close()).To(Succeed())
+
+				object, found := server.Object("bucket", "artifact.tar")
+				Expect(found).To(BeTrue())
+				Expect(object).To(Equal([]byte("small")))
+				Expect(server.Requests).To(Equal([]string{"PUT /bucket/artifact.tar"}))
+			})
+		})
+
+		Context("when the object spans several parts", func() {
+			var contents []byte
+
+			BeforeEach(func() {
+				contents = bytes.Repeat([]byte("0123456789"), 1200*1024)
+			})
+
+			It("streams it as a multipart upload", func() {
+				_, err := io.Copy(upload, bytes.NewReader(contents))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(upload.Close()).To(Succeed())
+
+				object, found := server.Object("bucket", "artifact.tar")
+				Expect(found).To(BeTrue())
+				Expect(object).To(Equal(contents))
+				Expect(server.Requests).To(Equal([]string{
+					"POST /bucket/artifact.tar?uploads=",
+					"PUT /bucket/artifact.tar?partNumber=1&uploadId=1",
+					"PUT /bucket/artifact.tar?partNumber=2&uploadId=1",
+					"PUT /bucket/artifact.tar?partNumber=3&uploadId=1",
+					"POST /bucket/artifact.tar?uploadId=1",
+				}))
+			})
+
+			Context("when uploading a part fails", func() {
+				BeforeEach(func() {
+					server.FailPartsFrom = 2
+				})
+
+				It("aborts the upload and returns the error", func() {
+					_, err := io.Copy(upload, bytes.NewReader(contents))
+					Expect(err).To(MatchError(ContainSubstring("part upload failed")))
+					Expect(upload.Close()).To(MatchError(ContainSubstring("part upload failed")))
+
+					_, found := server.Object("bucket", "artifact.tar")
+					Expect(found).To(BeFalse())
+					Expect(server.PendingUploads()).To(BeZero())
+				})
+			})
+
+			Context("when the upload is closed with an error", func() {
+				It("aborts the upload and returns the error", func() {
+					_, err := io.Copy(upload, bytes.NewReader(contents))
+					Expect(err).NotTo(HaveOccurred())
+
+					abortable := upload.(interface{ CloseWithError(error) error })
+					Expect(abortable.CloseWithError(fmt.Errorf("stream failed"))).To(MatchError("stream failed"))
+
+					_, found := server.Object("bucket", "artifact.tar")
+					Expect(found).To(BeFalse())
+					Expect(server.PendingUploads()).To(BeZero())
+					Expect(server.Requests).To(ContainElement("DELETE /bucket/artifact.tar?uploadId=1"))
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,152 @@
+package s3test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server is an in-memory stand-in for the subset of the S3 API used by bbr. It does not
+// verify request signatures, only that requests are signed.
+type Server struct {
+	*httptest.Server
+
+	sync.Mutex
+	objects       map[string][]byte
+	uploads       map[string]map[int][]byte
+	nextUploadID  int
+	Requests      []string
+	FailPartsFrom int
+}
+
+func NewServer() *Server {
+	server := &Server{
+		objects: map[string][]byte{},
+		uploads: map[string]map[int][]byte{},
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	return server
+}
+
+func (s *Server) Object(bucket, key string) ([]byte, bool) {
+	s.Lock()
+	defer s.Unlock()
+	contents, found := s.objects[bucket+"/"+key]
+	return contents, found
+}
+
+func (s *Server) PutObject(bucket, key string, contents []byte) {
+	s.Lock()
+	defer s.Unlock()
+	s.objects[bucket+"/"+key] = contents
+}
+
+func (s *Server) Keys() []string {
+	s.Lock()
+	defer s.Unlock()
+	var keys []string
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *Server) PendingUploads() int {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.uploads)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.Requests = append(s.Requests, r.Method+" "+r.URL.RequestURI())
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		writeError(w, http.StatusForbidden, "AccessDenied", "request is not signed")
+		return
+	}
+
+	objectKey := strings.TrimPrefix(r.URL.Path, "/")
+	query := r.URL.Query()
+	body, _ := ioutil.ReadAll(r.Body)
+
+	switch {
+	case r.Method == "POST" && query["uploads"] != nil:
+		s.nextUploadID++
+		uploadID := strconv.Itoa(s.nextUploadID)
+		s.uploads[uploadID] = map[int][]byte{}
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", uploadID)
+
+	case r.Method == "PUT" && query.Get("uploadId") != "":
+		parts, found := s.uploads[query.Get("uploadId")]
+		if !found {
+			writeError(w, http.StatusNotFound, "NoSuchUpload", "no such upload")
+			return
+		}
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		if s.FailPartsFrom > 0 && partNumber >= s.FailPartsFrom {
+			writeError(w, http.StatusInternalServerError, "InternalError", "part upload failed")
+			return
+		}
+		parts[partNumber] = body
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, partNumber))
+
+	case r.Method == "POST" && query.Get("uploadId") != "":
+		parts, found := s.uploads[query.Get("uploadId")]
+		if !found {
+			writeError(w, http.StatusNotFound, "NoSuchUpload", "no such upload")
+			return
+		}
+		var complete struct {
+			Parts []struct {
+				PartNumber int
+			} `xml:"Part"`
+		}
+		if err := xml.Unmarshal(body, &complete); err != nil {
+			writeError(w, http.StatusBadRequest, "MalformedXML", err.Error())
+			return
+		}
+		var contents []byte
+		for _, part := range complete.Parts {
+			contents = append(contents, parts[part.PartNumber]...)
+		}
+		s.objects[objectKey] = contents
+		delete(s.uploads, query.Get("uploadId"))
+		fmt.Fprint(w, "<CompleteMultipartUploadResult></CompleteMultipartUploadResult>")
+
+	case r.Method == "DELETE" && query.Get("uploadId") != "":
+		delete(s.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == "PUT":
+		s.objects[objectKey] = body
+
+	case r.Method == "GET" || r.Method == "HEAD":
+		contents, found := s.objects[objectKey]
+		if !found {
+			writeError(w, http.StatusNotFound, "NoSuchKey", "no such key")
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(contents)))
+		if r.Method == "GET" {
+			w.Write(contents)
+		}
+
+	default:
+		writeError(w, http.StatusNotImplemented, "NotImplemented", r.Method+" is not supported")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, message)
+}
@@ -0,0 +1,164 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+type upload struct {
+	client   *Client
+	bucket   string
+	key      string
+	buffer   bytes.Buffer
+	uploadID string
+	parts    []completedPart
+	err      error
+}
+
+type initiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+func (u *upload) Write(data []byte) (int, error) {
+	if u.err != nil {
+		return 0, u.err
+	}
+
+	written, _ := u.buffer.Write(data)
+	for u.buffer.Len() >= u.client.partSize {
+		if err := u.uploadPart(u.buffer.Next(u.client.partSize)); err != nil {
+			u.abort(err)
+			return written, u.err
+		}
+	}
+	return written, nil
+}
+
+// Close finishes the upload. Objects smaller than a single part are sent with one PUT.
+func (u *upload) Close() error {
+	if u.err != nil {
+		return u.err
+	}
+
+	if u.uploadID == "" {
+		u.err = u.client.Put(u.bucket, u.key, u.buffer.Bytes())
+		return u.err
+	}
+
+	if u.buffer.Len() > 0 {
+		if err := u.uploadPart(u.buffer.Bytes()); err != nil {
+			u.abort(err)
+			return u.err
+		}
+	}
+
+	if err := u.complete(); err != nil {
+		u.abort(err)
+		return u.err
+	}
+	u.err = errors.New("upload already completed")
+	return nil
+}
+
+// CloseWithError abandons the upload, so nothing written to it becomes visible, and returns
+// the given error. It does nothing once the upload has been closed.
+func (u *upload) CloseWithError(cause error) error {
+	if u.err == nil {
+		u.abort(cause)
+	}
+	return cause
+}
+
+func (u *upload) uploadPart(part []byte) error {
+	if u.uploadID == "" {
+		if err := u.initiate(); err != nil {
+			return err
+		}
+	}
+
+	partNumber := len(u.parts) + 1
+	query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {u.uploadID}}
+	response, err := u.client.do("PUT", u.bucket, u.key, query, part)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return responseError(response, "PUT part", u.bucket, u.key)
+	}
+
+	u.parts = append(u.parts, completedPart{PartNumber: partNumber, ETag: response.Header.Get("ETag")})
+	return nil
+}
+
+func (u *upload) initiate() error {
+	response, err := u.client.do("POST", u.bucket, u.key, url.Values{"uploads": {""}}, nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return responseError(response, "POST uploads", u.bucket, u.key)
+	}
+
+	var result initiateMultipartUploadResult
+	if err := xml.NewDecoder(response.Body).Decode(&result); err != nil {
+		return errors.Wrapf(err, "failed to start upload of %s/%s", u.bucket, u.key)
+	}
+	u.uploadID = result.UploadID
+	return nil
+}
+
+func (u *upload) complete() error {
+	body, err := xml.Marshal(completeMultipartUpload{Parts: u.parts})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal upload parts")
+	}
+
+	response, err := u.client.do("POST", u.bucket, u.key, url.Values{"uploadId": {u.uploadID}}, body)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return responseError(response, "POST complete upload", u.bucket, u.key)
+	}
+
+	// S3 can report a failed completion with a 200 status and an error document
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to complete upload of %s/%s", u.bucket, u.key)
+	}
+	return bodyError(responseBody, "POST complete upload", u.bucket, u.key)
+}
+
+func (u *upload) abort(cause error) {
+	u.err = cause
+	if u.uploadID == "" {
+		return
+	}
+
+	response, err := u.client.do("DELETE", u.bucket, u.key, url.Values{"uploadId": {u.uploadID}}, nil)
+	if err == nil {
+		response.Body.Close()
+	}
+}
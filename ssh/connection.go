@@ -2,8 +2,11 @@ package ssh
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 
 	"time"
 
@@ -24,14 +27,20 @@ type SSHConnection interface {
 	Stream(cmd string, writer io.Writer) ([]byte, int, error)
 	StreamStdin(cmd string, reader io.Reader) ([]byte, []byte, int, error)
 	Run(cmd string) ([]byte, []byte, int, error)
+	RunStreaming(ctx context.Context, cmd string, stdout, stderr io.Writer) (int, error)
 	Username() string
+	Close() error
 }
 
 type Logger interface {
+	Info(tag, msg string, args ...interface{})
 	Warn(tag, msg string, args ...interface{})
 	Debug(tag, msg string, args ...interface{})
 }
 
+// ProxyLogOutput is where the SOCKS5 proxy used to reach instances through BOSH_ALL_PROXY logs to
+var ProxyLogOutput io.Writer = os.Stdout
+
 var dialFunc boshhttp.DialFunc
 var dialFuncMutex sync.RWMutex
 
@@ -45,7 +54,7 @@ func NewConnectionWithServerAliveInterval(hostName, userName, privateKey string,
 		return nil, errors.Wrap(err, "ssh.NewConnection.ParsePrivateKey failed")
 	}
 
-	conn := Connection{
+	conn := &Connection{
 		host: defaultToSSHPort(hostName),
 		sshConfig: &ssh.ClientConfig{
 			User: userName,
@@ -63,15 +72,20 @@ func NewConnectionWithServerAliveInterval(hostName, userName, privateKey string,
 	return conn, nil
 }
 
+// Connection keeps a single ssh client open to the host and runs each command in a new
+// session on it, reconnecting if the client has died since it was last used.
 type Connection struct {
 	host                string
 	sshConfig           *ssh.ClientConfig
 	logger              Logger
 	serverAliveInterval time.Duration
 	dialFunc            boshhttp.DialFunc
+
+	client      *ssh.Client
+	clientMutex sync.Mutex
 }
 
-func (c Connection) Run(cmd string) (stdout, stderr []byte, exitCode int, err error) {
+func (c *Connection) Run(cmd string) (stdout, stderr []byte, exitCode int, err error) {
 	stdoutBuffer := bytes.NewBuffer([]byte{})
 
 	stderr, exitCode, err = c.Stream(cmd, stdoutBuffer)
@@ -79,19 +93,28 @@ func (c Connection) Run(cmd string) (stdout, stderr []byte, exitCode int, err er
 	return stdoutBuffer.Bytes(), stderr, exitCode, errors.Wrap(err, "ssh.Run failed")
 }
 
-func (c Connection) Stream(cmd string, stdoutWriter io.Writer) (stderr []byte, exitCode int, err error) {
+func (c *Connection) Stream(cmd string, stdoutWriter io.Writer) (stderr []byte, exitCode int, err error) {
 	errBuffer := bytes.NewBuffer([]byte{})
 
-	exitCode, err = c.runInSession(cmd, stdoutWriter, errBuffer, nil)
+	exitCode, err = c.runInSession(context.Background(), cmd, stdoutWriter, errBuffer, nil, false)
 
 	return errBuffer.Bytes(), exitCode, errors.Wrap(err, "ssh.Stream failed")
 }
 
-func (c Connection) StreamStdin(cmd string, stdinReader io.Reader) (stdout, stderr []byte, exitCode int, err error) {
+// RunStreaming writes the output of the command as it arrives, rather than once it exits.
+// The command is interrupted if ctx is done before it exits, and killed on the instance,
+// along with any processes it started.
+func (c *Connection) RunStreaming(ctx context.Context, cmd string, stdout, stderr io.Writer) (int, error) {
+	exitCode, err := c.runInSession(ctx, cmd, stdout, stderr, nil, true)
+
+	return exitCode, errors.Wrap(err, "ssh.RunStreaming failed")
+}
+
+func (c *Connection) StreamStdin(cmd string, stdinReader io.Reader) (stdout, stderr []byte, exitCode int, err error) {
 	stdoutBuffer := bytes.NewBuffer([]byte{})
 	stderrBuffer := bytes.NewBuffer([]byte{})
 
-	exitCode, err = c.runInSession(cmd, stdoutBuffer, stderrBuffer, stdinReader)
+	exitCode, err = c.runInSession(context.Background(), cmd, stdoutBuffer, stderrBuffer, stdinReader, false)
 
 	return stdoutBuffer.Bytes(), stderrBuffer.Bytes(), exitCode, errors.Wrap(err, "ssh.StreamStdin failed")
 }
@@ -111,7 +134,7 @@ func (w *sessionClosingOnErrorWriter) Write(data []byte) (int, error) {
 	return n, err
 }
 
-func (c Connection) newClient() (*ssh.Client, error) {
+func (c *Connection) newClient() (*ssh.Client, error) {
 	conn, err := c.dialFunc("tcp", c.host)
 	if err != nil {
 		return nil, err
@@ -119,12 +142,100 @@ func (c Connection) newClient() (*ssh.Client, error) {
 
 	client, chans, reqs, err := ssh.NewClientConn(conn, c.host, c.sshConfig)
 	if err != nil {
+		conn.Close()
 		return nil, err
 	}
 
 	return ssh.NewClient(client, chans, reqs), nil
 }
 
+func (c *Connection) newSession() (*ssh.Session, func(), error) {
+	client, err := c.sharedClient()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "ssh.Dial failed")
+	}
+
+	session, err := client.NewSession()
+	if err == nil {
+		return session, func() { session.Close() }, nil
+	}
+
+	if _, _, pingErr := client.SendRequest("keepalive@bbr", true, nil); pingErr == nil {
+		// the connection is healthy but the server refused another session, most likely
+		// because of its MaxSessions limit, so run this command on a connection of its own
+		c.logger.Debug("bbr", "Unable to open another session on the connection to %s, using a new connection: %s", c.host, err)
+		return c.newDedicatedSession()
+	}
+
+	c.logger.Debug("bbr", "Connection to %s was lost, reconnecting: %s", c.host, err)
+	c.discardClient(client)
+
+	client, err = c.sharedClient()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "ssh.Dial failed")
+	}
+
+	session, err = client.NewSession()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "ssh.NewSession failed")
+	}
+	return session, func() { session.Close() }, nil
+}
+
+func (c *Connection) newDedicatedSession() (*ssh.Session, func(), error) {
+	client, err := c.newClient()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "ssh.Dial failed")
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		client.Close()
+		return nil, nil, errors.Wrap(err, "ssh.NewSession failed")
+	}
+	return session, func() { session.Close(); client.Close() }, nil
+}
+
+func (c *Connection) sharedClient() (*ssh.Client, error) {
+	c.clientMutex.Lock()
+	defer c.clientMutex.Unlock()
+
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	client, err := c.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	c.client = client
+	return client, nil
+}
+
+func (c *Connection) discardClient(client *ssh.Client) {
+	c.clientMutex.Lock()
+	defer c.clientMutex.Unlock()
+
+	if c.client == client {
+		c.client = nil
+	}
+	client.Close()
+}
+
+func (c *Connection) Close() error {
+	c.clientMutex.Lock()
+	defer c.clientMutex.Unlock()
+
+	if c.client == nil {
+		return nil
+	}
+
+	err := c.client.Close()
+	c.client = nil
+	return errors.Wrap(err, "ssh.Close failed")
+}
+
 func createDialFunc() boshhttp.DialFunc {
 	dialFuncMutex.RLock()
 	haveDialer := dialFunc != nil
@@ -137,24 +248,27 @@ func createDialFunc() boshhttp.DialFunc {
 	dialFuncMutex.Lock()
 	defer dialFuncMutex.Unlock()
 
-	socksProxy := proxy.NewSocks5Proxy(proxy.NewHostKey(), log.New(os.Stdout, "sock5-proxy", log.LstdFlags))
+	socksProxy := proxy.NewSocks5Proxy(proxy.NewHostKey(), log.New(ProxyLogOutput, "sock5-proxy", log.LstdFlags))
 	dialFunc = boshhttp.SOCKS5DialFuncFromEnvironment(net.Dial, socksProxy)
 	return dialFunc
 }
 
-func (c Connection) runInSession(cmd string, stdout, stderr io.Writer, stdin io.Reader) (int, error) {
-	client, err := c.newClient()
+func (c *Connection) runInSession(ctx context.Context, cmd string, stdout, stderr io.Writer, stdin io.Reader, killable bool) (int, error) {
+	session, closeSession, err := c.newSession()
 	if err != nil {
-		return -1, errors.Wrap(err, "ssh.Dial failed")
+		return -1, err
 	}
-	defer client.Close()
+	defer closeSession()
 
-	session, err := client.NewSession()
-	if err != nil {
-		return -1, errors.Wrap(err, "ssh.NewSession failed")
-	}
 	c.logger.Debug("bbr", "Trying to execute '%s' on remote", cmd)
 
+	var kill func()
+	if killable {
+		processGroupFile := nextProcessGroupFile()
+		kill = func() { c.killProcessGroup(processGroupFile, cmd) }
+		cmd = recordingProcessGroup(cmd, processGroupFile)
+	}
+
 	stopKeepAliveLoop := c.startKeepAliveLoop(session)
 	defer close(stopKeepAliveLoop)
 
@@ -166,7 +280,18 @@ func (c Connection) runInSession(cmd string, stdout, stderr io.Writer, stdin io.
 
 	var exitCode int
 
+	if err := Sessions.start(session, kill); err != nil {
+		return -1, err
+	}
+	stopWatching := interruptWhenDone(ctx, session, kill)
 	err = session.Run(cmd)
+	stopWatching()
+	if Sessions.finish(session) {
+		return -1, ErrInterrupted
+	}
+	if ctx.Err() != nil {
+		return -1, ctx.Err()
+	}
 
 	if err == nil && stdoutWrappingWriter.writerError == nil {
 		exitCode = 0
@@ -183,7 +308,39 @@ func (c Connection) runInSession(cmd string, stdout, stderr io.Writer, stdin io.
 	return exitCode, nil
 }
 
-func (c Connection) startKeepAliveLoop(session *ssh.Session) chan struct{} {
+var processGroupFiles uint64
+
+func nextProcessGroupFile() string {
+	return fmt.Sprintf(`"$HOME/.bbr-%d-%d.pgid"`, os.Getpid(), atomic.AddUint64(&processGroupFiles, 1))
+}
+
+// recordingProcessGroup writes the process group of the command to a file while it runs. The
+// ssh server starts each command in a session of its own, so the process group is the PID of
+// the shell running the command, and holds the processes it starts as root.
+func recordingProcessGroup(cmd, processGroupFile string) string {
+	return fmt.Sprintf("echo $$ > %[1]s; %[2]s; status=$?; rm -f %[1]s; exit $status", processGroupFile, cmd)
+}
+
+// killProcessGroup stops a command run by recordingProcessGroup. Closing its session is not
+// enough, as the processes it started with sudo cannot be signalled over ssh.
+func (c *Connection) killProcessGroup(processGroupFile, cmd string) {
+	session, closeSession, err := c.newSession()
+	if err != nil {
+		c.logger.Warn("bbr", "Unable to stop '%s' on %s, so it may still be running: %s", cmd, c.host, err)
+		return
+	}
+	defer closeSession()
+
+	output, err := session.CombinedOutput(fmt.Sprintf(
+		`if [ -f %[1]s ]; then pgid=$(cat %[1]s) && rm -f %[1]s && { sudo kill -TERM -- "-$pgid" || ! sudo kill -0 -- "-$pgid"; }; fi`,
+		processGroupFile,
+	))
+	if err != nil {
+		c.logger.Warn("bbr", "Unable to stop '%s' on %s, so it may still be running: %s %s", cmd, c.host, err, strings.TrimSpace(string(output)))
+	}
+}
+
+func (c *Connection) startKeepAliveLoop(session *ssh.Session) chan struct{} {
 	terminate := make(chan struct{})
 	go func() {
 		for {
@@ -202,7 +359,7 @@ func (c Connection) startKeepAliveLoop(session *ssh.Session) chan struct{} {
 	return terminate
 }
 
-func (c Connection) Username() string {
+func (c *Connection) Username() string {
 	return c.sshConfig.User
 }
 
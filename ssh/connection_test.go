@@ -2,9 +2,11 @@ package ssh_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"errors"
 	"io"
+	"io/ioutil"
 	"log"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
@@ -172,10 +174,39 @@ var _ = Describe("Connection", func() {
 			It("captures exit code", func() {
 				Expect(exitCode).To(BeZero())
 			})
-			It("closes the connection after executing the command", func() {
-				Eventually(instance1.Run("ps", "auxwww")).ShouldNot(ContainSubstring(user))
+			It("keeps the connection open until it is closed", func() {
+				Expect(instance1.Run("ps", "auxwww")).To(ContainSubstring(user))
+
+				Expect(conn.Close()).To(Succeed())
+				Eventually(func() string { return instance1.Run("ps", "auxwww") }).ShouldNot(ContainSubstring(user))
 			})
 			Context("running multiple commands", func() {
+				It("runs them over the same connection", func() {
+					firstConnection, _, _, err := conn.Run("echo $SSH_CONNECTION")
+					Expect(err).NotTo(HaveOccurred())
+					secondConnection, _, _, err := conn.Run("echo $SSH_CONNECTION")
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(secondConnection).To(Equal(firstConnection))
+				})
+
+				It("reconnects when the connection has been closed", func() {
+					firstConnection, _, _, err := conn.Run("echo $SSH_CONNECTION")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(conn.Close()).To(Succeed())
+
+					secondConnection, _, _, err := conn.Run("echo $SSH_CONNECTION")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(secondConnection).NotTo(Equal(firstConnection))
+				})
+
+				It("reconnects when the connection has been dropped by the server", func() {
+					conn.Run("sudo pkill -f 'sshd: test-user'")
+
+					_, _, exitCode, err := conn.Run("ls")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(exitCode).To(BeZero())
+				})
 
 				It("does not fail", func() {
 					_, _, _, runError1 := conn.Run("ls")
@@ -212,6 +243,55 @@ var _ = Describe("Connection", func() {
 				})
 			})
 		})
+
+		Describe("interrupting sessions", func() {
+			AfterEach(func() {
+				ssh.Sessions.Resume()
+			})
+
+			It("stops running commands, and those started before sessions are resumed", func() {
+				Expect(connErr).NotTo(HaveOccurred())
+
+				runErrors := make(chan error)
+				go func() {
+					_, _, _, err := conn.Run("sleep 60")
+					runErrors <- err
+				}()
+
+				Eventually(func() string { return instance1.Run("ps", "auxwww") }).Should(ContainSubstring("sleep 60"))
+				ssh.Sessions.Interrupt()
+
+				var err error
+				Eventually(runErrors, "10s").Should(Receive(&err))
+				Expect(err).To(MatchError(ContainSubstring("interrupted")))
+
+				_, _, _, err = conn.Run("ls")
+				Expect(err).To(MatchError(ContainSubstring("interrupted")))
+
+				ssh.Sessions.Resume()
+				_, _, exitCode, err := conn.Run("ls")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exitCode).To(BeZero())
+			})
+
+			It("kills scripts run as root on the instance", func() {
+				Expect(connErr).NotTo(HaveOccurred())
+
+				runErrors := make(chan error)
+				go func() {
+					_, err := conn.RunStreaming(context.Background(), "sudo sleep 61", ioutil.Discard, ioutil.Discard)
+					runErrors <- err
+				}()
+
+				Eventually(func() string { return instance1.Run("ps", "auxwww") }).Should(ContainSubstring("sleep 61"))
+				ssh.Sessions.Interrupt()
+
+				var err error
+				Eventually(runErrors, "10s").Should(Receive(&err))
+				Expect(err).To(MatchError(ContainSubstring("interrupted")))
+				Eventually(func() string { return instance1.Run("ps", "auxwww") }, "10s").ShouldNot(ContainSubstring("sleep 61"))
+			})
+		})
 	})
 
 	Context("connection failures", func() {
@@ -2,8 +2,10 @@
 package fakes
 
 import (
+	"context"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
 )
@@ -117,12 +119,15 @@ type FakeRemoteRunner struct {
 		result1 string
 		result2 error
 	}
-	RunScriptWithEnvStub        func(path string, env map[string]string, label string) (string, error)
+	RunScriptWithEnvStub        func(ctx context.Context, path string, env map[string]string, label string, timeout time.Duration, output io.Writer) (string, error)
 	runScriptWithEnvMutex       sync.RWMutex
 	runScriptWithEnvArgsForCall []struct {
-		path  string
-		env   map[string]string
-		label string
+		ctx     context.Context
+		path    string
+		env     map[string]string
+		label   string
+		timeout time.Duration
+		output  io.Writer
 	}
 	runScriptWithEnvReturns struct {
 		result1 string
@@ -156,6 +161,15 @@ type FakeRemoteRunner struct {
 		result1 bool
 		result2 error
 	}
+	CloseStub        func() error
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct{}
+	closeReturns     struct {
+		result1 error
+	}
+	closeReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -599,18 +613,21 @@ func (fake *FakeRemoteRunner) RunScriptReturnsOnCall(i int, result1 string, resu
 	}{result1, result2}
 }
 
-func (fake *FakeRemoteRunner) RunScriptWithEnv(path string, env map[string]string, label string) (string, error) {
+func (fake *FakeRemoteRunner) RunScriptWithEnv(ctx context.Context, path string, env map[string]string, label string, timeout time.Duration, output io.Writer) (string, error) {
 	fake.runScriptWithEnvMutex.Lock()
 	ret, specificReturn := fake.runScriptWithEnvReturnsOnCall[len(fake.runScriptWithEnvArgsForCall)]
 	fake.runScriptWithEnvArgsForCall = append(fake.runScriptWithEnvArgsForCall, struct {
-		path  string
-		env   map[string]string
-		label string
-	}{path, env, label})
-	fake.recordInvocation("RunScriptWithEnv", []interface{}{path, env, label})
+		ctx     context.Context
+		path    string
+		env     map[string]string
+		label   string
+		timeout time.Duration
+		output  io.Writer
+	}{ctx, path, env, label, timeout, output})
+	fake.recordInvocation("RunScriptWithEnv", []interface{}{ctx, path, env, label, timeout, output})
 	fake.runScriptWithEnvMutex.Unlock()
 	if fake.RunScriptWithEnvStub != nil {
-		return fake.RunScriptWithEnvStub(path, env, label)
+		return fake.RunScriptWithEnvStub(ctx, path, env, label, timeout, output)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -624,10 +641,10 @@ func (fake *FakeRemoteRunner) RunScriptWithEnvCallCount() int {
 	return len(fake.runScriptWithEnvArgsForCall)
 }
 
-func (fake *FakeRemoteRunner) RunScriptWithEnvArgsForCall(i int) (string, map[string]string, string) {
+func (fake *FakeRemoteRunner) RunScriptWithEnvArgsForCall(i int) (context.Context, string, map[string]string, string, time.Duration, io.Writer) {
 	fake.runScriptWithEnvMutex.RLock()
 	defer fake.runScriptWithEnvMutex.RUnlock()
-	return fake.runScriptWithEnvArgsForCall[i].path, fake.runScriptWithEnvArgsForCall[i].env, fake.runScriptWithEnvArgsForCall[i].label
+	return fake.runScriptWithEnvArgsForCall[i].ctx, fake.runScriptWithEnvArgsForCall[i].path, fake.runScriptWithEnvArgsForCall[i].env, fake.runScriptWithEnvArgsForCall[i].label, fake.runScriptWithEnvArgsForCall[i].timeout, fake.runScriptWithEnvArgsForCall[i].output
 }
 
 func (fake *FakeRemoteRunner) RunScriptWithEnvReturns(result1 string, result2 error) {
@@ -746,6 +763,46 @@ func (fake *FakeRemoteRunner) IsWindowsReturnsOnCall(i int, result1 bool, result
 	}{result1, result2}
 }
 
+func (fake *FakeRemoteRunner) Close() error {
+	fake.closeMutex.Lock()
+	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct{}{})
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if fake.CloseStub != nil {
+		return fake.CloseStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.closeReturns.result1
+}
+
+func (fake *FakeRemoteRunner) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *FakeRemoteRunner) CloseReturns(result1 error) {
+	fake.CloseStub = nil
+	fake.closeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRemoteRunner) CloseReturnsOnCall(i int, result1 error) {
+	fake.CloseStub = nil
+	if fake.closeReturnsOnCall == nil {
+		fake.closeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.closeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRemoteRunner) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -773,6 +830,8 @@ func (fake *FakeRemoteRunner) Invocations() map[string][][]interface{} {
 	defer fake.findFilesMutex.RUnlock()
 	fake.isWindowsMutex.RLock()
 	defer fake.isWindowsMutex.RUnlock()
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"io"
 	"sync"
 
@@ -60,6 +61,22 @@ type FakeSSHConnection struct {
 		result3 int
 		result4 error
 	}
+	RunStreamingStub        func(ctx context.Context, cmd string, stdout, stderr io.Writer) (int, error)
+	runStreamingMutex       sync.RWMutex
+	runStreamingArgsForCall []struct {
+		ctx    context.Context
+		cmd    string
+		stdout io.Writer
+		stderr io.Writer
+	}
+	runStreamingReturns struct {
+		result1 int
+		result2 error
+	}
+	runStreamingReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
 	UsernameStub        func() string
 	usernameMutex       sync.RWMutex
 	usernameArgsForCall []struct{}
@@ -69,6 +86,15 @@ type FakeSSHConnection struct {
 	usernameReturnsOnCall map[int]struct {
 		result1 string
 	}
+	CloseStub        func() error
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct{}
+	closeReturns     struct {
+		result1 error
+	}
+	closeReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -243,6 +269,60 @@ func (fake *FakeSSHConnection) RunReturnsOnCall(i int, result1 []byte, result2 [
 	}{result1, result2, result3, result4}
 }
 
+func (fake *FakeSSHConnection) RunStreaming(ctx context.Context, cmd string, stdout io.Writer, stderr io.Writer) (int, error) {
+	fake.runStreamingMutex.Lock()
+	ret, specificReturn := fake.runStreamingReturnsOnCall[len(fake.runStreamingArgsForCall)]
+	fake.runStreamingArgsForCall = append(fake.runStreamingArgsForCall, struct {
+		ctx    context.Context
+		cmd    string
+		stdout io.Writer
+		stderr io.Writer
+	}{ctx, cmd, stdout, stderr})
+	fake.recordInvocation("RunStreaming", []interface{}{ctx, cmd, stdout, stderr})
+	fake.runStreamingMutex.Unlock()
+	if fake.RunStreamingStub != nil {
+		return fake.RunStreamingStub(ctx, cmd, stdout, stderr)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.runStreamingReturns.result1, fake.runStreamingReturns.result2
+}
+
+func (fake *FakeSSHConnection) RunStreamingCallCount() int {
+	fake.runStreamingMutex.RLock()
+	defer fake.runStreamingMutex.RUnlock()
+	return len(fake.runStreamingArgsForCall)
+}
+
+func (fake *FakeSSHConnection) RunStreamingArgsForCall(i int) (context.Context, string, io.Writer, io.Writer) {
+	fake.runStreamingMutex.RLock()
+	defer fake.runStreamingMutex.RUnlock()
+	return fake.runStreamingArgsForCall[i].ctx, fake.runStreamingArgsForCall[i].cmd, fake.runStreamingArgsForCall[i].stdout, fake.runStreamingArgsForCall[i].stderr
+}
+
+func (fake *FakeSSHConnection) RunStreamingReturns(result1 int, result2 error) {
+	fake.RunStreamingStub = nil
+	fake.runStreamingReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSSHConnection) RunStreamingReturnsOnCall(i int, result1 int, result2 error) {
+	fake.RunStreamingStub = nil
+	if fake.runStreamingReturnsOnCall == nil {
+		fake.runStreamingReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.runStreamingReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSSHConnection) Username() string {
 	fake.usernameMutex.Lock()
 	ret, specificReturn := fake.usernameReturnsOnCall[len(fake.usernameArgsForCall)]
@@ -283,6 +363,46 @@ func (fake *FakeSSHConnection) UsernameReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
+func (fake *FakeSSHConnection) Close() error {
+	fake.closeMutex.Lock()
+	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct{}{})
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if fake.CloseStub != nil {
+		return fake.CloseStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.closeReturns.result1
+}
+
+func (fake *FakeSSHConnection) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *FakeSSHConnection) CloseReturns(result1 error) {
+	fake.CloseStub = nil
+	fake.closeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSSHConnection) CloseReturnsOnCall(i int, result1 error) {
+	fake.CloseStub = nil
+	if fake.closeReturnsOnCall == nil {
+		fake.closeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.closeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeSSHConnection) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -292,8 +412,12 @@ func (fake *FakeSSHConnection) Invocations() map[string][][]interface{} {
 	defer fake.streamStdinMutex.RUnlock()
 	fake.runMutex.RLock()
 	defer fake.runMutex.RUnlock()
+	fake.runStreamingMutex.RLock()
+	defer fake.runStreamingMutex.RUnlock()
 	fake.usernameMutex.RLock()
 	defer fake.usernameMutex.RUnlock()
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,106 @@
+package ssh
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// ErrInterrupted is the cause of the errors of commands which were interrupted by Sessions
+var ErrInterrupted = errors.New("command was interrupted because bbr is aborting")
+
+// Sessions tracks the commands running on instances, so that they can be interrupted when
+// bbr is aborted.
+var Sessions = &SessionInterrupter{sessions: map[*ssh.Session]*runningCommand{}}
+
+type SessionInterrupter struct {
+	mux          sync.Mutex
+	sessions     map[*ssh.Session]*runningCommand
+	interrupting bool
+}
+
+// runningCommand is a command running in a session. Commands run as root outlive their
+// session, so those which can be stopped on the instance have a kill func to stop them.
+type runningCommand struct {
+	interrupted bool
+	kill        func()
+}
+
+// Interrupt stops the commands which are running, and any which are started until Resume
+// is called. Commands which can be are killed on the instance, and once they have been,
+// their sessions are sent SIGTERM, then closed.
+func (s *SessionInterrupter) Interrupt() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.interrupting = true
+
+	var interrupting sync.WaitGroup
+	for session, command := range s.sessions {
+		if !command.interrupted {
+			command.interrupted = true
+			interrupting.Add(1)
+			go func(session *ssh.Session, kill func()) {
+				defer interrupting.Done()
+				interruptSession(session, kill)
+			}(session, command.kill)
+		}
+	}
+	interrupting.Wait()
+}
+
+// Resume lets commands run again, such as those which unlock jobs and clean up
+func (s *SessionInterrupter) Resume() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.interrupting = false
+}
+
+func (s *SessionInterrupter) start(session *ssh.Session, kill func()) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.interrupting {
+		return ErrInterrupted
+	}
+	s.sessions[session] = &runningCommand{kill: kill}
+	return nil
+}
+
+func (s *SessionInterrupter) finish(session *ssh.Session) (interrupted bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	interrupted = s.sessions[session].interrupted
+	delete(s.sessions, session)
+	return interrupted
+}
+
+// interruptWhenDone interrupts the session if ctx is done before the returned func is called
+func interruptWhenDone(ctx context.Context, session *ssh.Session, kill func()) func() {
+	finished := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			interruptSession(session, kill)
+		case <-finished:
+		}
+	}()
+
+	return func() { close(finished) }
+}
+
+func interruptSession(session *ssh.Session, kill func()) {
+	if kill != nil {
+		kill()
+	}
+	session.Signal(ssh.SIGTERM)
+	session.Close()
+}
+
+func interrupted(err error) bool {
+	return errors.Cause(err) == ErrInterrupted
+}
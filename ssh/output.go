@@ -0,0 +1,54 @@
+package ssh
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// lineLogger logs each line written to it as soon as the line is complete
+type lineLogger struct {
+	logger  Logger
+	prefix  string
+	partial []byte
+}
+
+func newLineLogger(logger Logger, prefix string) *lineLogger {
+	return &lineLogger{logger: logger, prefix: prefix}
+}
+
+func (l *lineLogger) Write(p []byte) (int, error) {
+	l.partial = append(l.partial, p...)
+
+	for {
+		end := bytes.IndexByte(l.partial, '\n')
+		if end == -1 {
+			break
+		}
+		l.logger.Info("bbr", "%s%s", l.prefix, l.partial[:end])
+		l.partial = l.partial[end+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush logs the last line, if it did not end in a newline
+func (l *lineLogger) Flush() {
+	if len(l.partial) > 0 {
+		l.logger.Info("bbr", "%s%s", l.prefix, l.partial)
+		l.partial = nil
+	}
+}
+
+// lockedWriter lets stdout and stderr, which are copied concurrently, share a writer
+type lockedWriter struct {
+	mux    sync.Mutex
+	writer io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	return w.writer.Write(p)
+}
@@ -1,15 +1,27 @@
 package ssh
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math"
+	"sort"
 	"strings"
+	"time"
 
 	"golang.org/x/crypto/ssh"
 
 	"github.com/pkg/errors"
 )
 
+const (
+	scriptKillGracePeriod = 10 * time.Second
+	timedOutExitCode      = 124
+	killedExitCode        = 128 + 9
+)
+
 //go:generate counterfeiter -o fakes/fake_remote_runner.go . RemoteRunner
 type RemoteRunner interface {
 	ConnectedUsername() string
@@ -21,26 +33,41 @@ type RemoteRunner interface {
 	SizeOf(path string) (string, error)
 	ChecksumDirectory(path string) (map[string]string, error)
 	RunScript(path, label string) (string, error)
-	RunScriptWithEnv(path string, env map[string]string, label string) (string, error)
+	RunScriptWithEnv(ctx context.Context, path string, env map[string]string, label string, timeout time.Duration, output io.Writer) (string, error)
 	FindFiles(pattern string) ([]string, error)
 	IsWindows() (bool, error)
+	Close() error
 }
 
 type SshRemoteRunner struct {
-	logger     Logger
-	connection SSHConnection
+	logger      Logger
+	connection  SSHConnection
+	retryPolicy RetryPolicy
+	sleep       func(time.Duration)
 }
 
 func NewSshRemoteRunner(host, user, privateKey string, publicKeyCallback ssh.HostKeyCallback, publicKeyAlgorithm []string, logger Logger) (RemoteRunner, error) {
-	connection, err := NewConnection(host, user, privateKey, publicKeyCallback, publicKeyAlgorithm, logger)
-	if err != nil {
-		return SshRemoteRunner{}, err
+	return NewSshRemoteRunnerFactory(DefaultRetryPolicy)(host, user, privateKey, publicKeyCallback, publicKeyAlgorithm, logger)
+}
+
+func NewSshRemoteRunnerFactory(retryPolicy RetryPolicy) RemoteRunnerFactory {
+	return func(host, user, privateKey string, publicKeyCallback ssh.HostKeyCallback, publicKeyAlgorithm []string, logger Logger) (RemoteRunner, error) {
+		connection, err := NewConnection(host, user, privateKey, publicKeyCallback, publicKeyAlgorithm, logger)
+		if err != nil {
+			return SshRemoteRunner{}, err
+		}
+
+		return NewSshRemoteRunnerWithConnection(connection, retryPolicy, time.Sleep, logger), nil
 	}
+}
 
+func NewSshRemoteRunnerWithConnection(connection SSHConnection, retryPolicy RetryPolicy, sleep func(time.Duration), logger Logger) SshRemoteRunner {
 	return SshRemoteRunner{
-		connection: connection,
-		logger:     logger,
-	}, nil
+		connection:  connection,
+		logger:      logger,
+		retryPolicy: retryPolicy,
+		sleep:       sleep,
+	}
 }
 
 func (r SshRemoteRunner) ConnectedUsername() string {
@@ -48,17 +75,17 @@ func (r SshRemoteRunner) ConnectedUsername() string {
 }
 
 func (r SshRemoteRunner) DirectoryExists(dir string) (bool, error) {
-	_, _, exitCode, err := r.connection.Run(fmt.Sprintf("sudo stat %s", dir))
+	_, _, exitCode, err := r.runWithRetries(OperationDirectoryExists, fmt.Sprintf("sudo stat %s", dir))
 	return exitCode == 0, err
 }
 
 func (r SshRemoteRunner) CreateDirectory(directory string) error {
-	_, err := r.runOnInstance("sudo mkdir -p " + directory)
+	_, err := r.runOnInstance(OperationCreateDirectory, "sudo mkdir -p "+directory)
 	return err
 }
 
 func (r SshRemoteRunner) RemoveDirectory(dir string) error {
-	_, err := r.runOnInstance(fmt.Sprintf("sudo rm -rf %s", dir))
+	_, err := r.runOnInstance(OperationRemoveDirectory, fmt.Sprintf("sudo rm -rf %s", dir))
 	return err
 }
 
@@ -73,7 +100,7 @@ func (r SshRemoteRunner) ExtractAndUpload(reader io.Reader, directory string) er
 }
 
 func (r SshRemoteRunner) SizeOf(path string) (string, error) {
-	stdout, err := r.runOnInstance(fmt.Sprintf("sudo du -sh %s", path))
+	stdout, err := r.runOnInstance(OperationSizeOf, fmt.Sprintf("sudo du -sh %s", path))
 	if err != nil {
 		return "", err
 	}
@@ -82,7 +109,7 @@ func (r SshRemoteRunner) SizeOf(path string) (string, error) {
 }
 
 func (r SshRemoteRunner) ChecksumDirectory(path string) (map[string]string, error) {
-	stdout, err := r.runOnInstance(fmt.Sprintf("sudo sh -c 'cd %s && find . -type f | xargs shasum -a 256'", path))
+	stdout, err := r.runOnInstance(OperationChecksumDirectory, fmt.Sprintf("sudo sh -c 'cd %s && find . -type f | xargs shasum -a 256'", path))
 	if err != nil {
 		return nil, err
 	}
@@ -91,20 +118,67 @@ func (r SshRemoteRunner) ChecksumDirectory(path string) (map[string]string, erro
 }
 
 func (r SshRemoteRunner) RunScript(path, label string) (string, error) {
-	return r.RunScriptWithEnv(path, map[string]string{}, label)
+	stdout, stderr, exitCode, err := r.connection.Run(scriptCommand(path, nil, 0))
+	r.logOutput(stdout, stderr, label)
+
+	return scriptResult(stdout, stderr, exitCode, err, label, 0, 0)
 }
 
-func (r SshRemoteRunner) RunScriptWithEnv(path string, env map[string]string, label string) (string, error) {
-	var varsList = ""
-	for varName, value := range env {
-		varsList = varsList + varName + "=" + value + " "
+// RunScriptWithEnv runs a script as root, logging its output line by line as it runs and
+// copying it to output, if given. A script which runs for longer than a non-zero timeout
+// is terminated on the instance, and killed if it has not exited shortly after. The script
+// is also interrupted if ctx is done before it exits.
+func (r SshRemoteRunner) RunScriptWithEnv(ctx context.Context, path string, env map[string]string, label string, timeout time.Duration, output io.Writer) (string, error) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	stdoutLog := newLineLogger(r.logger, fmt.Sprintf("[%s] stdout: ", label))
+	stderrLog := newLineLogger(r.logger, fmt.Sprintf("[%s] stderr: ", label))
+
+	var combinedOutput io.Writer = ioutil.Discard
+	if output != nil {
+		combinedOutput = &lockedWriter{writer: output}
+	}
+
+	start := time.Now()
+	exitCode, err := r.connection.RunStreaming(
+		ctx,
+		scriptCommand(path, env, timeout),
+		io.MultiWriter(stdout, stdoutLog, combinedOutput),
+		io.MultiWriter(stderr, stderrLog, combinedOutput),
+	)
+	stdoutLog.Flush()
+	stderrLog.Flush()
+
+	return scriptResult(stdout.Bytes(), stderr.Bytes(), exitCode, err, label, timeout, time.Since(start))
+}
+
+func scriptCommand(path string, env map[string]string, timeout time.Duration) string {
+	if timeout <= 0 {
+		return "sudo " + environmentAssignments(env) + path
+	}
+
+	return fmt.Sprintf("sudo %stimeout --kill-after=%ds %ds %s", environmentAssignments(env), int(scriptKillGracePeriod.Seconds()), int(math.Ceil(timeout.Seconds())), path)
+}
+
+// scriptResult only reports a timeout once the script has run for as long as its timeout, as
+// a script may exit with the codes used by timeout for reasons of its own.
+func scriptResult(stdout, stderr []byte, exitCode int, err error, label string, timeout, elapsed time.Duration) (string, error) {
+	if err != nil {
+		return "", err
+	}
+
+	if timeout > 0 && elapsed >= timeout && (exitCode == timedOutExitCode || exitCode == killedExitCode) {
+		return "", ScriptTimeoutError{Label: label, After: timeout}
 	}
 
-	return r.runOnInstanceWithLabel("sudo "+varsList+path, label)
+	if exitCode != 0 {
+		return "", exitError(stderr, exitCode)
+	}
+
+	return string(stdout), nil
 }
 
 func (r SshRemoteRunner) FindFiles(pattern string) ([]string, error) {
-	stdout, stderr, exitCode, err := r.connection.Run(fmt.Sprintf("sudo sh -c 'find %s -type f'", pattern))
+	stdout, stderr, exitCode, err := r.runWithRetries(OperationFindFiles, fmt.Sprintf("sudo sh -c 'find %s -type f'", pattern))
 
 	r.logOutput(stdout, stderr, "find files")
 
@@ -126,7 +200,7 @@ func (r SshRemoteRunner) FindFiles(pattern string) ([]string, error) {
 }
 
 func (r SshRemoteRunner) IsWindows() (bool, error) {
-	stdout, _, _, err := r.connection.Run(`echo %OS%`)
+	stdout, _, _, err := r.runWithRetries(OperationIsWindows, `echo %OS%`)
 	if err != nil {
 		return false, err
 	}
@@ -134,14 +208,14 @@ func (r SshRemoteRunner) IsWindows() (bool, error) {
 	return strings.TrimSpace(string(stdout)) == "Windows_NT", nil
 }
 
-func (r SshRemoteRunner) runOnInstance(cmd string) (string, error) {
-	return r.runOnInstanceWithLabel(cmd, "")
+func (r SshRemoteRunner) Close() error {
+	return r.connection.Close()
 }
 
-func (r SshRemoteRunner) runOnInstanceWithLabel(cmd, label string) (string, error) {
-	stdout, stderr, exitCode, runErr := r.connection.Run(cmd)
+func (r SshRemoteRunner) runOnInstance(operation Operation, cmd string) (string, error) {
+	stdout, stderr, exitCode, runErr := r.runWithRetries(operation, cmd)
 
-	err := r.logAndCheckErrors(stdout, stderr, exitCode, runErr, label)
+	err := r.logAndCheckErrors(stdout, stderr, exitCode, runErr, "")
 	if err != nil {
 		return "", err
 	}
@@ -149,6 +223,22 @@ func (r SshRemoteRunner) runOnInstanceWithLabel(cmd, label string) (string, erro
 	return string(stdout), nil
 }
 
+// runWithRetries runs cmd, retrying it if the connection fails and the retry policy allows the
+// operation to be retried
+func (r SshRemoteRunner) runWithRetries(operation Operation, cmd string) (stdout, stderr []byte, exitCode int, err error) {
+	maxAttempts := r.retryPolicy.maxAttempts(operation)
+	for attempt := 1; ; attempt++ {
+		stdout, stderr, exitCode, err = r.connection.Run(cmd)
+		if err == nil || interrupted(err) || attempt >= maxAttempts {
+			return stdout, stderr, exitCode, err
+		}
+
+		backoff := r.retryPolicy.backoff(attempt)
+		r.logger.Warn("bbr", "Attempt %d of %d to run '%s' failed, retrying in %s: %s", attempt, maxAttempts, cmd, backoff, err)
+		r.sleep(backoff)
+	}
+}
+
 func (r SshRemoteRunner) logAndCheckErrors(stdout, stderr []byte, exitCode int, err error, label string) error {
 	r.logOutput(stdout, stderr, label)
 
@@ -174,6 +264,43 @@ func (r SshRemoteRunner) logOutput(stdout []byte, stderr []byte, label string) {
 	}
 }
 
+// ScriptTimeoutError is returned when a script runs for longer than its timeout
+type ScriptTimeoutError struct {
+	Label string
+	After time.Duration
+}
+
+func (e ScriptTimeoutError) Error() string {
+	if e.Label == "" {
+		return fmt.Sprintf("script timed out after %s", e.After)
+	}
+	return fmt.Sprintf("%s timed out after %s", e.Label, e.After)
+}
+
+func (e ScriptTimeoutError) Timeout() bool {
+	return true
+}
+
+// environmentAssignments quotes each value, so that values with spaces or shell
+// metacharacters reach the script unchanged
+func environmentAssignments(env map[string]string) string {
+	var names []string
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var assignments string
+	for _, name := range names {
+		assignments += name + "=" + shellQuote(env[name]) + " "
+	}
+	return assignments
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
+
 func exitError(stderr []byte, exitCode int) error {
 	return errors.New(fmt.Sprintf("%s - exit code %d", strings.TrimSpace(string(stderr)), exitCode))
 }
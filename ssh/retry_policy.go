@@ -0,0 +1,65 @@
+package ssh
+
+import "time"
+
+// Operation names a remote command which SshRemoteRunner can retry. Only commands which are
+// safe to run more than once, because they read state or converge on it, have an Operation.
+type Operation string
+
+const (
+	OperationDirectoryExists   Operation = "directory-exists"
+	OperationCreateDirectory   Operation = "create-directory"
+	OperationRemoveDirectory   Operation = "remove-directory"
+	OperationSizeOf            Operation = "size-of"
+	OperationChecksumDirectory Operation = "checksum-directory"
+	OperationFindFiles         Operation = "find-files"
+	OperationIsWindows         Operation = "is-windows"
+)
+
+// IdempotentOperations are all the operations which are safe to retry
+var IdempotentOperations = []Operation{
+	OperationDirectoryExists,
+	OperationCreateDirectory,
+	OperationRemoveDirectory,
+	OperationSizeOf,
+	OperationChecksumDirectory,
+	OperationFindFiles,
+	OperationIsWindows,
+}
+
+// RetryPolicy controls how often the given operations are retried after the SSH connection
+// fails. Any other remote command is attempted once, as are commands which ran and returned
+// a non-zero exit code.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Operations     []Operation
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Second,
+	MaxBackoff:     30 * time.Second,
+	Operations:     IdempotentOperations,
+}
+
+func (p RetryPolicy) maxAttempts(operation Operation) int {
+	for _, retried := range p.Operations {
+		if retried == operation {
+			return p.MaxAttempts
+		}
+	}
+	return 1
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		backoff = backoff * 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,129 @@
+package ssh_test
+
+import (
+	"bytes"
+	"log"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh/fakes"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("SshRemoteRunner retries", func() {
+	var connection *fakes.FakeSSHConnection
+	var remoteRunner ssh.SshRemoteRunner
+	var sleeps []time.Duration
+	var logOutput *bytes.Buffer
+	var retryPolicy ssh.RetryPolicy
+
+	BeforeEach(func() {
+		connection = new(fakes.FakeSSHConnection)
+		sleeps = nil
+		logOutput = new(bytes.Buffer)
+		retryPolicy = ssh.RetryPolicy{
+			MaxAttempts:    4,
+			InitialBackoff: time.Second,
+			MaxBackoff:     3 * time.Second,
+			Operations:     ssh.IdempotentOperations,
+		}
+	})
+
+	JustBeforeEach(func() {
+		logger := boshlog.New(boshlog.LevelDebug, log.New(logOutput, "", 0))
+		remoteRunner = ssh.NewSshRemoteRunnerWithConnection(connection, retryPolicy, func(d time.Duration) { sleeps = append(sleeps, d) }, logger)
+	})
+
+	Context("when an idempotent command fails to connect and then succeeds", func() {
+		BeforeEach(func() {
+			connection.RunReturnsOnCall(0, nil, nil, 0, errors.New("connection reset"))
+			connection.RunReturnsOnCall(1, nil, nil, 0, errors.New("connection reset"))
+			connection.RunReturnsOnCall(2, []byte("1234 /var/vcap/store/bbr-backup\n"), nil, 0, nil)
+		})
+
+		It("retries with exponential backoff and logs each retry", func() {
+			size, err := remoteRunner.SizeOf("/var/vcap/store/bbr-backup")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(size).To(Equal("1234"))
+			Expect(connection.RunCallCount()).To(Equal(3))
+			Expect(sleeps).To(Equal([]time.Duration{time.Second, 2 * time.Second}))
+			Expect(logOutput.String()).To(ContainSubstring("Attempt 1 of 4"))
+			Expect(logOutput.String()).To(ContainSubstring("Attempt 2 of 4"))
+		})
+	})
+
+	Context("when an idempotent command keeps failing to connect", func() {
+		BeforeEach(func() {
+			connection.RunReturns(nil, nil, 0, errors.New("connection reset"))
+		})
+
+		It("gives up after the maximum number of attempts, capping the backoff", func() {
+			_, err := remoteRunner.DirectoryExists("/var/vcap/store/bbr-backup")
+
+			Expect(err).To(MatchError(ContainSubstring("connection reset")))
+			Expect(connection.RunCallCount()).To(Equal(4))
+			Expect(sleeps).To(Equal([]time.Duration{time.Second, 2 * time.Second, 3 * time.Second}))
+		})
+	})
+
+	Context("when an idempotent command is interrupted because bbr is aborting", func() {
+		BeforeEach(func() {
+			connection.RunReturns(nil, nil, -1, errors.Wrap(ssh.ErrInterrupted, "ssh.Run failed"))
+		})
+
+		It("does not retry", func() {
+			_, err := remoteRunner.DirectoryExists("/var/vcap/store/bbr-backup")
+
+			Expect(errors.Cause(err)).To(Equal(ssh.ErrInterrupted))
+			Expect(connection.RunCallCount()).To(Equal(1))
+			Expect(sleeps).To(BeEmpty())
+		})
+	})
+
+	Context("when an idempotent command runs but exits non-zero", func() {
+		BeforeEach(func() {
+			connection.RunReturns(nil, []byte("permission denied"), 1, nil)
+		})
+
+		It("does not retry", func() {
+			_, err := remoteRunner.ChecksumDirectory("/var/vcap/store/bbr-backup")
+
+			Expect(err).To(HaveOccurred())
+			Expect(connection.RunCallCount()).To(Equal(1))
+			Expect(sleeps).To(BeEmpty())
+		})
+	})
+
+	Context("when the retry policy does not include the operation", func() {
+		BeforeEach(func() {
+			retryPolicy.Operations = []ssh.Operation{ssh.OperationSizeOf}
+			connection.RunReturns(nil, nil, 0, errors.New("connection reset"))
+		})
+
+		It("does not retry", func() {
+			_, err := remoteRunner.DirectoryExists("/var/vcap/store/bbr-backup")
+
+			Expect(err).To(MatchError(ContainSubstring("connection reset")))
+			Expect(connection.RunCallCount()).To(Equal(1))
+			Expect(sleeps).To(BeEmpty())
+		})
+	})
+
+	Context("when running a script fails to connect", func() {
+		BeforeEach(func() {
+			connection.RunReturns(nil, nil, 0, errors.New("connection reset"))
+		})
+
+		It("does not retry", func() {
+			_, err := remoteRunner.RunScript("/var/vcap/jobs/redis/bin/bbr/backup", "backup")
+
+			Expect(err).To(MatchError(ContainSubstring("connection reset")))
+			Expect(connection.RunCallCount()).To(Equal(1))
+			Expect(sleeps).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,125 @@
+package ssh_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh/fakes"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SshRemoteRunner running scripts", func() {
+	var connection *fakes.FakeSSHConnection
+	var remoteRunner ssh.SshRemoteRunner
+	var logOutput *bytes.Buffer
+
+	BeforeEach(func() {
+		connection = new(fakes.FakeSSHConnection)
+		logOutput = new(bytes.Buffer)
+		logger := boshlog.New(boshlog.LevelInfo, log.New(logOutput, "", 0))
+		remoteRunner = ssh.NewSshRemoteRunnerWithConnection(connection, ssh.DefaultRetryPolicy, time.Sleep, logger)
+	})
+
+	It("runs scripts without a timeout directly", func() {
+		remoteRunner.RunScript("/var/vcap/jobs/redis/bin/bbr/backup", "backup")
+
+		Expect(connection.RunArgsForCall(0)).To(Equal("sudo /var/vcap/jobs/redis/bin/bbr/backup"))
+	})
+
+	It("runs scripts with a timeout under timeout, killing them if they do not terminate", func() {
+		remoteRunner.RunScriptWithEnv(context.Background(), "/var/vcap/jobs/redis/bin/bbr/backup", map[string]string{"ENV": "value"}, "backup", 90*time.Second, nil)
+
+		_, cmd, _, _ := connection.RunStreamingArgsForCall(0)
+		Expect(cmd).To(Equal("sudo ENV='value' timeout --kill-after=10s 90s /var/vcap/jobs/redis/bin/bbr/backup"))
+	})
+
+	It("quotes environment variables, in a stable order", func() {
+		remoteRunner.RunScriptWithEnv(context.Background(), "/var/vcap/jobs/redis/bin/bbr/backup", map[string]string{
+			"B": "it's $HOME; rm -rf /",
+			"A": "two words",
+		}, "backup", 0, nil)
+
+		_, cmd, _, _ := connection.RunStreamingArgsForCall(0)
+		Expect(cmd).To(Equal(`sudo A='two words' B='it'\''s $HOME; rm -rf /' /var/vcap/jobs/redis/bin/bbr/backup`))
+	})
+
+	Context("when a script writes output", func() {
+		BeforeEach(func() {
+			connection.RunStreamingStub = func(ctx context.Context, cmd string, stdout, stderr io.Writer) (int, error) {
+				stdout.Write([]byte("dumping table one\ndumping "))
+				stderr.Write([]byte("warning: table two is large\n"))
+				stdout.Write([]byte("table two\ndone"))
+				return 0, nil
+			}
+		})
+
+		It("logs each line, tagged with the label", func() {
+			remoteRunner.RunScriptWithEnv(context.Background(), "/var/vcap/jobs/redis/bin/bbr/backup", nil, "backup redis on redis/0", 0, nil)
+
+			Expect(logOutput.String()).To(SatisfyAll(
+				ContainSubstring("[backup redis on redis/0] stdout: dumping table one\n"),
+				ContainSubstring("[backup redis on redis/0] stderr: warning: table two is large\n"),
+				ContainSubstring("[backup redis on redis/0] stdout: dumping table two\n"),
+				ContainSubstring("[backup redis on redis/0] stdout: done\n"),
+			))
+		})
+
+		It("copies stdout and stderr to the output and returns stdout", func() {
+			output := new(bytes.Buffer)
+			stdout, err := remoteRunner.RunScriptWithEnv(context.Background(), "/var/vcap/jobs/redis/bin/bbr/backup", nil, "backup", 0, output)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout).To(Equal("dumping table one\ndumping table two\ndone"))
+			Expect(output.String()).To(Equal("dumping table one\ndumping warning: table two is large\ntable two\ndone"))
+		})
+	})
+
+	Context("when the script runs for longer than its timeout", func() {
+		BeforeEach(func() {
+			connection.RunStreamingStub = func(ctx context.Context, cmd string, stdout, stderr io.Writer) (int, error) {
+				time.Sleep(20 * time.Millisecond)
+				return 124, nil
+			}
+		})
+
+		It("returns a timeout error", func() {
+			_, err := remoteRunner.RunScriptWithEnv(context.Background(), "/var/vcap/jobs/redis/bin/bbr/backup", nil, "backup redis on redis/0", 10*time.Millisecond, nil)
+
+			Expect(err).To(Equal(ssh.ScriptTimeoutError{Label: "backup redis on redis/0", After: 10 * time.Millisecond}))
+			Expect(err).To(MatchError("backup redis on redis/0 timed out after 10ms"))
+		})
+	})
+
+	Context("when the script exits with a timeout exit code before its timeout", func() {
+		BeforeEach(func() {
+			connection.RunStreamingStub = func(ctx context.Context, cmd string, stdout, stderr io.Writer) (int, error) {
+				stderr.Write([]byte("killed"))
+				return 137, nil
+			}
+		})
+
+		It("returns the usual error", func() {
+			_, err := remoteRunner.RunScriptWithEnv(context.Background(), "/var/vcap/jobs/redis/bin/bbr/backup", nil, "backup", time.Minute, nil)
+
+			Expect(err).To(MatchError("killed - exit code 137"))
+		})
+	})
+
+	Context("when a script without a timeout exits with the same code", func() {
+		BeforeEach(func() {
+			connection.RunReturns(nil, []byte("failed"), 124, nil)
+		})
+
+		It("returns the usual error", func() {
+			_, err := remoteRunner.RunScript("/var/vcap/jobs/redis/bin/bbr/backup", "backup")
+
+			Expect(err).To(MatchError("failed - exit code 124"))
+		})
+	})
+})
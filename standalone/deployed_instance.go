@@ -18,6 +18,8 @@ func NewDeployedInstance(instanceGroupName string, remoteRunner ssh.RemoteRunner
 }
 
 func (i DeployedInstance) Cleanup() error {
+	defer i.CloseConnection()
+
 	if !i.ArtifactDirCreated() {
 		i.Logger.Debug("bbr", "Backup directory was never created - skipping cleanup")
 		return nil
@@ -27,9 +29,20 @@ func (i DeployedInstance) Cleanup() error {
 }
 
 func (i DeployedInstance) CleanupPrevious() error {
+	defer i.CloseConnection()
+
 	return i.cleanupArtifact()
 }
 
+// SSHUser is empty, as bbr connects to the director as an existing user rather than setting one up
+func (i DeployedInstance) SSHUser() string {
+	return ""
+}
+
+func (i DeployedInstance) RemoveSSHUser(username string) error {
+	return nil
+}
+
 func (i DeployedInstance) cleanupArtifact() error {
 	i.Logger.Info("bbr", "Cleaning up...")
 
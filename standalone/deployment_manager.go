@@ -47,7 +47,7 @@ func (dm DeploymentManager) Find(deploymentName string) (orchestrator.Deployment
 		return nil, err
 	}
 
-	instanceIdentifier := instance.InstanceIdentifier{InstanceGroupName: "bosh", InstanceId: "0"}
+	instanceIdentifier := instance.InstanceIdentifier{DeploymentName: deploymentName, InstanceGroupName: "bosh", InstanceId: "0", InstanceIndex: "0"}
 
 	//TODO: change instanceIdentifier, its not always bosh
 	jobs, err := dm.jobFinder.FindJobs(instanceIdentifier, remoteRunner, instance.NewNoopReleaseMapping())
@@ -55,7 +55,7 @@ func (dm DeploymentManager) Find(deploymentName string) (orchestrator.Deployment
 		return nil, err
 	}
 
-	return orchestrator.NewDeployment(dm.Logger, []orchestrator.Instance{
+	return orchestrator.NewDeployment(deploymentName, dm.Logger, []orchestrator.Instance{
 		NewDeployedInstance("bosh", remoteRunner, dm.Logger, jobs, false),
 	}), nil
 }
@@ -72,7 +72,7 @@ var _ = Describe("DeploymentManager", func() {
 			})
 
 			It("returns a deployment", func() {
-				Expect(actualDeployment).To(Equal(orchestrator.NewDeployment(logger, []orchestrator.Instance{
+				Expect(actualDeployment).To(Equal(orchestrator.NewDeployment(deploymentName, logger, []orchestrator.Instance{
 					NewDeployedInstance("bosh", remoteRunner, logger, fakeJobs, false),
 				})))
 			})
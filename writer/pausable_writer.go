@@ -5,11 +5,14 @@ import (
 	"sync"
 )
 
+const clearLine = "\r\033[K"
+
 type PausableWriter struct {
 	out            io.Writer
 	mux            sync.Mutex
 	paused         bool
 	bufferedOutput []byte
+	statusLine     string
 }
 
 func NewPausableWriter(out io.Writer) *PausableWriter {
@@ -23,12 +26,34 @@ func (pw *PausableWriter) Write(p []byte) (int, error) {
 		pw.bufferedOutput = append(pw.bufferedOutput, p...)
 		return 0, nil
 	}
-	return pw.out.Write(p)
+
+	pw.clearStatusLine()
+	n, err := pw.out.Write(p)
+	pw.drawStatusLine()
+	return n, err
+}
+
+// SetStatusLine keeps a single line, such as a progress bar, at the bottom of the output.
+// Everything else written is printed above it. An empty line removes it.
+func (pw *PausableWriter) SetStatusLine(line string) {
+	pw.mux.Lock()
+	defer pw.mux.Unlock()
+
+	if !pw.paused {
+		pw.clearStatusLine()
+	}
+	pw.statusLine = line
+	if !pw.paused {
+		pw.drawStatusLine()
+	}
 }
 
 func (pw *PausableWriter) Pause() {
 	pw.mux.Lock()
 	defer pw.mux.Unlock()
+	if !pw.paused {
+		pw.clearStatusLine()
+	}
 	pw.paused = true
 }
 
@@ -39,5 +64,18 @@ func (pw *PausableWriter) Resume() (int, error) {
 	pw.paused = false
 	n, err := pw.out.Write(pw.bufferedOutput)
 	pw.bufferedOutput = []byte{}
+	pw.drawStatusLine()
 	return n, err
 }
+
+func (pw *PausableWriter) clearStatusLine() {
+	if pw.statusLine != "" {
+		io.WriteString(pw.out, clearLine)
+	}
+}
+
+func (pw *PausableWriter) drawStatusLine() {
+	if pw.statusLine != "" {
+		io.WriteString(pw.out, pw.statusLine)
+	}
+}
@@ -42,4 +42,32 @@ var _ = Describe("The Pausable Writer", func() {
 			Expect(string(buf.Contents())).To(Equal("not paused - paused - not paused"))
 		})
 	})
+
+	It("keeps the status line below everything else written", func() {
+		buf := gbytes.NewBuffer()
+		pw := NewPausableWriter(buf)
+
+		By("drawing the status line", func() {
+			pw.SetStatusLine("[===>   ]")
+			Expect(string(buf.Contents())).To(Equal("[===>   ]"))
+		})
+
+		By("redrawing it after other output", func() {
+			nb, err := pw.Write([]byte("log line\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nb).To(Equal(9))
+			Expect(string(buf.Contents())).To(Equal("[===>   ]\r\033[Klog line\n[===>   ]"))
+		})
+
+		By("clearing it while paused", func() {
+			pw.Pause()
+			pw.SetStatusLine("[=====> ]")
+			Expect(string(buf.Contents())).To(Equal("[===>   ]\r\033[Klog line\n[===>   ]\r\033[K"))
+		})
+
+		By("drawing the latest status line when resumed", func() {
+			pw.Resume()
+			Expect(string(buf.Contents())).To(Equal("[===>   ]\r\033[Klog line\n[===>   ]\r\033[K[=====> ]"))
+		})
+	})
 })